@@ -0,0 +1,39 @@
+package actions
+
+import (
+	"api/providers/jwt"
+	"net/http"
+
+	"github.com/go-flow/flow/v2"
+)
+
+type JWKSAction struct {
+	auth jwt.TokenAuth
+}
+
+func NewJWKSAction(auth jwt.TokenAuth) *JWKSAction {
+	return &JWKSAction{
+		auth: auth,
+	}
+}
+
+func (a *JWKSAction) Method() string {
+	return http.MethodGet
+}
+
+func (a *JWKSAction) Path() string {
+	return "/.well-known/jwks.json"
+}
+
+func (a *JWKSAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle returns public keys used to verify issued tokens
+// @Summary Returns JSON Web Key Set used to verify issued tokens
+// @Produce json
+// @Success 200 {object} jwt.JWKS
+// @Router /.well-known/jwks.json [get]
+func (a *JWKSAction) Handle(r *http.Request) flow.Response {
+	return flow.ResponseJSON(http.StatusOK, a.auth.JWKS())
+}
@@ -0,0 +1,78 @@
+package actions
+
+import (
+	"api/providers/config"
+	"net/http"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// OpenIDConfiguration holds OpenID Connect discovery document
+type OpenIDConfiguration struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	IntrospectionEndpoint             string   `json:"introspection_endpoint"`
+	RevocationEndpoint                string   `json:"revocation_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	UserinfoEndpoint                  string   `json:"userinfo_endpoint"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+}
+
+type OpenIDConfigurationAction struct {
+	config config.AppConfig
+}
+
+func NewOpenIDConfigurationAction(config config.AppConfig) *OpenIDConfigurationAction {
+	return &OpenIDConfigurationAction{
+		config: config,
+	}
+}
+
+func (a *OpenIDConfigurationAction) Method() string {
+	return http.MethodGet
+}
+
+func (a *OpenIDConfigurationAction) Path() string {
+	return "/.well-known/openid-configuration"
+}
+
+func (a *OpenIDConfigurationAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle returns OpenID Connect discovery document
+// @Summary Returns OpenID Connect discovery document
+// @Description Services validating issued tokens can discover signing keys and userinfo endpoint from it
+// @Produce json
+// @Success 200 {object} actions.OpenIDConfiguration
+// @Router /.well-known/openid-configuration [get]
+func (a *OpenIDConfigurationAction) Handle(r *http.Request) flow.Response {
+	issuer := a.config.JWTIssuer()
+
+	return flow.ResponseJSON(http.StatusOK, &OpenIDConfiguration{
+		Issuer:                            issuer,
+		AuthorizationEndpoint:             issuer + "/oauth/authorize",
+		TokenEndpoint:                     issuer + "/oauth/token",
+		IntrospectionEndpoint:             issuer + "/oauth/introspect",
+		RevocationEndpoint:                issuer + "/oauth/revoke",
+		JWKSURI:                           issuer + "/.well-known/jwks.json",
+		UserinfoEndpoint:                  issuer + "/oauth/userinfo",
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{"authorization_code", "refresh_token", "client_credentials"},
+		CodeChallengeMethodsSupported:     []string{"S256"},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{a.config.JWTAlgorithm()},
+		ClaimsSupported: []string{
+			"iss", "aud", "sub", "jti", "exp", "iat", "scope", "client_id",
+			"email", "email_verified", "name", "given_name", "family_name",
+		},
+	})
+}
@@ -1,15 +1,26 @@
 package api
 
 import (
+	"context"
+
 	"api/migrations"
 	"api/modules/account"
+	accountServices "api/modules/account/services"
+	"api/modules/invitations"
+	"api/modules/keys"
+	"api/modules/oauth"
+	"api/modules/roles"
+	roleServices "api/modules/roles/services"
 	"api/modules/users"
 	"api/providers/binding"
 	"api/providers/config"
 	"api/providers/db"
 	"api/providers/db/migrator"
 	"api/providers/jwt"
+	"api/providers/lockout"
 	"api/providers/log"
+	"api/providers/notify"
+	"api/providers/oidc"
 	"api/providers/vm"
 	"api/routers"
 
@@ -22,6 +33,12 @@ type AppModule struct {
 	Logger    log.Logger
 	Store     db.Store
 	Injector  flow.Injector
+
+	AccountService    accountServices.AccountService
+	RolesAdminService roleServices.RolesAdminService
+	TokenAuth         jwt.TokenAuth
+
+	stopJobs context.CancelFunc
 }
 
 // Start -
@@ -36,11 +53,20 @@ func (app *AppModule) Start() error {
 	}
 	app.Logger.Info("End application migrations.")
 
+	app.startJobs()
+
 	app.Logger.Infof("Application is running on: %s", app.Options().Addr)
 
 	return nil
 }
 
+// Stop -
+func (app *AppModule) Stop() {
+	if app.stopJobs != nil {
+		app.stopJobs()
+	}
+}
+
 func (app *AppModule) Options() flow.Options {
 	opts := flow.NewOptions()
 	opts.Name = "core-api"
@@ -61,6 +87,9 @@ func (app *AppModule) ProvideImports() []flow.Provider {
 		flow.NewProvider(binding.New),
 		flow.NewProvider(vm.NewJson),
 		flow.NewProvider(jwt.NewAuth),
+		flow.NewProvider(notify.New),
+		flow.NewProvider(lockout.New),
+		flow.NewProvider(oidc.New),
 	}
 }
 
@@ -72,6 +101,10 @@ func (app *AppModule) ProvideModules() []flow.Provider {
 	return []flow.Provider{
 		flow.NewProvider(account.NewModule),
 		flow.NewProvider(users.NewModule),
+		flow.NewProvider(invitations.NewModule),
+		flow.NewProvider(roles.NewModule),
+		flow.NewProvider(keys.NewModule),
+		flow.NewProvider(oauth.NewModule),
 	}
 }
 
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"time"
+)
+
+// job is background task executed periodically while application is running
+type job struct {
+	name     string
+	interval time.Duration
+	run      func(ctx context.Context) error
+}
+
+// jobs returns list of application background jobs
+func (app *AppModule) jobs() []job {
+	return []job{
+		{
+			name:     "purge deleted accounts",
+			interval: app.AppConfig.AccountPurgeInterval(),
+			run:      app.AccountService.PurgeDeletedAccounts,
+		},
+		{
+			name:     "sweep expired role assignments",
+			interval: app.AppConfig.RoleAssignmentSweepInterval(),
+			run:      app.RolesAdminService.SweepExpiredAssignments,
+		},
+		{
+			name:     "reload signing keys",
+			interval: app.AppConfig.JWTKeysReloadInterval(),
+			run: func(ctx context.Context) error {
+				return app.TokenAuth.ReloadKeys()
+			},
+		},
+	}
+}
+
+// startJobs starts all background jobs, jobs are stopped when application stops
+func (app *AppModule) startJobs() {
+	ctx, cancel := context.WithCancel(context.Background())
+	app.stopJobs = cancel
+
+	for _, j := range app.jobs() {
+		go app.runJob(ctx, j)
+	}
+}
+
+// runJob executes given job on every interval until context is cancelled
+func (app *AppModule) runJob(ctx context.Context, j job) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.run(ctx); err != nil {
+			app.Logger.Errorf("job `%s` failed. Error: %v", j.name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,2 @@
+ALTER TABLE `users`
+    ADD COLUMN `email_confirmed_at` TIMESTAMP NULL AFTER `email`;
@@ -0,0 +1 @@
+UPDATE `users` SET `email_confirmed_at` = CURRENT_TIMESTAMP WHERE `email_confirmed_at` IS NULL;
@@ -0,0 +1,15 @@
+CREATE TABLE `users_mfa`
+(
+    `user_id`        INT unsigned    NOT NULL,
+    `secret`         VARCHAR(64)     NOT NULL,
+    `last_used_step` BIGINT unsigned NOT NULL DEFAULT 0,
+    `enabled_at`     TIMESTAMP       NULL,
+    `created_at`     TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    `updated_at`     TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`user_id`),
+    CONSTRAINT `fk_users_mfa_user_id`
+        FOREIGN KEY (`user_id`)
+            REFERENCES `users` (`id`)
+            ON DELETE CASCADE
+            ON UPDATE CASCADE
+) ENGINE = InnoDB;
@@ -0,0 +1,15 @@
+CREATE TABLE `mfa_recovery_codes`
+(
+    `id`         INT unsigned NOT NULL AUTO_INCREMENT,
+    `user_id`    INT unsigned NOT NULL,
+    `code_hash`  VARCHAR(64)  NOT NULL,
+    `used_at`    TIMESTAMP    NULL,
+    `created_at` TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    INDEX `fk_mfa_recovery_codes_user_id_idx` (`user_id` ASC),
+    CONSTRAINT `fk_mfa_recovery_codes_user_id`
+        FOREIGN KEY (`user_id`)
+            REFERENCES `users` (`id`)
+            ON DELETE CASCADE
+            ON UPDATE CASCADE
+) ENGINE = InnoDB;
@@ -0,0 +1,10 @@
+CREATE TABLE `login_attempts`
+(
+    `attempt_key`     VARCHAR(255) NOT NULL,
+    `failures`        INT unsigned NOT NULL DEFAULT 0,
+    `locked_until`    TIMESTAMP    NULL,
+    `last_failure_at` TIMESTAMP    NULL,
+    `created_at`      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    `updated_at`      TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`attempt_key`)
+) ENGINE = InnoDB;
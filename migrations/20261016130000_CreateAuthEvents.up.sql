@@ -0,0 +1,22 @@
+CREATE TABLE `auth_events`
+(
+    `id`         BIGINT unsigned NOT NULL AUTO_INCREMENT,
+    `user_id`    INT unsigned    NULL,
+    `event`      VARCHAR(50)     NOT NULL,
+    `success`    TINYINT(1)      NOT NULL DEFAULT 0,
+    `client_ip`  VARCHAR(255)    NOT NULL DEFAULT '',
+    `user_agent` VARCHAR(255)    NOT NULL DEFAULT '',
+    `request_id` VARCHAR(255)    NOT NULL DEFAULT '',
+    `error_code` VARCHAR(50)     NOT NULL DEFAULT '',
+    `meta`       JSON,
+    `created_at` TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    INDEX `fk_auth_events_user_id_idx` (`user_id` ASC, `created_at` DESC),
+    INDEX `auth_events_event_idx` (`event` ASC),
+    INDEX `auth_events_client_ip_idx` (`client_ip` ASC),
+    CONSTRAINT `fk_auth_events_user_id`
+        FOREIGN KEY (`user_id`)
+            REFERENCES `users` (`id`)
+            ON DELETE CASCADE
+            ON UPDATE CASCADE
+) ENGINE = InnoDB;
@@ -0,0 +1,3 @@
+ALTER TABLE `users`
+    ADD COLUMN `anonymized_at` TIMESTAMP NULL AFTER `deleted_at`,
+    ADD INDEX `users_deleted_at_idx` (`deleted_at` ASC);
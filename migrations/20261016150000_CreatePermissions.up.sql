@@ -0,0 +1,10 @@
+CREATE TABLE `permissions`
+(
+    `id`          INT unsigned NOT NULL AUTO_INCREMENT,
+    `name`        VARCHAR(45)  NOT NULL,
+    `description` VARCHAR(255) NOT NULL DEFAULT '',
+    `created_at`  TIMESTAMP             DEFAULT CURRENT_TIMESTAMP,
+    `updated_at`  TIMESTAMP             DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `permissions_name_UNIQUE` (`name` ASC)
+) ENGINE = InnoDB;
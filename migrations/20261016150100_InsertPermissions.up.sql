@@ -0,0 +1,8 @@
+INSERT INTO `permissions` (`id`, `name`, `description`)
+VALUES 
+(1, 'users:read', 'List and view users'),
+(2, 'users:write', 'Update, delete, restore and unlock users'),
+(3, 'roles:read', 'List and view roles'),
+(4, 'roles:write', 'Create, update and delete roles and manage role assignments'),
+(5, 'invitations:read', 'List invitations'),
+(6, 'invitations:write', 'Invite users, resend and revoke invitations');
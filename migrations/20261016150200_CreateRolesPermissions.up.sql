@@ -0,0 +1,20 @@
+CREATE TABLE `roles_permissions`
+(
+    `role_id`       INT unsigned NOT NULL,
+    `permission_id` INT unsigned NOT NULL,
+    `created_at`    TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    `updated_at`    TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`role_id`, `permission_id`),
+    INDEX `fk_roles_permissions_permission_id_idx` (`permission_id` ASC),
+    INDEX `fk_roles_permissions_role_id_idx` (`role_id` ASC),
+    CONSTRAINT `fk_roles_permissions_role_id`
+        FOREIGN KEY (`role_id`)
+            REFERENCES `roles` (`id`)
+            ON DELETE CASCADE
+            ON UPDATE CASCADE,
+    CONSTRAINT `fk_roles_permissions_permission_id`
+        FOREIGN KEY (`permission_id`)
+            REFERENCES `permissions` (`id`)
+            ON DELETE CASCADE
+            ON UPDATE CASCADE
+) ENGINE = InnoDB;
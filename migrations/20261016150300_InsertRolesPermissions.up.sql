@@ -0,0 +1,2 @@
+INSERT INTO `roles_permissions` (`role_id`, `permission_id`)
+SELECT 1, `id` FROM `permissions`;
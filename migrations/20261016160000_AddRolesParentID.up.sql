@@ -0,0 +1,8 @@
+ALTER TABLE `roles`
+    ADD COLUMN `parent_id` INT unsigned NULL AFTER `description`,
+    ADD INDEX `fk_roles_parent_id_idx` (`parent_id` ASC),
+    ADD CONSTRAINT `fk_roles_parent_id`
+        FOREIGN KEY (`parent_id`)
+            REFERENCES `roles` (`id`)
+            ON DELETE SET NULL
+            ON UPDATE CASCADE;
@@ -0,0 +1 @@
+UPDATE `roles` SET `parent_id` = 2 WHERE `id` = 1;
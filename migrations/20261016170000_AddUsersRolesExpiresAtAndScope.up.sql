@@ -0,0 +1,6 @@
+ALTER TABLE `users_roles`
+    ADD COLUMN `resource_scope` VARCHAR(100) NOT NULL DEFAULT '' AFTER `role_id`,
+    ADD COLUMN `expires_at`     TIMESTAMP    NULL AFTER `resource_scope`,
+    DROP PRIMARY KEY,
+    ADD PRIMARY KEY (`user_id`, `role_id`, `resource_scope`),
+    ADD INDEX `users_roles_expires_at_idx` (`expires_at` ASC);
@@ -0,0 +1,3 @@
+INSERT INTO `permissions` (`id`, `name`, `description`)
+VALUES 
+(7, 'keys:write', 'Rotate token signing keys');
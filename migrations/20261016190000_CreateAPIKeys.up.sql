@@ -0,0 +1,20 @@
+CREATE TABLE `api_keys`
+(
+    `id`           INT unsigned  NOT NULL AUTO_INCREMENT,
+    `user_id`      INT unsigned  NOT NULL,
+    `name`         VARCHAR(100)  NOT NULL,
+    `prefix`       VARCHAR(16)   NOT NULL,
+    `key_hash`     VARCHAR(64)   NOT NULL,
+    `scopes`       VARCHAR(1000) NOT NULL,
+    `expires_at`   TIMESTAMP     NOT NULL,
+    `last_used_at` TIMESTAMP     NULL,
+    `created_at`   TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `api_keys_key_hash_idx` (`key_hash` ASC),
+    INDEX `fk_api_keys_user_id_idx` (`user_id` ASC),
+    CONSTRAINT `fk_api_keys_user_id`
+        FOREIGN KEY (`user_id`)
+            REFERENCES `users` (`id`)
+            ON DELETE CASCADE
+            ON UPDATE CASCADE
+) ENGINE = InnoDB;
@@ -0,0 +1,11 @@
+CREATE TABLE `oauth_clients`
+(
+    `id`          INT unsigned NOT NULL AUTO_INCREMENT,
+    `client_id`   VARCHAR(64)  NOT NULL,
+    `secret_hash` VARCHAR(64)  NOT NULL,
+    `name`        VARCHAR(100) NOT NULL,
+    `created_at`  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    `updated_at`  TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `oauth_clients_client_id_idx` (`client_id` ASC)
+) ENGINE = InnoDB;
@@ -0,0 +1,4 @@
+INSERT INTO `permissions` (`id`, `name`, `description`)
+VALUES 
+(8, 'clients:read', 'List OAuth clients'),
+(9, 'clients:write', 'Register and remove OAuth clients');
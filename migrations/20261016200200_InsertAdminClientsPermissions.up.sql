@@ -0,0 +1,3 @@
+INSERT INTO `roles_permissions` (`role_id`, `permission_id`)
+VALUES (1, 8),
+       (1, 9);
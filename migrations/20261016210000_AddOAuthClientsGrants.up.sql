@@ -0,0 +1,5 @@
+ALTER TABLE `oauth_clients`
+    ADD COLUMN `public`        TINYINT(1)    NOT NULL DEFAULT 0 AFTER `secret_hash`,
+    ADD COLUMN `redirect_uris` VARCHAR(2000) NOT NULL DEFAULT '' AFTER `name`,
+    ADD COLUMN `scopes`        VARCHAR(1000) NOT NULL DEFAULT '' AFTER `redirect_uris`,
+    ADD COLUMN `grant_types`   VARCHAR(255)  NOT NULL DEFAULT '' AFTER `scopes`;
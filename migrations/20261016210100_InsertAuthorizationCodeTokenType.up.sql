@@ -0,0 +1,2 @@
+INSERT INTO `token_types` (`id`, `name`)
+VALUES (6, 'OAuth Authorization Code');
@@ -0,0 +1,2 @@
+ALTER TABLE `auth_providers`
+    ADD UNIQUE INDEX `auth_providers_provider_uid_idx` (`provider` ASC, `uid` ASC);
@@ -0,0 +1,2 @@
+ALTER TABLE `auth_events`
+    DROP FOREIGN KEY `fk_auth_events_user_id`;
@@ -0,0 +1,8 @@
+CREATE TABLE `revoked_tokens`
+(
+    `jti`        VARCHAR(64) NOT NULL,
+    `expires_at` TIMESTAMP   NOT NULL,
+    `created_at` TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`jti`),
+    INDEX `revoked_tokens_expires_at_idx` (`expires_at` ASC)
+) ENGINE = InnoDB;
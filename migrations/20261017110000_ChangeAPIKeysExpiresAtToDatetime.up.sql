@@ -0,0 +1,2 @@
+ALTER TABLE `api_keys`
+    MODIFY `expires_at` DATETIME NOT NULL;
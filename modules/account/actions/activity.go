@@ -0,0 +1,63 @@
+package actions
+
+import (
+	"api/modules/account/services"
+	"api/pkg/paging"
+	"api/providers/jwt"
+	"api/providers/vm"
+	"net/http"
+
+	"github.com/go-flow/flow/v2"
+)
+
+type ActivityAction struct {
+	vm             vm.Transformer
+	auth           jwt.TokenAuth
+	accountService services.AccountService
+}
+
+func NewActivityAction(vm vm.Transformer, auth jwt.TokenAuth, accountService services.AccountService) *ActivityAction {
+	return &ActivityAction{
+		vm:             vm,
+		auth:           auth,
+		accountService: accountService,
+	}
+}
+
+func (a *ActivityAction) Method() string {
+	return http.MethodGet
+}
+
+func (a *ActivityAction) Path() string {
+	return "/activity"
+}
+
+func (a *ActivityAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle lists user activity
+// @Summary Lists authentication events (logins, refreshes, password changes...) of current user
+// @Produce json
+// @Tags account
+// @Security BearerAuth
+// @Param page query int false "Page"
+// @Param per_page query int false "Results per page"
+// @Success 200 {object} paging.Model{results=[]audit.Event}
+// @Failure 400 {object} vm.ResponseError
+// @Router /account/activity [get]
+func (a *ActivityAction) Handle(r *http.Request) flow.Response {
+	userID, err := a.auth.RequestUserID(r)
+	if err != nil {
+		return a.vm.Error(http.StatusUnauthorized, err)
+	}
+
+	paginator := paging.NewPaginatorFromParams(r.URL.Query())
+
+	events, err := a.accountService.GetActivity(r.Context(), userID, paginator)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, &paging.Model{Results: events, Paginator: paginator})
+}
@@ -0,0 +1,58 @@
+package actions
+
+import (
+	"api/modules/account/services"
+	"api/providers/jwt"
+	"api/providers/vm"
+	"net/http"
+
+	"github.com/go-flow/flow/v2"
+)
+
+type APIKeysAction struct {
+	vm             vm.Transformer
+	auth           jwt.TokenAuth
+	accountService services.AccountService
+}
+
+func NewAPIKeysAction(vm vm.Transformer, auth jwt.TokenAuth, accountService services.AccountService) *APIKeysAction {
+	return &APIKeysAction{
+		vm:             vm,
+		auth:           auth,
+		accountService: accountService,
+	}
+}
+
+func (a *APIKeysAction) Method() string {
+	return http.MethodGet
+}
+
+func (a *APIKeysAction) Path() string {
+	return "/api-keys"
+}
+
+func (a *APIKeysAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle lists user API keys
+// @Summary Lists API keys of current user
+// @Produce json
+// @Tags account
+// @Security BearerAuth
+// @Success 200 {array} apikeys.APIKey
+// @Failure 400 {object} vm.ResponseError
+// @Router /account/api-keys [get]
+func (a *APIKeysAction) Handle(r *http.Request) flow.Response {
+	userID, err := a.auth.RequestUserID(r)
+	if err != nil {
+		return a.vm.Error(http.StatusUnauthorized, err)
+	}
+
+	keys, err := a.accountService.GetAPIKeys(r.Context(), userID)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, keys)
+}
@@ -0,0 +1,87 @@
+package actions
+
+import (
+	"api/modules/account/services"
+	"api/pkg/apperror"
+	"api/pkg/userip"
+	"api/providers/config"
+	"api/providers/oidc"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+
+	"github.com/go-flow/flow/v2"
+)
+
+type CompleteExternalLoginAction struct {
+	vm             vm.Transformer
+	cfg            config.AppConfig
+	accountService services.AccountService
+}
+
+func NewCompleteExternalLoginAction(vm vm.Transformer, cfg config.AppConfig, accountService services.AccountService) *CompleteExternalLoginAction {
+	return &CompleteExternalLoginAction{
+		vm:             vm,
+		cfg:            cfg,
+		accountService: accountService,
+	}
+}
+
+func (a *CompleteExternalLoginAction) Method() string {
+	return http.MethodGet
+}
+
+func (a *CompleteExternalLoginAction) Path() string {
+	return "/oauth/:provider/callback"
+}
+
+func (a *CompleteExternalLoginAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle completes login with external identity provider
+// @Summary Completes login with external OpenID Connect identity provider and provides accesToken and refreshToken pair.
+// @Description Identity provider redirects user to this endpoint. User is registered when provider identity is not linked to any account.
+// @Description Users with MFA enabled receive mfaToken instead. Linking started by `/account/providers/link` returns empty data,
+// @Description as does registration when unconfirmed email can not login
+// @Produce json
+// @Tags account
+// @Param provider path string true "Identity provider name"
+// @Param code query string true "Authorization code"
+// @Param state query string true "Login state"
+// @Success 200 {object} models.Auth
+// @Failure 400 {object} vm.ResponseError
+// @Failure 502 {object} vm.ResponseError
+// @Router /account/oauth/{provider}/callback [get]
+func (a *CompleteExternalLoginAction) Handle(r *http.Request) flow.Response {
+	provider := flow.ParamsFromContext(r.Context()).ByName("provider")
+	q := r.URL.Query()
+
+	// state cookie is used only once
+	clearCookie := newExternalLoginCookie(a.cfg, provider, "")
+
+	if q.Get("error") != "" {
+		err := apperror.New("400", errors.New("identity provider rejected login"), errors.New(q.Get("error")))
+		return vm.WithCookies(a.vm.Error(http.StatusBadRequest, err), clearCookie)
+	}
+
+	var stateToken string
+	if cookie, err := r.Cookie(externalLoginCookie); err == nil {
+		stateToken = cookie.Value
+	}
+
+	if stateToken == "" || q.Get("code") == "" {
+		err := apperror.New("400", errors.New("validation error"), services.ErrExternalLoginState)
+		return vm.WithCookies(a.vm.Error(http.StatusBadRequest, err), clearCookie)
+	}
+
+	auth, err := a.accountService.CompleteExternalLogin(r.Context(), provider, stateToken, q.Get("state"), q.Get("code"), userip.Get(r), r.UserAgent())
+	if err != nil {
+		if errors.Is(err, oidc.ErrDiscovery) || errors.Is(err, oidc.ErrExchange) {
+			return vm.WithCookies(a.vm.Error(http.StatusBadGateway, err), clearCookie)
+		}
+		return vm.WithCookies(a.vm.Error(http.StatusBadRequest, err), clearCookie)
+	}
+
+	return vm.WithCookies(a.vm.Success(http.StatusOK, auth), clearCookie)
+}
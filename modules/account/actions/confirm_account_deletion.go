@@ -0,0 +1,76 @@
+package actions
+
+import (
+	"api/modules/account/services"
+	"api/pkg/apperror"
+	"api/pkg/userip"
+	"api/providers/binding"
+	"api/providers/jwt"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// ConfirmAccountDeletion request object
+type ConfirmAccountDeletion struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type ConfirmAccountDeletionAction struct {
+	vm             vm.Transformer
+	binder         binding.Binder
+	auth           jwt.TokenAuth
+	accountService services.AccountService
+}
+
+func NewConfirmAccountDeletionAction(vm vm.Transformer, binder binding.Binder, auth jwt.TokenAuth, accountService services.AccountService) *ConfirmAccountDeletionAction {
+	return &ConfirmAccountDeletionAction{
+		vm:             vm,
+		binder:         binder,
+		auth:           auth,
+		accountService: accountService,
+	}
+}
+
+func (a *ConfirmAccountDeletionAction) Method() string {
+	return http.MethodPost
+}
+
+func (a *ConfirmAccountDeletionAction) Path() string {
+	return "/delete/confirm"
+}
+
+func (a *ConfirmAccountDeletionAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle confirms account deletion
+// @Summary Deletes user account and terminates all user sessions
+// @Description Personal information is removed after grace period. Completing login, including MFA step, before grace period expires cancels deletion
+// @Produce json
+// @Tags account
+// @Security BearerAuth
+// @Param req body ConfirmAccountDeletion true "Confirm Account Deletion Request"
+// @Success 200 {object} vm.Response
+// @Failure 400 {object} vm.ResponseError
+// @Router /account/delete/confirm [post]
+func (a *ConfirmAccountDeletionAction) Handle(r *http.Request) flow.Response {
+	var reqObj ConfirmAccountDeletion
+	if err := a.binder.Bind(r, &reqObj); err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	userID, err := a.auth.RequestUserID(r)
+	if err != nil {
+		return a.vm.Error(http.StatusUnauthorized, err)
+	}
+
+	err = a.accountService.ConfirmAccountDeletion(r.Context(), userID, a.auth.RequestAccessToken(r), reqObj.Token, userip.Get(r), r.UserAgent())
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, nil)
+}
@@ -0,0 +1,64 @@
+package actions
+
+import (
+	"api/modules/account/services"
+	"api/pkg/apperror"
+	"api/providers/binding"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// ConfirmEmail request object
+type ConfirmEmail struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type ConfirmEmailAction struct {
+	vm             vm.Transformer
+	binder         binding.Binder
+	accountService services.AccountService
+}
+
+func NewConfirmEmailAction(vm vm.Transformer, binder binding.Binder, accountService services.AccountService) *ConfirmEmailAction {
+	return &ConfirmEmailAction{
+		vm:             vm,
+		binder:         binder,
+		accountService: accountService,
+	}
+}
+
+func (a *ConfirmEmailAction) Method() string {
+	return http.MethodPost
+}
+
+func (a *ConfirmEmailAction) Path() string {
+	return "/email/confirm"
+}
+
+func (a *ConfirmEmailAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle confirms user email
+// @Summary Confirms user email using email confirmation token
+// @Produce json
+// @Tags account
+// @Param req body ConfirmEmail true "Confirm Email Request"
+// @Success 200 {object} vm.Response
+// @Failure 400 {object} vm.ResponseError
+// @Router /account/email/confirm [post]
+func (a *ConfirmEmailAction) Handle(r *http.Request) flow.Response {
+	var reqObj ConfirmEmail
+	if err := a.binder.Bind(r, &reqObj); err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	if err := a.accountService.ConfirmEmail(r.Context(), reqObj.Token); err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, nil)
+}
@@ -0,0 +1,81 @@
+package actions
+
+import (
+	"api/modules/account/services"
+	"api/pkg/apperror"
+	"api/pkg/userip"
+	"api/providers/binding"
+	"api/providers/jwt"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// CreateAPIKey request object
+type CreateAPIKey struct {
+	Name      string     `json:"name" binding:"required,max=100"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1,dive,required,max=100"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+type CreateAPIKeyAction struct {
+	vm             vm.Transformer
+	auth           jwt.TokenAuth
+	binder         binding.Binder
+	accountService services.AccountService
+}
+
+func NewCreateAPIKeyAction(vm vm.Transformer, auth jwt.TokenAuth, binder binding.Binder, accountService services.AccountService) *CreateAPIKeyAction {
+	return &CreateAPIKeyAction{
+		vm:             vm,
+		auth:           auth,
+		binder:         binder,
+		accountService: accountService,
+	}
+}
+
+func (a *CreateAPIKeyAction) Method() string {
+	return http.MethodPost
+}
+
+func (a *CreateAPIKeyAction) Path() string {
+	return "/api-keys"
+}
+
+func (a *CreateAPIKeyAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle creates API key
+// @Summary Creates API key of current user
+// @Description Key is sent in `X-API-KEY` header. Plain key is returned only in this response, only its prefix is stored.
+// @Description Scopes have to be roles or permissions granted to the user, scopes revoked from the user stop working for the key.
+// @Description Key expires after configured lifetime unless earlier expiration is given
+// @Produce json
+// @Tags account
+// @Security BearerAuth
+// @Param req body CreateAPIKey true "Create API Key Request"
+// @Success 200 {object} apikeys.CreatedAPIKey
+// @Failure 400 {object} vm.ResponseError
+// @Router /account/api-keys [post]
+func (a *CreateAPIKeyAction) Handle(r *http.Request) flow.Response {
+	userID, err := a.auth.RequestUserID(r)
+	if err != nil {
+		return a.vm.Error(http.StatusUnauthorized, err)
+	}
+
+	var reqObj CreateAPIKey
+	if err := a.binder.Bind(r, &reqObj); err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	key, err := a.accountService.CreateAPIKey(r.Context(), userID, reqObj.Name, reqObj.Scopes, reqObj.ExpiresAt, userip.Get(r), r.UserAgent())
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, key)
+}
@@ -0,0 +1,60 @@
+package actions
+
+import (
+	"api/modules/account/services"
+	"api/providers/jwt"
+	"api/providers/vm"
+	"net/http"
+
+	"github.com/go-flow/flow/v2"
+)
+
+type DeleteSessionAction struct {
+	vm             vm.Transformer
+	auth           jwt.TokenAuth
+	accountService services.AccountService
+}
+
+func NewDeleteSessionAction(vm vm.Transformer, auth jwt.TokenAuth, accountService services.AccountService) *DeleteSessionAction {
+	return &DeleteSessionAction{
+		vm:             vm,
+		auth:           auth,
+		accountService: accountService,
+	}
+}
+
+func (a *DeleteSessionAction) Method() string {
+	return http.MethodDelete
+}
+
+func (a *DeleteSessionAction) Path() string {
+	return "/sessions/:id"
+}
+
+func (a *DeleteSessionAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle terminates user session
+// @Summary Revokes session (device) of current user
+// @Produce json
+// @Tags account
+// @Security BearerAuth
+// @Param id path string true "Session ID"
+// @Success 200 {object} vm.Response
+// @Failure 400 {object} vm.ResponseError
+// @Router /account/sessions/{id} [delete]
+func (a *DeleteSessionAction) Handle(r *http.Request) flow.Response {
+	userID, err := a.auth.RequestUserID(r)
+	if err != nil {
+		return a.vm.Error(http.StatusUnauthorized, err)
+	}
+
+	sessionID := flow.ParamsFromContext(r.Context()).ByName("id")
+
+	if err := a.accountService.DeleteSession(r.Context(), userID, sessionID); err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, nil)
+}
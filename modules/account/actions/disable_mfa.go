@@ -0,0 +1,73 @@
+package actions
+
+import (
+	"api/modules/account/services"
+	"api/pkg/apperror"
+	"api/providers/binding"
+	"api/providers/jwt"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// DisableMFA request object
+type DisableMFA struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type DisableMFAAction struct {
+	vm             vm.Transformer
+	auth           jwt.TokenAuth
+	binder         binding.Binder
+	accountService services.AccountService
+}
+
+func NewDisableMFAAction(vm vm.Transformer, auth jwt.TokenAuth, binder binding.Binder, accountService services.AccountService) *DisableMFAAction {
+	return &DisableMFAAction{
+		vm:             vm,
+		auth:           auth,
+		binder:         binder,
+		accountService: accountService,
+	}
+}
+
+func (a *DisableMFAAction) Method() string {
+	return http.MethodPost
+}
+
+func (a *DisableMFAAction) Path() string {
+	return "/mfa/disable"
+}
+
+func (a *DisableMFAAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle disables MFA for current user
+// @Summary Disables MFA for current user after validating TOTP or recovery code
+// @Produce json
+// @Tags account
+// @Security BearerAuth
+// @Param req body DisableMFA true "Disable MFA Request"
+// @Success 200 {object} vm.Response
+// @Failure 400 {object} vm.ResponseError
+// @Router /account/mfa/disable [post]
+func (a *DisableMFAAction) Handle(r *http.Request) flow.Response {
+	var reqObj DisableMFA
+	if err := a.binder.Bind(r, &reqObj); err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	userID, err := a.auth.RequestUserID(r)
+	if err != nil {
+		return a.vm.Error(http.StatusUnauthorized, err)
+	}
+
+	if err := a.accountService.DisableMFA(r.Context(), userID, reqObj.Code); err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, nil)
+}
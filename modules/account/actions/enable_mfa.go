@@ -0,0 +1,74 @@
+package actions
+
+import (
+	"api/modules/account/services"
+	"api/pkg/apperror"
+	"api/providers/binding"
+	"api/providers/jwt"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// EnableMFA request object
+type EnableMFA struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type EnableMFAAction struct {
+	vm             vm.Transformer
+	auth           jwt.TokenAuth
+	binder         binding.Binder
+	accountService services.AccountService
+}
+
+func NewEnableMFAAction(vm vm.Transformer, auth jwt.TokenAuth, binder binding.Binder, accountService services.AccountService) *EnableMFAAction {
+	return &EnableMFAAction{
+		vm:             vm,
+		auth:           auth,
+		binder:         binder,
+		accountService: accountService,
+	}
+}
+
+func (a *EnableMFAAction) Method() string {
+	return http.MethodPost
+}
+
+func (a *EnableMFAAction) Path() string {
+	return "/mfa/enable"
+}
+
+func (a *EnableMFAAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle enables MFA for current user
+// @Summary Confirms enrolled TOTP secret with valid code, enables MFA and returns recovery codes
+// @Produce json
+// @Tags account
+// @Security BearerAuth
+// @Param req body EnableMFA true "Enable MFA Request"
+// @Success 200 {object} models.RecoveryCodes
+// @Failure 400 {object} vm.ResponseError
+// @Router /account/mfa/enable [post]
+func (a *EnableMFAAction) Handle(r *http.Request) flow.Response {
+	var reqObj EnableMFA
+	if err := a.binder.Bind(r, &reqObj); err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	userID, err := a.auth.RequestUserID(r)
+	if err != nil {
+		return a.vm.Error(http.StatusUnauthorized, err)
+	}
+
+	codes, err := a.accountService.EnableMFA(r.Context(), userID, reqObj.Code)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, codes)
+}
@@ -0,0 +1,58 @@
+package actions
+
+import (
+	"api/modules/account/services"
+	"api/providers/jwt"
+	"api/providers/vm"
+	"net/http"
+
+	"github.com/go-flow/flow/v2"
+)
+
+type EnrollMFAAction struct {
+	vm             vm.Transformer
+	auth           jwt.TokenAuth
+	accountService services.AccountService
+}
+
+func NewEnrollMFAAction(vm vm.Transformer, auth jwt.TokenAuth, accountService services.AccountService) *EnrollMFAAction {
+	return &EnrollMFAAction{
+		vm:             vm,
+		auth:           auth,
+		accountService: accountService,
+	}
+}
+
+func (a *EnrollMFAAction) Method() string {
+	return http.MethodPost
+}
+
+func (a *EnrollMFAAction) Path() string {
+	return "/mfa/enroll"
+}
+
+func (a *EnrollMFAAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle starts MFA enrolment
+// @Summary Creates TOTP secret for current user. MFA is enabled after secret is confirmed with valid code
+// @Produce json
+// @Tags account
+// @Security BearerAuth
+// @Success 200 {object} models.MFAEnrollment
+// @Failure 400 {object} vm.ResponseError
+// @Router /account/mfa/enroll [post]
+func (a *EnrollMFAAction) Handle(r *http.Request) flow.Response {
+	userID, err := a.auth.RequestUserID(r)
+	if err != nil {
+		return a.vm.Error(http.StatusUnauthorized, err)
+	}
+
+	enrollment, err := a.accountService.EnrollMFA(r.Context(), userID)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, enrollment)
+}
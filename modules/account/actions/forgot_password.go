@@ -0,0 +1,64 @@
+package actions
+
+import (
+	"api/modules/account/services"
+	"api/pkg/apperror"
+	"api/providers/binding"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// ForgotPassword request object
+type ForgotPassword struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ForgotPasswordAction struct {
+	vm             vm.Transformer
+	binder         binding.Binder
+	accountService services.AccountService
+}
+
+func NewForgotPasswordAction(vm vm.Transformer, binder binding.Binder, accountService services.AccountService) *ForgotPasswordAction {
+	return &ForgotPasswordAction{
+		vm:             vm,
+		binder:         binder,
+		accountService: accountService,
+	}
+}
+
+func (a *ForgotPasswordAction) Method() string {
+	return http.MethodPost
+}
+
+func (a *ForgotPasswordAction) Path() string {
+	return "/password/forgot"
+}
+
+func (a *ForgotPasswordAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle sends password reset token to user
+// @Summary Sends password reset token to given email. Response is the same whether email exists or not
+// @Produce json
+// @Tags account
+// @Param req body ForgotPassword true "Forgot Password Request"
+// @Success 200 {object} vm.Response
+// @Failure 400 {object} vm.ResponseError
+// @Router /account/password/forgot [post]
+func (a *ForgotPasswordAction) Handle(r *http.Request) flow.Response {
+	var reqObj ForgotPassword
+	if err := a.binder.Bind(r, &reqObj); err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	if err := a.accountService.ForgotPassword(r.Context(), reqObj.Email); err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, nil)
+}
@@ -0,0 +1,93 @@
+package actions
+
+import (
+	"api/modules/account/services"
+	"api/pkg/apperror"
+	"api/providers/binding"
+	"api/providers/config"
+	"api/providers/jwt"
+	"api/providers/oidc"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// LinkProvider request object
+type LinkProvider struct {
+	Provider    string `json:"provider" binding:"required,max=50"`
+	ReauthToken string `json:"reauthToken" binding:"required"`
+}
+
+type LinkProviderAction struct {
+	vm             vm.Transformer
+	auth           jwt.TokenAuth
+	binder         binding.Binder
+	cfg            config.AppConfig
+	accountService services.AccountService
+}
+
+func NewLinkProviderAction(vm vm.Transformer, auth jwt.TokenAuth, binder binding.Binder, cfg config.AppConfig, accountService services.AccountService) *LinkProviderAction {
+	return &LinkProviderAction{
+		vm:             vm,
+		auth:           auth,
+		binder:         binder,
+		cfg:            cfg,
+		accountService: accountService,
+	}
+}
+
+func (a *LinkProviderAction) Method() string {
+	return http.MethodPost
+}
+
+func (a *LinkProviderAction) Path() string {
+	return "/providers/link"
+}
+
+func (a *LinkProviderAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle starts linking of external identity provider
+// @Summary Starts linking of external identity provider to current user
+// @Description User has to be redirected to returned redirectUrl. Identity provider redirects user back to
+// @Description `/account/oauth/{provider}/callback`, which links provider identity and returns empty data.
+// @Description State of the linking is kept in HttpOnly cookie
+// @Produce json
+// @Tags account
+// @Security BearerAuth
+// @Param req body LinkProvider true "Link Provider Request"
+// @Success 200 {object} models.ExternalLogin
+// @Failure 400 {object} vm.ResponseError
+// @Failure 403 {object} vm.ResponseError
+// @Failure 404 {object} vm.ResponseError
+// @Router /account/providers/link [post]
+func (a *LinkProviderAction) Handle(r *http.Request) flow.Response {
+	userID, err := a.auth.RequestUserID(r)
+	if err != nil {
+		return a.vm.Error(http.StatusUnauthorized, err)
+	}
+
+	var reqObj LinkProvider
+	if err := a.binder.Bind(r, &reqObj); err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	login, err := a.accountService.StartProviderLink(r.Context(), userID, reqObj.Provider, reqObj.ReauthToken)
+	if err != nil {
+		if errors.Is(err, services.ErrReauthenticationRequired) {
+			return a.vm.Error(http.StatusForbidden, err)
+		}
+		if errors.Is(err, oidc.ErrUnknownProvider) {
+			return a.vm.Error(http.StatusNotFound, err)
+		}
+		if errors.Is(err, oidc.ErrDiscovery) {
+			return a.vm.Error(http.StatusBadGateway, err)
+		}
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return vm.WithCookies(a.vm.Success(http.StatusOK, login), newExternalLoginCookie(a.cfg, reqObj.Provider, login.StateToken))
+}
@@ -5,6 +5,7 @@ import (
 	"api/pkg/apperror"
 	"api/pkg/userip"
 	"api/providers/binding"
+	"api/providers/lockout"
 	"api/providers/vm"
 	"errors"
 	"net/http"
@@ -44,12 +45,14 @@ func (a *LoginAction) Middlewares() []flow.MiddlewareHandlerFunc {
 }
 
 // Handle provides authentication tokens for user
-// @Summary Login user and provides accesToken and refreshToken pair
+// @Summary Login user and provides accesToken and refreshToken pair. Users with MFA enabled receive mfaToken instead
+// @Description Unknown email and wrong password get the same error. User or client IP locked out after too many failures gets 429
 // @Produce json
 // @Tags account
 // @Param req body Login true "Account Login Request"
 // @Success 200 {object} models.Auth
 // @Failure 400 {object} vm.ResponseError
+// @Failure 429 {object} vm.ResponseError
 // @Router /account/login [post]
 func (a *LoginAction) Handle(r *http.Request) flow.Response {
 	var reqObj Login
@@ -58,8 +61,12 @@ func (a *LoginAction) Handle(r *http.Request) flow.Response {
 	}
 
 	ip := userip.Get(r)
-	auth, err := a.accountService.Login(r.Context(), reqObj.Email, reqObj.Password, ip)
+	auth, err := a.accountService.Login(r.Context(), reqObj.Email, reqObj.Password, ip, r.UserAgent())
 	if err != nil {
+		var locked *lockout.LockedError
+		if errors.As(err, &locked) {
+			return vm.WithHeaders(a.vm.Error(http.StatusTooManyRequests, err), map[string]string{"Retry-After": locked.RetryAfterSeconds()})
+		}
 		return a.vm.Error(http.StatusBadRequest, err)
 	}
 
@@ -0,0 +1,79 @@
+package actions
+
+import (
+	"api/modules/account/services"
+	"api/pkg/apperror"
+	"api/providers/binding"
+	"api/providers/jwt"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// Logout request object
+type Logout struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+type LogoutAction struct {
+	vm             vm.Transformer
+	auth           jwt.TokenAuth
+	binder         binding.Binder
+	accountService services.AccountService
+}
+
+func NewLogoutAction(vm vm.Transformer, auth jwt.TokenAuth, binder binding.Binder, accountService services.AccountService) *LogoutAction {
+	return &LogoutAction{
+		vm:             vm,
+		auth:           auth,
+		binder:         binder,
+		accountService: accountService,
+	}
+}
+
+func (a *LogoutAction) Method() string {
+	return http.MethodPost
+}
+
+func (a *LogoutAction) Path() string {
+	return "/logout"
+}
+
+func (a *LogoutAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle terminates current user session
+// @Summary Deletes presented refresh token and revokes access token used for the request
+// @Produce json
+// @Tags account
+// @Security BearerAuth
+// @Param req body Logout true "Logout Request"
+// @Success 200 {object} vm.Response
+// @Failure 400 {object} vm.ResponseError
+// @Router /account/logout [post]
+func (a *LogoutAction) Handle(r *http.Request) flow.Response {
+	var reqObj Logout
+	if err := a.binder.Bind(r, &reqObj); err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	userID, err := a.auth.RequestUserID(r)
+	if err != nil {
+		return a.vm.Error(http.StatusUnauthorized, err)
+	}
+
+	// verify refresh token
+	token, err := a.auth.VerifyRefreshToken(reqObj.RefreshToken)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	if err := a.accountService.Logout(r.Context(), userID, a.auth.RequestAccessToken(r), token); err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, nil)
+}
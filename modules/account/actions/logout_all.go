@@ -0,0 +1,57 @@
+package actions
+
+import (
+	"api/modules/account/services"
+	"api/providers/jwt"
+	"api/providers/vm"
+	"net/http"
+
+	"github.com/go-flow/flow/v2"
+)
+
+type LogoutAllAction struct {
+	vm             vm.Transformer
+	auth           jwt.TokenAuth
+	accountService services.AccountService
+}
+
+func NewLogoutAllAction(vm vm.Transformer, auth jwt.TokenAuth, accountService services.AccountService) *LogoutAllAction {
+	return &LogoutAllAction{
+		vm:             vm,
+		auth:           auth,
+		accountService: accountService,
+	}
+}
+
+func (a *LogoutAllAction) Method() string {
+	return http.MethodPost
+}
+
+func (a *LogoutAllAction) Path() string {
+	return "/logout-all"
+}
+
+func (a *LogoutAllAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle terminates all user sessions
+// @Summary Deletes all user tokens and revokes access token used for the request
+// @Produce json
+// @Tags account
+// @Security BearerAuth
+// @Success 200 {object} vm.Response
+// @Failure 400 {object} vm.ResponseError
+// @Router /account/logout-all [post]
+func (a *LogoutAllAction) Handle(r *http.Request) flow.Response {
+	userID, err := a.auth.RequestUserID(r)
+	if err != nil {
+		return a.vm.Error(http.StatusUnauthorized, err)
+	}
+
+	if err := a.accountService.LogoutAll(r.Context(), userID, a.auth.RequestAccessToken(r)); err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, nil)
+}
@@ -0,0 +1,58 @@
+package actions
+
+import (
+	"api/modules/account/services"
+	"api/providers/jwt"
+	"api/providers/vm"
+	"net/http"
+
+	"github.com/go-flow/flow/v2"
+)
+
+type ProvidersAction struct {
+	vm             vm.Transformer
+	auth           jwt.TokenAuth
+	accountService services.AccountService
+}
+
+func NewProvidersAction(vm vm.Transformer, auth jwt.TokenAuth, accountService services.AccountService) *ProvidersAction {
+	return &ProvidersAction{
+		vm:             vm,
+		auth:           auth,
+		accountService: accountService,
+	}
+}
+
+func (a *ProvidersAction) Method() string {
+	return http.MethodGet
+}
+
+func (a *ProvidersAction) Path() string {
+	return "/providers"
+}
+
+func (a *ProvidersAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle lists user authentication providers
+// @Summary Lists authentication providers linked to current user and identity providers available for linking
+// @Produce json
+// @Tags account
+// @Security BearerAuth
+// @Success 200 {array} models.Provider
+// @Failure 400 {object} vm.ResponseError
+// @Router /account/providers [get]
+func (a *ProvidersAction) Handle(r *http.Request) flow.Response {
+	userID, err := a.auth.RequestUserID(r)
+	if err != nil {
+		return a.vm.Error(http.StatusUnauthorized, err)
+	}
+
+	providers, err := a.accountService.GetProviders(r.Context(), userID)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, providers)
+}
@@ -0,0 +1,83 @@
+package actions
+
+import (
+	"api/modules/account/services"
+	"api/pkg/apperror"
+	"api/pkg/userip"
+	"api/providers/binding"
+	"api/providers/jwt"
+	"api/providers/lockout"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// Reauthenticate request object, either password or MFA code is required
+type Reauthenticate struct {
+	Password string `json:"password" binding:"required_without=Code"`
+	Code     string `json:"code" binding:"required_without=Password"`
+}
+
+type ReauthenticateAction struct {
+	vm             vm.Transformer
+	auth           jwt.TokenAuth
+	binder         binding.Binder
+	accountService services.AccountService
+}
+
+func NewReauthenticateAction(vm vm.Transformer, auth jwt.TokenAuth, binder binding.Binder, accountService services.AccountService) *ReauthenticateAction {
+	return &ReauthenticateAction{
+		vm:             vm,
+		auth:           auth,
+		binder:         binder,
+		accountService: accountService,
+	}
+}
+
+func (a *ReauthenticateAction) Method() string {
+	return http.MethodPost
+}
+
+func (a *ReauthenticateAction) Path() string {
+	return "/reauthenticate"
+}
+
+func (a *ReauthenticateAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle confirms identity of current user
+// @Summary Confirms identity of current user with password or MFA code
+// @Description Returned reauthToken is valid for 5 minutes and it is required for changes of linked authentication providers
+// @Produce json
+// @Tags account
+// @Security BearerAuth
+// @Param req body Reauthenticate true "Reauthenticate Request"
+// @Success 200 {object} models.Reauthentication
+// @Failure 400 {object} vm.ResponseError
+// @Failure 429 {object} vm.ResponseError
+// @Router /account/reauthenticate [post]
+func (a *ReauthenticateAction) Handle(r *http.Request) flow.Response {
+	userID, err := a.auth.RequestUserID(r)
+	if err != nil {
+		return a.vm.Error(http.StatusUnauthorized, err)
+	}
+
+	var reqObj Reauthenticate
+	if err := a.binder.Bind(r, &reqObj); err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	reauth, err := a.accountService.Reauthenticate(r.Context(), userID, reqObj.Password, reqObj.Code, userip.Get(r), r.UserAgent())
+	if err != nil {
+		var locked *lockout.LockedError
+		if errors.As(err, &locked) {
+			return vm.WithHeaders(a.vm.Error(http.StatusTooManyRequests, err), map[string]string{"Retry-After": locked.RetryAfterSeconds()})
+		}
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, reauth)
+}
@@ -3,6 +3,7 @@ package actions
 import (
 	"api/modules/account/services"
 	"api/pkg/apperror"
+	"api/pkg/userip"
 	"api/providers/binding"
 	"api/providers/jwt"
 	"api/providers/vm"
@@ -66,7 +67,7 @@ func (a *RefreshTokenAction) Handle(r *http.Request) flow.Response {
 		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
 	}
 
-	auth, err := a.accountService.RefreshToken(r.Context(), token)
+	auth, err := a.accountService.RefreshToken(r.Context(), token, userip.Get(r), r.UserAgent())
 	if err != nil {
 		return a.vm.Error(http.StatusBadRequest, err)
 	}
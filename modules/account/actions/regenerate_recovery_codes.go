@@ -0,0 +1,74 @@
+package actions
+
+import (
+	"api/modules/account/services"
+	"api/pkg/apperror"
+	"api/providers/binding"
+	"api/providers/jwt"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// RegenerateRecoveryCodes request object
+type RegenerateRecoveryCodes struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type RegenerateRecoveryCodesAction struct {
+	vm             vm.Transformer
+	auth           jwt.TokenAuth
+	binder         binding.Binder
+	accountService services.AccountService
+}
+
+func NewRegenerateRecoveryCodesAction(vm vm.Transformer, auth jwt.TokenAuth, binder binding.Binder, accountService services.AccountService) *RegenerateRecoveryCodesAction {
+	return &RegenerateRecoveryCodesAction{
+		vm:             vm,
+		auth:           auth,
+		binder:         binder,
+		accountService: accountService,
+	}
+}
+
+func (a *RegenerateRecoveryCodesAction) Method() string {
+	return http.MethodPost
+}
+
+func (a *RegenerateRecoveryCodesAction) Path() string {
+	return "/mfa/recovery-codes"
+}
+
+func (a *RegenerateRecoveryCodesAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle replaces MFA recovery codes
+// @Summary Replaces MFA recovery codes of current user after validating TOTP or recovery code
+// @Produce json
+// @Tags account
+// @Security BearerAuth
+// @Param req body RegenerateRecoveryCodes true "Regenerate Recovery Codes Request"
+// @Success 200 {object} models.RecoveryCodes
+// @Failure 400 {object} vm.ResponseError
+// @Router /account/mfa/recovery-codes [post]
+func (a *RegenerateRecoveryCodesAction) Handle(r *http.Request) flow.Response {
+	var reqObj RegenerateRecoveryCodes
+	if err := a.binder.Bind(r, &reqObj); err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	userID, err := a.auth.RequestUserID(r)
+	if err != nil {
+		return a.vm.Error(http.StatusUnauthorized, err)
+	}
+
+	codes, err := a.accountService.RegenerateRecoveryCodes(r.Context(), userID, reqObj.Code)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, codes)
+}
@@ -47,7 +47,8 @@ func (a *RegisterAction) Middlewares() []flow.MiddlewareHandlerFunc {
 }
 
 // Handle Registers user to database and provide authentication tokens
-// @Summary Register user to database and provides acces_token and refresh_token pair
+// @Summary Register user to database and provides acces_token and refresh_token pair.
+// @Description When users with unconfirmed email are not allowed to login, data is empty until email is confirmed
 // @Produce json
 // @Tags account
 // @Param req body Register true "Account Register Request"
@@ -62,7 +63,7 @@ func (a *RegisterAction) Handle(r *http.Request) flow.Response {
 
 	ip := userip.Get(r)
 
-	auth, err := a.accountService.Register(r.Context(), reqObj.Email, reqObj.Password, reqObj.FirstName, reqObj.LastName, ip)
+	auth, err := a.accountService.Register(r.Context(), reqObj.Email, reqObj.Password, reqObj.FirstName, reqObj.LastName, ip, r.UserAgent())
 	if err != nil {
 		return a.vm.Error(http.StatusBadRequest, err)
 	}
@@ -0,0 +1,57 @@
+package actions
+
+import (
+	"api/modules/account/services"
+	"api/providers/jwt"
+	"api/providers/vm"
+	"net/http"
+
+	"github.com/go-flow/flow/v2"
+)
+
+type RequestAccountDeletionAction struct {
+	vm             vm.Transformer
+	auth           jwt.TokenAuth
+	accountService services.AccountService
+}
+
+func NewRequestAccountDeletionAction(vm vm.Transformer, auth jwt.TokenAuth, accountService services.AccountService) *RequestAccountDeletionAction {
+	return &RequestAccountDeletionAction{
+		vm:             vm,
+		auth:           auth,
+		accountService: accountService,
+	}
+}
+
+func (a *RequestAccountDeletionAction) Method() string {
+	return http.MethodPost
+}
+
+func (a *RequestAccountDeletionAction) Path() string {
+	return "/delete"
+}
+
+func (a *RequestAccountDeletionAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle requests account deletion
+// @Summary Sends account deletion confirmation token to user email
+// @Produce json
+// @Tags account
+// @Security BearerAuth
+// @Success 200 {object} vm.Response
+// @Failure 400 {object} vm.ResponseError
+// @Router /account/delete [post]
+func (a *RequestAccountDeletionAction) Handle(r *http.Request) flow.Response {
+	userID, err := a.auth.RequestUserID(r)
+	if err != nil {
+		return a.vm.Error(http.StatusUnauthorized, err)
+	}
+
+	if err := a.accountService.RequestAccountDeletion(r.Context(), userID); err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, nil)
+}
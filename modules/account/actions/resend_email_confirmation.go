@@ -0,0 +1,64 @@
+package actions
+
+import (
+	"api/modules/account/services"
+	"api/pkg/apperror"
+	"api/providers/binding"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// ResendEmailConfirmation request object
+type ResendEmailConfirmation struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResendEmailConfirmationAction struct {
+	vm             vm.Transformer
+	binder         binding.Binder
+	accountService services.AccountService
+}
+
+func NewResendEmailConfirmationAction(vm vm.Transformer, binder binding.Binder, accountService services.AccountService) *ResendEmailConfirmationAction {
+	return &ResendEmailConfirmationAction{
+		vm:             vm,
+		binder:         binder,
+		accountService: accountService,
+	}
+}
+
+func (a *ResendEmailConfirmationAction) Method() string {
+	return http.MethodPost
+}
+
+func (a *ResendEmailConfirmationAction) Path() string {
+	return "/email/resend"
+}
+
+func (a *ResendEmailConfirmationAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle sends new email confirmation token
+// @Summary Sends new email confirmation token. Response is the same whether email exists or not
+// @Produce json
+// @Tags account
+// @Param req body ResendEmailConfirmation true "Resend Email Confirmation Request"
+// @Success 200 {object} vm.Response
+// @Failure 400 {object} vm.ResponseError
+// @Router /account/email/resend [post]
+func (a *ResendEmailConfirmationAction) Handle(r *http.Request) flow.Response {
+	var reqObj ResendEmailConfirmation
+	if err := a.binder.Bind(r, &reqObj); err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	if err := a.accountService.ResendEmailConfirmation(r.Context(), reqObj.Email); err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, nil)
+}
@@ -0,0 +1,66 @@
+package actions
+
+import (
+	"api/modules/account/services"
+	"api/pkg/apperror"
+	"api/pkg/userip"
+	"api/providers/binding"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// ResetPassword request object
+type ResetPassword struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=3"`
+}
+
+type ResetPasswordAction struct {
+	vm             vm.Transformer
+	binder         binding.Binder
+	accountService services.AccountService
+}
+
+func NewResetPasswordAction(vm vm.Transformer, binder binding.Binder, accountService services.AccountService) *ResetPasswordAction {
+	return &ResetPasswordAction{
+		vm:             vm,
+		binder:         binder,
+		accountService: accountService,
+	}
+}
+
+func (a *ResetPasswordAction) Method() string {
+	return http.MethodPost
+}
+
+func (a *ResetPasswordAction) Path() string {
+	return "/password/reset"
+}
+
+func (a *ResetPasswordAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle sets new user password
+// @Summary Sets new password using password reset token. All user sessions are terminated
+// @Produce json
+// @Tags account
+// @Param req body ResetPassword true "Reset Password Request"
+// @Success 200 {object} vm.Response
+// @Failure 400 {object} vm.ResponseError
+// @Router /account/password/reset [post]
+func (a *ResetPasswordAction) Handle(r *http.Request) flow.Response {
+	var reqObj ResetPassword
+	if err := a.binder.Bind(r, &reqObj); err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	if err := a.accountService.ResetPassword(r.Context(), reqObj.Token, reqObj.Password, userip.Get(r), r.UserAgent()); err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, nil)
+}
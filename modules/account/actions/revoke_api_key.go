@@ -0,0 +1,67 @@
+package actions
+
+import (
+	"api/modules/account/services"
+	"api/pkg/apperror"
+	"api/pkg/userip"
+	"api/providers/jwt"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-flow/flow/v2"
+)
+
+type RevokeAPIKeyAction struct {
+	vm             vm.Transformer
+	auth           jwt.TokenAuth
+	accountService services.AccountService
+}
+
+func NewRevokeAPIKeyAction(vm vm.Transformer, auth jwt.TokenAuth, accountService services.AccountService) *RevokeAPIKeyAction {
+	return &RevokeAPIKeyAction{
+		vm:             vm,
+		auth:           auth,
+		accountService: accountService,
+	}
+}
+
+func (a *RevokeAPIKeyAction) Method() string {
+	return http.MethodDelete
+}
+
+func (a *RevokeAPIKeyAction) Path() string {
+	return "/api-keys/:id"
+}
+
+func (a *RevokeAPIKeyAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle revokes user API key
+// @Summary Revokes API key of current user
+// @Produce json
+// @Tags account
+// @Security BearerAuth
+// @Param id path int true "API Key ID"
+// @Success 200 {object} vm.Response
+// @Failure 400 {object} vm.ResponseError
+// @Router /account/api-keys/{id} [delete]
+func (a *RevokeAPIKeyAction) Handle(r *http.Request) flow.Response {
+	userID, err := a.auth.RequestUserID(r)
+	if err != nil {
+		return a.vm.Error(http.StatusUnauthorized, err)
+	}
+
+	id, err := strconv.ParseUint(flow.ParamsFromContext(r.Context()).ByName("id"), 10, 64)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	if err := a.accountService.RevokeAPIKey(r.Context(), userID, id, userip.Get(r), r.UserAgent()); err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, nil)
+}
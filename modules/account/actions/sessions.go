@@ -0,0 +1,58 @@
+package actions
+
+import (
+	"api/modules/account/services"
+	"api/providers/jwt"
+	"api/providers/vm"
+	"net/http"
+
+	"github.com/go-flow/flow/v2"
+)
+
+type SessionsAction struct {
+	vm             vm.Transformer
+	auth           jwt.TokenAuth
+	accountService services.AccountService
+}
+
+func NewSessionsAction(vm vm.Transformer, auth jwt.TokenAuth, accountService services.AccountService) *SessionsAction {
+	return &SessionsAction{
+		vm:             vm,
+		auth:           auth,
+		accountService: accountService,
+	}
+}
+
+func (a *SessionsAction) Method() string {
+	return http.MethodGet
+}
+
+func (a *SessionsAction) Path() string {
+	return "/sessions"
+}
+
+func (a *SessionsAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle lists user sessions
+// @Summary Lists active sessions (devices) of current user
+// @Produce json
+// @Tags account
+// @Security BearerAuth
+// @Success 200 {array} models.Session
+// @Failure 400 {object} vm.ResponseError
+// @Router /account/sessions [get]
+func (a *SessionsAction) Handle(r *http.Request) flow.Response {
+	userID, err := a.auth.RequestUserID(r)
+	if err != nil {
+		return a.vm.Error(http.StatusUnauthorized, err)
+	}
+
+	sessions, err := a.accountService.GetSessions(r.Context(), userID)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, sessions)
+}
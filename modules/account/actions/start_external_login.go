@@ -0,0 +1,87 @@
+package actions
+
+import (
+	"api/modules/account/services"
+	"api/providers/config"
+	"api/providers/oidc"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// externalLoginCookie holds state token of external login until identity provider redirects user back
+const externalLoginCookie = "external_login"
+
+// newExternalLoginCookie returns state cookie of external login, cookie is sent only to endpoints of given provider.
+// Empty value expires the cookie
+func newExternalLoginCookie(cfg config.AppConfig, provider string, value string) *http.Cookie {
+	cookie := &http.Cookie{
+		Name:     externalLoginCookie,
+		Value:    value,
+		Path:     "/account/oauth/" + provider,
+		HttpOnly: true,
+		Secure:   strings.HasPrefix(cfg.IdentityProviderCallbackURL(), "https://"),
+		// provider redirects user back with top level navigation, which carries Lax cookies
+		SameSite: http.SameSiteLaxMode,
+	}
+
+	if value == "" {
+		cookie.MaxAge = -1
+	}
+	return cookie
+}
+
+type StartExternalLoginAction struct {
+	vm             vm.Transformer
+	cfg            config.AppConfig
+	accountService services.AccountService
+}
+
+func NewStartExternalLoginAction(vm vm.Transformer, cfg config.AppConfig, accountService services.AccountService) *StartExternalLoginAction {
+	return &StartExternalLoginAction{
+		vm:             vm,
+		cfg:            cfg,
+		accountService: accountService,
+	}
+}
+
+func (a *StartExternalLoginAction) Method() string {
+	return http.MethodGet
+}
+
+func (a *StartExternalLoginAction) Path() string {
+	return "/oauth/:provider/start"
+}
+
+func (a *StartExternalLoginAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle redirects user to external identity provider
+// @Summary Starts login with external OpenID Connect identity provider
+// @Description User is redirected to identity provider, state of the login is kept in HttpOnly cookie
+// @Tags account
+// @Param provider path string true "Identity provider name"
+// @Success 302
+// @Failure 404 {object} vm.ResponseError
+// @Failure 502 {object} vm.ResponseError
+// @Router /account/oauth/{provider}/start [get]
+func (a *StartExternalLoginAction) Handle(r *http.Request) flow.Response {
+	provider := flow.ParamsFromContext(r.Context()).ByName("provider")
+
+	login, err := a.accountService.StartExternalLogin(r.Context(), provider)
+	if err != nil {
+		if errors.Is(err, oidc.ErrUnknownProvider) {
+			return a.vm.Error(http.StatusNotFound, err)
+		}
+		if errors.Is(err, oidc.ErrDiscovery) {
+			return a.vm.Error(http.StatusBadGateway, err)
+		}
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return vm.WithCookies(flow.ResponseRedirect(http.StatusFound, login.RedirectURL), newExternalLoginCookie(a.cfg, provider, login.StateToken))
+}
@@ -0,0 +1,80 @@
+package actions
+
+import (
+	"api/modules/account/services"
+	"api/pkg/apperror"
+	"api/pkg/userip"
+	"api/providers/binding"
+	"api/providers/jwt"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// UnlinkProvider request object
+type UnlinkProvider struct {
+	Provider    string `json:"provider" binding:"required,max=50"`
+	ReauthToken string `json:"reauthToken" binding:"required"`
+}
+
+type UnlinkProviderAction struct {
+	vm             vm.Transformer
+	auth           jwt.TokenAuth
+	binder         binding.Binder
+	accountService services.AccountService
+}
+
+func NewUnlinkProviderAction(vm vm.Transformer, auth jwt.TokenAuth, binder binding.Binder, accountService services.AccountService) *UnlinkProviderAction {
+	return &UnlinkProviderAction{
+		vm:             vm,
+		auth:           auth,
+		binder:         binder,
+		accountService: accountService,
+	}
+}
+
+func (a *UnlinkProviderAction) Method() string {
+	return http.MethodPost
+}
+
+func (a *UnlinkProviderAction) Path() string {
+	return "/providers/unlink"
+}
+
+func (a *UnlinkProviderAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle unlinks external identity provider
+// @Summary Unlinks external identity provider from current user
+// @Description Last authentication provider of the account can not be unlinked
+// @Produce json
+// @Tags account
+// @Security BearerAuth
+// @Param req body UnlinkProvider true "Unlink Provider Request"
+// @Success 200 {object} vm.Response
+// @Failure 400 {object} vm.ResponseError
+// @Failure 403 {object} vm.ResponseError
+// @Router /account/providers/unlink [post]
+func (a *UnlinkProviderAction) Handle(r *http.Request) flow.Response {
+	userID, err := a.auth.RequestUserID(r)
+	if err != nil {
+		return a.vm.Error(http.StatusUnauthorized, err)
+	}
+
+	var reqObj UnlinkProvider
+	if err := a.binder.Bind(r, &reqObj); err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	if err := a.accountService.UnlinkProvider(r.Context(), userID, reqObj.Provider, reqObj.ReauthToken, userip.Get(r), r.UserAgent()); err != nil {
+		if errors.Is(err, services.ErrReauthenticationRequired) {
+			return a.vm.Error(http.StatusForbidden, err)
+		}
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, nil)
+}
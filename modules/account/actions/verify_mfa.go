@@ -0,0 +1,73 @@
+package actions
+
+import (
+	"api/modules/account/services"
+	"api/pkg/apperror"
+	"api/pkg/userip"
+	"api/providers/binding"
+	"api/providers/lockout"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// VerifyMFA request object
+type VerifyMFA struct {
+	MFAToken string `json:"mfaToken" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+type VerifyMFAAction struct {
+	vm             vm.Transformer
+	binder         binding.Binder
+	accountService services.AccountService
+}
+
+func NewVerifyMFAAction(vm vm.Transformer, binder binding.Binder, accountService services.AccountService) *VerifyMFAAction {
+	return &VerifyMFAAction{
+		vm:             vm,
+		binder:         binder,
+		accountService: accountService,
+	}
+}
+
+func (a *VerifyMFAAction) Method() string {
+	return http.MethodPost
+}
+
+func (a *VerifyMFAAction) Path() string {
+	return "/mfa/verify"
+}
+
+func (a *VerifyMFAAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle completes login of user with MFA enabled
+// @Summary Exchanges MFA token and TOTP or recovery code for accesToken and refreshToken pair
+// @Produce json
+// @Tags account
+// @Param req body VerifyMFA true "Verify MFA Request"
+// @Success 200 {object} models.Auth
+// @Failure 400 {object} vm.ResponseError
+// @Failure 429 {object} vm.ResponseError
+// @Router /account/mfa/verify [post]
+func (a *VerifyMFAAction) Handle(r *http.Request) flow.Response {
+	var reqObj VerifyMFA
+	if err := a.binder.Bind(r, &reqObj); err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	auth, err := a.accountService.VerifyMFA(r.Context(), reqObj.MFAToken, reqObj.Code, userip.Get(r), r.UserAgent())
+	if err != nil {
+		var locked *lockout.LockedError
+		if errors.As(err, &locked) {
+			return vm.WithHeaders(a.vm.Error(http.StatusTooManyRequests, err), map[string]string{"Retry-After": locked.RetryAfterSeconds()})
+		}
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, auth)
+}
@@ -1,6 +1,9 @@
 package models
 
 type Auth struct {
-	AccessToken  string `json:"accessToken"`
-	RefreshToken string `json:"refreshToken"`
+	AccessToken  string `json:"accessToken,omitempty"`
+	RefreshToken string `json:"refreshToken,omitempty"`
+	// MFAToken is returned instead of access and refresh tokens when user has MFA enabled.
+	// It can be used only to complete login with MFA code
+	MFAToken string `json:"mfaToken,omitempty"`
 }
@@ -0,0 +1,8 @@
+package models
+
+// ExternalLogin holds redirect to external identity provider.
+// State token has to be kept by user agent and presented when provider redirects user back
+type ExternalLogin struct {
+	RedirectURL string `json:"redirectUrl"`
+	StateToken  string `json:"-"`
+}
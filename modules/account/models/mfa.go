@@ -0,0 +1,13 @@
+package models
+
+// MFAEnrollment holds TOTP secret which user registers in authenticator app
+type MFAEnrollment struct {
+	Secret string `json:"secret"`
+	URL    string `json:"url"`
+}
+
+// RecoveryCodes holds single use MFA recovery codes.
+// Codes are shown only once and stored hashed
+type RecoveryCodes struct {
+	Codes []string `json:"codes"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// Provider describes authentication provider user can login with
+type Provider struct {
+	Name     string     `json:"name"`
+	Linked   bool       `json:"linked"`
+	LinkedAt *time.Time `json:"linkedAt"`
+}
+
+// Reauthentication holds short lived token proving that user recently confirmed identity.
+// It is required for changes of linked authentication providers
+type Reauthentication struct {
+	ReauthToken string `json:"reauthToken"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// Session describes single user login on a device
+type Session struct {
+	ID         string     `json:"id"`
+	UserAgent  string     `json:"userAgent"`
+	ClientIP   string     `json:"clientIp"`
+	StartedAt  *time.Time `json:"startedAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	ExpiresAt  time.Time  `json:"expiresAt"`
+	ClientID   string     `json:"clientId,omitempty"`
+}
@@ -3,7 +3,10 @@ package account
 import (
 	"api/modules/account/routers"
 	"api/modules/account/services"
+	"api/modules/apikeys"
+	"api/modules/audit"
 	"api/modules/auth"
+	"api/modules/mfa"
 	"api/modules/roles"
 	"api/modules/tokens"
 	"api/modules/users"
@@ -36,11 +39,15 @@ func (m *Module) ProvideModules() []flow.Provider {
 		flow.NewProvider(users.NewModule),
 		flow.NewProvider(auth.NewModule),
 		flow.NewProvider(tokens.NewModule),
+		flow.NewProvider(mfa.NewModule),
+		flow.NewProvider(apikeys.NewModule),
+		flow.NewProvider(audit.NewModule),
 	}
 }
 
 func (m *Module) ProvideRouters() []flow.Provider {
 	return []flow.Provider{
 		flow.NewProvider(routers.NewPublicRouter),
+		flow.NewProvider(routers.NewPrivateRouter),
 	}
 }
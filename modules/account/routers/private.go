@@ -0,0 +1,56 @@
+package routers
+
+import (
+	"api/modules/account/actions"
+	"api/providers/jwt"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// PrivateRouter handles account actions available only to authorized users
+type PrivateRouter struct {
+	auth jwt.TokenAuth
+}
+
+func NewPrivateRouter(auth jwt.TokenAuth) *PrivateRouter {
+	return &PrivateRouter{
+		auth: auth,
+	}
+}
+
+func (r *PrivateRouter) Path() string {
+	return "/account"
+}
+
+func (r *PrivateRouter) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{
+		r.auth.AuthorizeRequest(jwt.ScopeAuthorized),
+	}
+}
+
+func (r *PrivateRouter) ProvideHandlers() []flow.Provider {
+	return []flow.Provider{
+		flow.NewProvider(actions.NewLogoutAction),
+		flow.NewProvider(actions.NewLogoutAllAction),
+		flow.NewProvider(actions.NewSessionsAction),
+		flow.NewProvider(actions.NewDeleteSessionAction),
+		flow.NewProvider(actions.NewEnrollMFAAction),
+		flow.NewProvider(actions.NewEnableMFAAction),
+		flow.NewProvider(actions.NewDisableMFAAction),
+		flow.NewProvider(actions.NewRegenerateRecoveryCodesAction),
+		flow.NewProvider(actions.NewActivityAction),
+		flow.NewProvider(actions.NewRequestAccountDeletionAction),
+		flow.NewProvider(actions.NewConfirmAccountDeletionAction),
+		flow.NewProvider(actions.NewCreateAPIKeyAction),
+		flow.NewProvider(actions.NewAPIKeysAction),
+		flow.NewProvider(actions.NewRevokeAPIKeyAction),
+		flow.NewProvider(actions.NewReauthenticateAction),
+		flow.NewProvider(actions.NewProvidersAction),
+		flow.NewProvider(actions.NewLinkProviderAction),
+		flow.NewProvider(actions.NewUnlinkProviderAction),
+	}
+}
+
+func (r *PrivateRouter) RegisterSubRouters() bool {
+	return false
+}
@@ -26,6 +26,14 @@ func (r *PublicRouter) ProvideHandlers() []flow.Provider {
 	return []flow.Provider{
 		flow.NewProvider(actions.NewRegisterAction),
 		flow.NewProvider(actions.NewLoginAction),
+		flow.NewProvider(actions.NewRefreshTokenAction),
+		flow.NewProvider(actions.NewForgotPasswordAction),
+		flow.NewProvider(actions.NewResetPasswordAction),
+		flow.NewProvider(actions.NewConfirmEmailAction),
+		flow.NewProvider(actions.NewResendEmailConfirmationAction),
+		flow.NewProvider(actions.NewVerifyMFAAction),
+		flow.NewProvider(actions.NewStartExternalLoginAction),
+		flow.NewProvider(actions.NewCompleteExternalLoginAction),
 	}
 }
 
@@ -2,18 +2,45 @@ package services
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
 	"errors"
+	"fmt"
+	"strconv"
+	"time"
 
 	"api/modules/account/models"
+	"api/modules/apikeys"
+	"api/modules/audit"
 	"api/modules/auth"
-	"api/modules/roles"
+	"api/modules/mfa"
+	roleModels "api/modules/roles/models"
+	roleServices "api/modules/roles/services"
 	"api/modules/tokens"
+	userModels "api/modules/users/models"
 	"api/modules/users/services"
 	"api/pkg/apperror"
+	"api/pkg/paging"
 
+	"api/providers/config"
+	"api/providers/db"
 	"api/providers/jwt"
+	"api/providers/lockout"
+	"api/providers/log"
+	"api/providers/notify"
+	"api/providers/oidc"
 )
 
+// externalLoginLifetime limits time user can spend at external identity provider
+const externalLoginLifetime = 10 * time.Minute
+
+// reauthLifetime limits time after reauthentication in which user can change linked authentication providers
+const reauthLifetime = 5 * time.Minute
+
+// reauthPurpose distinguishes reauthentication tokens from other state tokens
+const reauthPurpose = "reauth"
+
 var (
 	// ErrRegisterUser error is returned when user could not be registered
 	ErrRegisterUser = errors.New("unable to register user")
@@ -23,6 +50,121 @@ var (
 
 	// ErrRefreshTokens error is returned when auth tokens could not be refreshed
 	ErrRefreshTokens = errors.New("unable to refresh tokens")
+
+	// ErrForgotPassword error is returned when password reset could not be requested
+	ErrForgotPassword = errors.New("unable to request password reset")
+
+	// ErrResetPassword error is returned when password could not be reset
+	ErrResetPassword = errors.New("unable to reset password")
+
+	// ErrResetTokenUsed error is returned when password reset token was already used
+	ErrResetTokenUsed = errors.New("password reset token was already used")
+
+	// ErrConfirmEmail error is returned when email could not be confirmed
+	ErrConfirmEmail = errors.New("unable to confirm email")
+
+	// ErrResendEmailConfirmation error is returned when email confirmation could not be resent
+	ErrResendEmailConfirmation = errors.New("unable to resend email confirmation")
+
+	// ErrLogout error is returned when user session could not be terminated
+	ErrLogout = errors.New("unable to logout user")
+
+	// ErrFetchSessions error is returned when user sessions could not be retrieved
+	ErrFetchSessions = errors.New("unable to fetch sessions")
+
+	// ErrDeleteSession error is returned when user session could not be deleted
+	ErrDeleteSession = errors.New("unable to delete session")
+
+	// ErrSessionNotExist error is returned when user session does not exist
+	ErrSessionNotExist = errors.New("session does not exist")
+
+	// ErrVerifyMFA error is returned when MFA login step could not be completed
+	ErrVerifyMFA = errors.New("unable to verify mfa")
+
+	// ErrEnrollMFA error is returned when MFA enrolment could not be started
+	ErrEnrollMFA = errors.New("unable to enroll mfa")
+
+	// ErrEnableMFA error is returned when MFA could not be enabled
+	ErrEnableMFA = errors.New("unable to enable mfa")
+
+	// ErrDisableMFA error is returned when MFA could not be disabled
+	ErrDisableMFA = errors.New("unable to disable mfa")
+
+	// ErrRecoveryCodes error is returned when MFA recovery codes could not be regenerated
+	ErrRecoveryCodes = errors.New("unable to regenerate recovery codes")
+
+	// ErrFetchActivity error is returned when user activity could not be retrieved
+	ErrFetchActivity = errors.New("unable to fetch activity")
+
+	// ErrAccountLocked error is returned when user or client is temporarily locked out after failed login attempts
+	ErrAccountLocked = errors.New("account is temporarily locked")
+
+	// ErrInvalidCredentials error is returned when email or password is not valid.
+	// Causes are not distinguished, so login does not reveal which emails are registered
+	ErrInvalidCredentials = errors.New("invalid email or password")
+
+	// ErrTokenOwner error is returned when presented token does not belong to user
+	ErrTokenOwner = errors.New("token does not belong to user")
+
+	// ErrEmailNotConfirmed error is returned when user with unconfirmed email is not allowed to login
+	ErrEmailNotConfirmed = errors.New("email is not confirmed")
+
+	// ErrRequestAccountDeletion error is returned when account deletion could not be requested
+	ErrRequestAccountDeletion = errors.New("unable to request account deletion")
+
+	// ErrDeleteAccount error is returned when account could not be deleted
+	ErrDeleteAccount = errors.New("unable to delete account")
+
+	// ErrPurgeAccounts error is returned when deleted accounts could not be anonymized
+	ErrPurgeAccounts = errors.New("unable to purge deleted accounts")
+
+	// ErrCreateAPIKey error is returned when API key could not be created
+	ErrCreateAPIKey = errors.New("unable to create api key")
+
+	// ErrFetchAPIKeys error is returned when API keys could not be retrieved
+	ErrFetchAPIKeys = errors.New("unable to fetch api keys")
+
+	// ErrRevokeAPIKey error is returned when API key could not be revoked
+	ErrRevokeAPIKey = errors.New("unable to revoke api key")
+
+	// ErrStartExternalLogin error is returned when login with external identity provider could not be started
+	ErrStartExternalLogin = errors.New("unable to start external login")
+
+	// ErrExternalLogin error is returned when user could not be logged in with external identity provider
+	ErrExternalLogin = errors.New("unable to login with identity provider")
+
+	// ErrExternalLoginState error is returned when callback does not belong to login started by user agent
+	ErrExternalLoginState = errors.New("invalid external login state")
+
+	// ErrExternalAccountExists error is returned when identity provider is not linked to existing account with the same email
+	ErrExternalAccountExists = errors.New("account with the same email already exists")
+
+	// ErrExternalEmailMissing error is returned when identity provider does not share user email
+	ErrExternalEmailMissing = errors.New("identity provider did not share email")
+
+	// ErrReauthenticate error is returned when user could not confirm identity
+	ErrReauthenticate = errors.New("unable to reauthenticate user")
+
+	// ErrReauthenticationRequired error is returned when change requires recent reauthentication of the user
+	ErrReauthenticationRequired = errors.New("recent reauthentication is required")
+
+	// ErrFetchProviders error is returned when authentication providers of user could not be retrieved
+	ErrFetchProviders = errors.New("unable to fetch authentication providers")
+
+	// ErrLinkProvider error is returned when authentication provider could not be linked to account
+	ErrLinkProvider = errors.New("unable to link authentication provider")
+
+	// ErrUnlinkProvider error is returned when authentication provider could not be unlinked from account
+	ErrUnlinkProvider = errors.New("unable to unlink authentication provider")
+
+	// ErrProviderLinked error is returned when provider or provider identity is already linked to account
+	ErrProviderLinked = errors.New("authentication provider is already linked")
+
+	// ErrProviderNotLinked error is returned when provider is not linked to account
+	ErrProviderNotLinked = errors.New("authentication provider is not linked")
+
+	// ErrLastCredential error is returned when the only authentication provider of account would be unlinked
+	ErrLastCredential = errors.New("last authentication provider can not be unlinked")
 )
 
 // AccountService interface
@@ -31,37 +173,154 @@ type AccountService interface {
 	AccountService() string
 
 	// Register new user to system using email and password combination
-	Register(ctx context.Context, email string, password string, firstName string, lasatName string, clientIP string) (*models.Auth, error)
+	Register(ctx context.Context, email string, password string, firstName string, lasatName string, clientIP string, userAgent string) (*models.Auth, error)
 
 	// Login user to system using email and password combination
-	Login(ctx context.Context, email string, password string, clientIP string) (*models.Auth, error)
+	Login(ctx context.Context, email string, password string, clientIP string, userAgent string) (*models.Auth, error)
 
 	// RefreshToken issues new Auth Tokens based on given refreshToken
-	RefreshToken(ctx context.Context, token string) (*models.Auth, error)
+	RefreshToken(ctx context.Context, token string, clientIP string, userAgent string) (*models.Auth, error)
+
+	// ForgotPassword issues password reset token and sends it to user with given email.
+	// Missing users and message delivery failures are not reported so the caller can not tell whether email exists
+	ForgotPassword(ctx context.Context, email string) error
+
+	// ResetPassword sets new password for user identified by password reset token
+	// and invalidates all user's refresh tokens
+	ResetPassword(ctx context.Context, token string, password string, clientIP string, userAgent string) error
+
+	// ConfirmEmail confirms email of user identified by email confirmation token
+	ConfirmEmail(ctx context.Context, token string) error
+
+	// ResendEmailConfirmation sends new email confirmation token to user with given email.
+	// Missing or already confirmed users are not reported
+	ResendEmailConfirmation(ctx context.Context, email string) error
+
+	// Logout terminates user session identified by given refresh token and revokes given access token
+	Logout(ctx context.Context, userID uint64, accessToken string, refreshToken string) error
+
+	// LogoutAll terminates all user sessions and revokes given access token
+	LogoutAll(ctx context.Context, userID uint64, accessToken string) error
+
+	// GetSessions returns all active sessions of given user
+	GetSessions(ctx context.Context, userID uint64) ([]*models.Session, error)
+
+	// DeleteSession terminates user session with given id
+	DeleteSession(ctx context.Context, userID uint64, sessionID string) error
+
+	// VerifyMFA completes login of user with MFA enabled using MFA token issued by Login and TOTP or recovery code
+	VerifyMFA(ctx context.Context, mfaToken string, code string, clientIP string, userAgent string) (*models.Auth, error)
+
+	// EnrollMFA creates new TOTP secret for given user
+	EnrollMFA(ctx context.Context, userID uint64) (*models.MFAEnrollment, error)
+
+	// EnableMFA enables MFA for given user after validating TOTP code and returns recovery codes
+	EnableMFA(ctx context.Context, userID uint64, code string) (*models.RecoveryCodes, error)
+
+	// DisableMFA disables MFA for given user after validating TOTP or recovery code
+	DisableMFA(ctx context.Context, userID uint64, code string) error
+
+	// RegenerateRecoveryCodes replaces MFA recovery codes for given user after validating TOTP or recovery code
+	RegenerateRecoveryCodes(ctx context.Context, userID uint64, code string) (*models.RecoveryCodes, error)
+
+	// GetActivity returns authentication events of given user
+	GetActivity(ctx context.Context, userID uint64, paginator *paging.Paginator) ([]*audit.Event, error)
+
+	// RequestAccountDeletion sends account deletion confirmation token to given user
+	RequestAccountDeletion(ctx context.Context, userID uint64) error
+
+	// ConfirmAccountDeletion deletes account of given user identified by account deletion token.
+	// Account can be restored by logging in until grace period expires
+	ConfirmAccountDeletion(ctx context.Context, userID uint64, accessToken string, token string, clientIP string, userAgent string) error
+
+	// PurgeDeletedAccounts removes personal information of accounts deleted before grace period
+	PurgeDeletedAccounts(ctx context.Context) error
+
+	// CreateAPIKey creates API key of given user limited to given scopes.
+	// Plain key is returned only once
+	CreateAPIKey(ctx context.Context, userID uint64, name string, scopes []string, expiresAt *time.Time, clientIP string, userAgent string) (*apikeys.CreatedAPIKey, error)
+
+	// GetAPIKeys returns API keys of given user
+	GetAPIKeys(ctx context.Context, userID uint64) ([]*apikeys.APIKey, error)
+
+	// RevokeAPIKey revokes API key of given user
+	RevokeAPIKey(ctx context.Context, userID uint64, id uint64, clientIP string, userAgent string) error
+
+	// StartExternalLogin starts login with given external identity provider
+	// and returns redirect to provider together with state token of the login
+	StartExternalLogin(ctx context.Context, provider string) (*models.ExternalLogin, error)
+
+	// CompleteExternalLogin logs in user identified by external identity provider using authorization code
+	// the provider redirected back with. User is registered when provider identity is not linked to any account.
+	// Registered user who can not login until email is confirmed gets nil Auth.
+	// Login started by StartProviderLink links provider identity to the account instead and returns nil Auth
+	CompleteExternalLogin(ctx context.Context, provider string, stateToken string, state string, code string, clientIP string, userAgent string) (*models.Auth, error)
+
+	// Reauthenticate confirms identity of logged in user with password or MFA code
+	// and returns short lived token required for changes of linked authentication providers
+	Reauthenticate(ctx context.Context, userID uint64, password string, code string, clientIP string, userAgent string) (*models.Reauthentication, error)
+
+	// GetProviders returns authentication providers linked to given user and identity providers available for linking
+	GetProviders(ctx context.Context, userID uint64) ([]*models.Provider, error)
+
+	// StartProviderLink starts login with given external identity provider which links provider identity to account
+	// of given user. Reauthentication token issued by Reauthenticate is required
+	StartProviderLink(ctx context.Context, userID uint64, provider string, reauthToken string) (*models.ExternalLogin, error)
+
+	// UnlinkProvider removes given external identity provider from account of given user.
+	// Reauthentication token issued by Reauthenticate is required, last provider of the account can not be removed
+	UnlinkProvider(ctx context.Context, userID uint64, provider string, reauthToken string, clientIP string, userAgent string) error
 }
 
 // NewAccountService creates AccountService Implementation
 func NewAccountService(
-	rolesService roles.RolesService,
+	rolesService roleServices.RolesService,
+	rolesAdminService roleServices.RolesAdminService,
 	usersService services.UsersService,
 	authService auth.AuthService,
 	tokensService tokens.TokensService,
-	jwt jwt.TokenAuth) AccountService {
+	mfaService mfa.MFAService,
+	apiKeysService apikeys.APIKeysService,
+	auditService audit.AuditService,
+	jwt jwt.TokenAuth,
+	notifier notify.Notifier,
+	lockout lockout.Lockout,
+	federation oidc.Federation,
+	cfg config.AppConfig,
+	logger log.Logger) AccountService {
 	return &accountService{
-		rolesService:  rolesService,
-		usersService:  usersService,
-		authService:   authService,
-		tokensService: tokensService,
-		jwt:           jwt,
+		rolesService:      rolesService,
+		rolesAdminService: rolesAdminService,
+		usersService:      usersService,
+		authService:       authService,
+		tokensService:     tokensService,
+		mfaService:        mfaService,
+		apiKeysService:    apiKeysService,
+		auditService:      auditService,
+		jwt:               jwt,
+		notifier:          notifier,
+		lockout:           lockout,
+		federation:        federation,
+		cfg:               cfg,
+		logger:            logger,
 	}
 }
 
 type accountService struct {
-	rolesService  roles.RolesService
-	usersService  services.UsersService
-	authService   auth.AuthService
-	tokensService tokens.TokensService
-	jwt           jwt.TokenAuth
+	rolesService      roleServices.RolesService
+	rolesAdminService roleServices.RolesAdminService
+	usersService      services.UsersService
+	authService       auth.AuthService
+	tokensService     tokens.TokensService
+	mfaService        mfa.MFAService
+	apiKeysService    apikeys.APIKeysService
+	auditService      audit.AuditService
+	jwt               jwt.TokenAuth
+	notifier          notify.Notifier
+	lockout           lockout.Lockout
+	federation        oidc.Federation
+	cfg               config.AppConfig
+	logger            log.Logger
 }
 
 // AccountService returns Interface implementation signature
@@ -69,39 +328,124 @@ func (svc *accountService) AccountService() string {
 	return "accountService"
 }
 
-func (svc *accountService) authenticate(ctx context.Context, userID uint64, roles ...string) (*models.Auth, error) {
-	// create access token
-	accessToken, err := svc.jwt.GenerateAccessToken(userID, roles...)
+// requestLogger returns request scoped logger when available
+func (svc *accountService) requestLogger(ctx context.Context) log.Logger {
+	if l, ok := log.FromContext(ctx); ok {
+		return l
+	}
+	return svc.logger
+}
+
+// recordEvent stores authentication event.
+// Failure to record event is logged, so it does not change outcome of the operation
+func (svc *accountService) recordEvent(ctx context.Context, event *audit.Event, err error) {
+	if recErr := svc.auditService.Record(ctx, event, err); recErr != nil {
+		svc.requestLogger(ctx).Error(recErr)
+	}
+}
+
+func (svc *accountService) authenticate(ctx context.Context, userID uint64, clientIP string, userAgent string, roles ...string) (*models.Auth, error) {
+	meta, err := (&tokens.RefreshTokenMeta{ClientIP: clientIP, UserAgent: userAgent}).Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	// create and store refresh token which starts new token family
+	token, err := svc.tokensService.CreateRefreshToken(ctx, userID, meta, svc.jwt.SessionLifetime(roles...))
 	if err != nil {
 		return nil, err
 	}
 
-	// create and store refresh token
-	token, err := svc.tokensService.CreateRefreshToken(ctx, userID, "")
+	return svc.issueTokens(userID, token, roles...)
+}
+
+// issueTokens generates access token and refresh token for given stored refresh token
+func (svc *accountService) issueTokens(userID uint64, token *tokens.Token, roles ...string) (*models.Auth, error) {
+	// create access token
+	accessToken, err := svc.jwt.GenerateAccessToken(userID, roles...)
 	if err != nil {
 		return nil, err
 	}
 
 	//generate refresh token
-	refreshToken, err := svc.jwt.GenerateRefreshToken(token.Token)
+	refreshToken, err := svc.jwt.GenerateRefreshToken(token.Token, token.ExpiresAt)
 	if err != nil {
 		return nil, err
 	}
 	return &models.Auth{AccessToken: accessToken, RefreshToken: refreshToken}, nil
 }
 
+// scope returns access token scope for given user, holding names of effective roles and granted permissions.
+// Users with unconfirmed email are handled according to configured login policy
+func (svc *accountService) scope(ctx context.Context, user *userModels.User) ([]string, error) {
+	// inherited roles are included, so AuthorizeRequest("User") accepts Admin token
+	roles, err := svc.rolesService.GetEffectiveRoles(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	scope := []string{jwt.ScopeAuthorized}
+	if !user.IsEmailConfirmed() {
+		switch svc.cfg.UnconfirmedLogin() {
+		case config.UnconfirmedLoginDeny:
+			return nil, ErrEmailNotConfirmed
+		case config.UnconfirmedLoginLimited:
+			scope = []string{jwt.ScopeUnconfirmed}
+		}
+	}
+
+	for _, role := range roles {
+		scope = append(scope, role.Name)
+	}
+
+	// permissions are granted only to fully authorized users
+	if scope[0] != jwt.ScopeAuthorized {
+		return scope, nil
+	}
+
+	permissions, err := svc.rolesService.GetUserPermissions(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(scope, permissions...), nil
+}
+
+// sendEmailConfirmation creates email confirmation token and sends it to user
+func (svc *accountService) sendEmailConfirmation(ctx context.Context, user *userModels.User) error {
+	token, err := svc.tokensService.CreateEmailConfirmToken(ctx, user.ID, "")
+	if err != nil {
+		return err
+	}
+
+	return svc.notifier.Send(ctx, &notify.Message{
+		Kind:    notify.KindEmailConfirmation,
+		To:      user.Email,
+		Subject: "Confirm your email",
+		Body:    fmt.Sprintf("Use the following token to confirm your email: %s", token.Token),
+		Data:    map[string]string{"token": token.Token},
+	})
+}
+
 // Register new user to system using email and password combination
-func (svc *accountService) Register(ctx context.Context, email string, password string, firstName string, lastName string, clientIP string) (*models.Auth, error) {
-	defaultRole := uint64(roles.UserRoleUser)
+func (svc *accountService) Register(ctx context.Context, email string, password string, firstName string, lastName string, clientIP string, userAgent string) (auth *models.Auth, err error) {
+	event := &audit.Event{Event: audit.EventRegister, ClientIP: clientIP, UserAgent: userAgent}
+	defer func() {
+		svc.recordEvent(ctx, event, err)
+	}()
+
+	defaultRole := uint64(roleModels.UserRoleUser)
 
 	// create user
 	user, err := svc.usersService.Create(ctx, firstName, lastName, email)
 	if err != nil {
 		return nil, apperror.New("ACCOUNT.000", ErrRegisterUser, err)
 	}
+	event.UserID = user.ID
 
 	//assign default role
-	if err := svc.rolesService.Assign(ctx, user.ID, defaultRole); err != nil {
+	err = svc.rolesAdminService.AssignUser(ctx, 0, &roleModels.Assignment{UserID: user.ID, RoleID: defaultRole}, clientIP, userAgent)
+	if err != nil {
 		return nil, apperror.New("ACCOUNT.001", ErrRegisterUser, err)
 	}
 
@@ -110,20 +454,23 @@ func (svc *accountService) Register(ctx context.Context, email string, password
 		return nil, apperror.New("ACCOUNT.002", ErrRegisterUser, err)
 	}
 
-	// get user roles
-	roles, err := svc.rolesService.GetByUserID(ctx, user.ID)
-	if err != nil {
-		return nil, apperror.New("ACCOUNT.003", ErrRegisterUser, err)
+	// send email confirmation token
+	if err = svc.sendEmailConfirmation(ctx, user); err != nil {
+		return nil, apperror.New("ACCOUNT.005", ErrRegisterUser, err)
 	}
 
-	// by default users do not have MFA enabled and access token should be authorized
-	rolesArr := []string{"Authorized"}
-	for _, role := range roles {
-		rolesArr = append(rolesArr, role.Name)
+	// get access token scope
+	rolesArr, err := svc.scope(ctx, user)
+	if err != nil {
+		if errors.Is(err, ErrEmailNotConfirmed) {
+			// user is registered, but can not login until email is confirmed
+			return nil, nil
+		}
+		return nil, apperror.New("ACCOUNT.003", ErrRegisterUser, err)
 	}
 
 	// authenticate user
-	auth, err := svc.authenticate(ctx, user.ID, rolesArr...)
+	auth, err = svc.authenticate(ctx, user.ID, clientIP, userAgent, rolesArr...)
 	if err != nil {
 		return nil, apperror.New("ACCOUNT.004", ErrRegisterUser, err)
 	}
@@ -132,65 +479,1082 @@ func (svc *accountService) Register(ctx context.Context, email string, password
 }
 
 // Login user to system using email and password combination
-func (svc *accountService) Login(ctx context.Context, email string, password string, clientIP string) (*models.Auth, error) {
+func (svc *accountService) Login(ctx context.Context, email string, password string, clientIP string, userAgent string) (auth *models.Auth, err error) {
+	event := &audit.Event{Event: audit.EventLogin, ClientIP: clientIP, UserAgent: userAgent}
+	defer func() {
+		// password was verified, but login is completed only after MFA step
+		if auth != nil && auth.MFAToken != "" {
+			event.Event = audit.EventMFAChallenge
+		}
+		svc.recordEvent(ctx, event, err)
+	}()
+
+	// reject clients which are locked out before doing any work
+	if err := svc.lockout.Check(ctx, 0, clientIP); err != nil {
+		return nil, apperror.New("ACCOUNT.015", ErrAccountLocked, err)
+	}
 
 	// get user by email
 	user, err := svc.usersService.GetByEmail(ctx, email)
+	if errors.Is(err, services.ErrUserNotExist) {
+		// deleted users can cancel deletion by logging in during grace period
+		user, err = svc.deletedUser(ctx, email)
+	}
+
+	if errors.Is(err, services.ErrUserNotExist) {
+		svc.registerLoginFailure(ctx, 0, clientIP)
+		return nil, apperror.New("ACCOUNT.011", ErrLoginUser, ErrInvalidCredentials)
+	}
 
 	if err != nil {
 		return nil, apperror.New("ACCOUNT.010", ErrLoginUser, err)
 	}
+	event.UserID = user.ID
+
+	if err := svc.lockout.Check(ctx, user.ID, ""); err != nil {
+		if errors.Is(err, lockout.ErrLocked) {
+			return nil, apperror.New("ACCOUNT.019", ErrAccountLocked, err)
+		}
+		return nil, apperror.New("ACCOUNT.017", ErrLoginUser, err)
+	}
 
 	// verify password
 	if err := svc.authService.AuthenticateLocal(ctx, user.ID, password); err != nil {
-		return nil, apperror.New("ACCOUNT.011", ErrLoginUser, err)
+		svc.registerLoginFailure(ctx, user.ID, clientIP)
+		return nil, apperror.New("ACCOUNT.011", ErrLoginUser, ErrInvalidCredentials)
 	}
 
-	// get user roles
-	roles, err := svc.rolesService.GetByUserID(ctx, user.ID)
+	// get access token scope
+	rolesArr, err := svc.scope(ctx, user)
 	if err != nil {
 		return nil, apperror.New("ACCOUNT.012", ErrLoginUser, err)
 	}
 
-	var rolesArr []string
-	for _, role := range roles {
-		rolesArr = append(rolesArr, role.Name)
+	mfaEnabled, err := svc.mfaService.IsEnabled(ctx, user.ID)
+	if err != nil {
+		return nil, apperror.New("ACCOUNT.013", ErrLoginUser, err)
+	}
+
+	// users with MFA enabled are authorized only after second step,
+	// so failed attempts are kept and deleted account is restored only when MFA code is verified
+	if mfaEnabled {
+		mfaToken, err := svc.jwt.GenerateMFAToken(user.ID)
+		if err != nil {
+			return nil, apperror.New("ACCOUNT.014", ErrLoginUser, err)
+		}
+		return &models.Auth{MFAToken: mfaToken}, nil
+	}
+
+	if user.IsDeleted() {
+		if err := svc.restoreAccount(ctx, user.ID, clientIP, userAgent); err != nil {
+			return nil, apperror.New("ACCOUNT.018", ErrLoginUser, err)
+		}
+	}
+
+	if err := svc.lockout.RegisterSuccess(ctx, user.ID); err != nil {
+		return nil, apperror.New("ACCOUNT.016", ErrLoginUser, err)
 	}
 
 	// authenticate user
-	return svc.authenticate(ctx, user.ID, rolesArr...)
+	return svc.authenticate(ctx, user.ID, clientIP, userAgent, rolesArr...)
+}
+
+// deletedUser returns deleted user with given email whose grace period did not expire
+func (svc *accountService) deletedUser(ctx context.Context, email string) (*userModels.User, error) {
+	user, err := svc.usersService.GetDeletedByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	return svc.restorableUser(user)
+}
+
+// deletedUserByID returns deleted user with given id whose grace period did not expire
+func (svc *accountService) deletedUserByID(ctx context.Context, userID uint64) (*userModels.User, error) {
+	user, err := svc.usersService.GetDeletedByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return svc.restorableUser(user)
+}
+
+// restorableUser returns given deleted user if its grace period did not expire
+func (svc *accountService) restorableUser(user *userModels.User) (*userModels.User, error) {
+	if time.Since(*user.DeletedAt) > svc.cfg.AccountDeletionGracePeriod() {
+		return nil, services.ErrUserNotExist
+	}
+	return user, nil
+}
+
+// restoreAccount cancels deletion of given user
+func (svc *accountService) restoreAccount(ctx context.Context, userID uint64, clientIP string, userAgent string) (err error) {
+	event := &audit.Event{Event: audit.EventAccountRestore, UserID: userID, ClientIP: clientIP, UserAgent: userAgent}
+	defer func() {
+		svc.recordEvent(ctx, event, err)
+	}()
+
+	return svc.usersService.Restore(ctx, userID)
+}
+
+// registerLoginFailure records failed login attempt.
+// Failure to record attempt is logged, so it does not hide authentication error from the caller
+func (svc *accountService) registerLoginFailure(ctx context.Context, userID uint64, clientIP string) {
+	// request transaction is rolled back on error response, so attempt is recorded outside of it
+	if err := svc.lockout.RegisterFailure(db.DetachTxContext(ctx), userID, clientIP); err != nil {
+		svc.requestLogger(ctx).Error(err)
+	}
 }
 
 // RefreshToken issues new Auth Tokens based on given refreshToken
-func (svc *accountService) RefreshToken(ctx context.Context, token string) (*models.Auth, error) {
+func (svc *accountService) RefreshToken(ctx context.Context, token string, clientIP string, userAgent string) (auth *models.Auth, err error) {
+	event := &audit.Event{Event: audit.EventRefresh, ClientIP: clientIP, UserAgent: userAgent}
+	defer func() {
+		svc.recordEvent(ctx, event, err)
+	}()
+
 	tokenObj, err := svc.tokensService.GetRefreshToken(ctx, token)
 	if err != nil {
 		return nil, apperror.New("ACCOUNT.020", ErrRefreshTokens, err)
 	}
+	event.UserID = tokenObj.UserID
+
+	// tokens issued to OAuth clients are limited to consented scope, so they are refreshed only by token endpoint
+	meta, err := tokenObj.RefreshTokenMeta()
+	if err != nil {
+		return nil, apperror.New("ACCOUNT.025", ErrRefreshTokens, err)
+	}
+
+	if meta.ClientID != "" {
+		return nil, apperror.New("ACCOUNT.026", ErrRefreshTokens, tokens.ErrWrongTkenType)
+	}
 
 	user, err := svc.usersService.GetByID(ctx, tokenObj.UserID)
 	if err != nil {
 		return nil, apperror.New("ACCOUNT.021", ErrRefreshTokens, err)
 	}
 
-	// get user roles
-	roles, err := svc.rolesService.GetByUserID(ctx, user.ID)
+	// get access token scope
+	rolesArr, err := svc.scope(ctx, user)
 	if err != nil {
 		return nil, apperror.New("ACCOUNT.022", ErrRefreshTokens, err)
 	}
 
-	var rolesArr []string
-	for _, role := range roles {
-		rolesArr = append(rolesArr, role.Name)
+	// exchange presented token for the new one within the same family
+	newToken, err := svc.tokensService.RotateRefreshToken(ctx, tokenObj, svc.jwt.SessionLifetime(rolesArr...), clientIP, userAgent)
+	if err != nil {
+		if errors.Is(err, tokens.ErrRefreshTokenReused) {
+			svc.revokeReusedToken(ctx, tokenObj)
+		}
+		return nil, apperror.New("ACCOUNT.023", ErrRefreshTokens, err)
+	}
+
+	// authenticate user
+	auth, err = svc.issueTokens(user.ID, newToken, rolesArr...)
+	if err != nil {
+		return nil, apperror.New("ACCOUNT.024", ErrRefreshTokens, err)
+	}
+
+	return auth, nil
+}
+
+// revokeReusedToken revokes whole family of reused refresh token and records security event.
+// Reused token indicates that token was stolen, so neither party can continue using the family
+func (svc *accountService) revokeReusedToken(ctx context.Context, token *tokens.Token) {
+	logger := svc.requestLogger(ctx)
+
+	meta, err := token.RefreshTokenMeta()
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	logger = logger.WithFields(log.Fields{
+		"event":   "refresh_token_reuse",
+		"user_id": token.UserID,
+		"token":   token.ID,
+		"family":  meta.Family,
+	})
+	logger.Warn("security-event")
+
+	// request transaction is rolled back on error response, so family is revoked outside of it
+	if err := svc.tokensService.RevokeRefreshTokenFamily(db.DetachTxContext(ctx), token.UserID, meta.Family); err != nil {
+		logger.Error(err)
+	}
+}
+
+// ForgotPassword issues password reset token and sends it to user with given email.
+// Missing users and message delivery failures are not reported so the caller can not tell whether email exists
+func (svc *accountService) ForgotPassword(ctx context.Context, email string) error {
+	user, err := svc.usersService.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotExist) {
+			return nil
+		}
+		return apperror.New("ACCOUNT.030", ErrForgotPassword, err)
+	}
+
+	// create single use password reset token
+	token, err := svc.tokensService.CreatePasswordRessetToken(ctx, user.ID, "")
+	if err != nil {
+		return apperror.New("ACCOUNT.031", ErrForgotPassword, err)
+	}
+
+	msg := &notify.Message{
+		Kind:    notify.KindPasswordReset,
+		To:      user.Email,
+		Subject: "Password reset",
+		Body:    fmt.Sprintf("Use the following token to reset your password: %s", token.Token),
+		Data:    map[string]string{"token": token.Token},
+	}
+
+	// failure is only logged, so response does not reveal that account with given email exists
+	if err := svc.notifier.Send(ctx, msg); err != nil {
+		svc.requestLogger(ctx).Error(apperror.New("ACCOUNT.032", ErrForgotPassword, err))
+	}
+
+	return nil
+}
+
+// ResetPassword sets new password for user identified by password reset token
+// and invalidates all user's refresh tokens
+func (svc *accountService) ResetPassword(ctx context.Context, token string, password string, clientIP string, userAgent string) (err error) {
+	event := &audit.Event{Event: audit.EventPasswordChange, ClientIP: clientIP, UserAgent: userAgent}
+	defer func() {
+		svc.recordEvent(ctx, event, err)
+	}()
+
+	tokenObj, err := svc.tokensService.GetPasswordRessetToken(ctx, token)
+	if err != nil {
+		return apperror.New("ACCOUNT.040", ErrResetPassword, err)
+	}
+	event.UserID = tokenObj.UserID
+
+	// password reset token can be used only once, so it is consumed before password is changed
+	// and only one of concurrent requests with the same token resets password
+	consumed, err := svc.tokensService.ConsumePasswordResetToken(ctx, tokenObj)
+	if err != nil {
+		return apperror.New("ACCOUNT.042", ErrResetPassword, err)
+	}
+
+	if !consumed {
+		return apperror.New("ACCOUNT.044", ErrResetPassword, ErrResetTokenUsed)
 	}
 
-	rolesArr = append(rolesArr, "Authorized")
+	if err := svc.authService.ResetLocal(ctx, tokenObj.UserID, password); err != nil {
+		return apperror.New("ACCOUNT.041", ErrResetPassword, err)
+	}
+
+	// logout user from all sessions
+	if err := svc.tokensService.DeleteRefreshTokens(ctx, tokenObj.UserID); err != nil {
+		return apperror.New("ACCOUNT.043", ErrResetPassword, err)
+	}
+
+	return nil
+}
+
+// ConfirmEmail confirms email of user identified by email confirmation token
+func (svc *accountService) ConfirmEmail(ctx context.Context, token string) error {
+	tokenObj, err := svc.tokensService.GetEmailConfirmToken(ctx, token)
+	if err != nil {
+		return apperror.New("ACCOUNT.050", ErrConfirmEmail, err)
+	}
 
-	// delete old token
+	if err := svc.usersService.ConfirmEmail(ctx, tokenObj.UserID); err != nil {
+		return apperror.New("ACCOUNT.051", ErrConfirmEmail, err)
+	}
+
+	// email confirmation token can be used only once
 	if err := svc.tokensService.Delete(ctx, tokenObj); err != nil {
-		return nil, apperror.New("ACCOUNT.023", ErrRefreshTokens, err)
+		return apperror.New("ACCOUNT.052", ErrConfirmEmail, err)
 	}
 
-	// authenticate user
-	return svc.authenticate(ctx, user.ID, rolesArr...)
+	return nil
+}
+
+// ResendEmailConfirmation sends new email confirmation token to user with given email.
+// Missing or already confirmed users are not reported
+func (svc *accountService) ResendEmailConfirmation(ctx context.Context, email string) error {
+	user, err := svc.usersService.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotExist) {
+			return nil
+		}
+		return apperror.New("ACCOUNT.060", ErrResendEmailConfirmation, err)
+	}
+
+	if user.IsEmailConfirmed() {
+		return nil
+	}
+
+	if err := svc.sendEmailConfirmation(ctx, user); err != nil {
+		return apperror.New("ACCOUNT.061", ErrResendEmailConfirmation, err)
+	}
+
+	return nil
+}
+
+// Logout terminates user session identified by given refresh token and revokes given access token
+func (svc *accountService) Logout(ctx context.Context, userID uint64, accessToken string, refreshToken string) error {
+	tokenObj, err := svc.tokensService.GetRefreshToken(ctx, refreshToken)
+	if err != nil {
+		return apperror.New("ACCOUNT.070", ErrLogout, err)
+	}
+
+	if tokenObj.UserID != userID {
+		return apperror.New("ACCOUNT.071", ErrLogout, ErrTokenOwner)
+	}
+
+	meta, err := tokenObj.RefreshTokenMeta()
+	if err != nil {
+		return apperror.New("ACCOUNT.072", ErrLogout, err)
+	}
+
+	// remove presented token together with tokens it was rotated from
+	if err := svc.tokensService.RevokeRefreshTokenFamily(ctx, userID, meta.Family); err != nil {
+		return apperror.New("ACCOUNT.073", ErrLogout, err)
+	}
+
+	if err := svc.jwt.RevokeAccessToken(accessToken); err != nil {
+		return apperror.New("ACCOUNT.074", ErrLogout, err)
+	}
+
+	return nil
+}
+
+// LogoutAll terminates all user sessions and revokes given access token
+func (svc *accountService) LogoutAll(ctx context.Context, userID uint64, accessToken string) error {
+	if err := svc.tokensService.DeleteByUserID(ctx, userID); err != nil {
+		return apperror.New("ACCOUNT.080", ErrLogout, err)
+	}
+
+	if err := svc.jwt.RevokeAccessToken(accessToken); err != nil {
+		return apperror.New("ACCOUNT.081", ErrLogout, err)
+	}
+
+	return nil
+}
+
+// GetSessions returns all active sessions of given user
+func (svc *accountService) GetSessions(ctx context.Context, userID uint64) ([]*models.Session, error) {
+	tokens, err := svc.tokensService.GetActiveRefreshTokens(ctx, userID)
+	if err != nil {
+		return nil, apperror.New("ACCOUNT.090", ErrFetchSessions, err)
+	}
+
+	sessions := make([]*models.Session, 0, len(tokens))
+	for _, t := range tokens {
+		meta, err := t.RefreshTokenMeta()
+		if err != nil {
+			return nil, apperror.New("ACCOUNT.091", ErrFetchSessions, err)
+		}
+
+		sessions = append(sessions, &models.Session{
+			ID:         meta.Family,
+			UserAgent:  meta.UserAgent,
+			ClientIP:   meta.ClientIP,
+			StartedAt:  meta.StartedAt,
+			LastUsedAt: meta.LastUsedAt,
+			ExpiresAt:  t.ExpiresAt,
+			ClientID:   meta.ClientID,
+		})
+	}
+
+	return sessions, nil
+}
+
+// DeleteSession terminates user session with given id
+func (svc *accountService) DeleteSession(ctx context.Context, userID uint64, sessionID string) error {
+	sessions, err := svc.GetSessions(ctx, userID)
+	if err != nil {
+		return apperror.New("ACCOUNT.100", ErrDeleteSession, err)
+	}
+
+	found := false
+	for _, session := range sessions {
+		if session.ID == sessionID {
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return apperror.New("ACCOUNT.101", ErrDeleteSession, ErrSessionNotExist)
+	}
+
+	if err := svc.tokensService.RevokeRefreshTokenFamily(ctx, userID, sessionID); err != nil {
+		return apperror.New("ACCOUNT.102", ErrDeleteSession, err)
+	}
+
+	return nil
+}
+
+// VerifyMFA completes login of user with MFA enabled using MFA token issued by Login and TOTP or recovery code
+func (svc *accountService) VerifyMFA(ctx context.Context, mfaToken string, code string, clientIP string, userAgent string) (auth *models.Auth, err error) {
+	event := &audit.Event{Event: audit.EventLogin, ClientIP: clientIP, UserAgent: userAgent, Meta: `{"mfa":true}`}
+	defer func() {
+		svc.recordEvent(ctx, event, err)
+	}()
+
+	userID, err := svc.jwt.VerifyMFAToken(mfaToken)
+	if err != nil {
+		return nil, apperror.New("ACCOUNT.110", ErrVerifyMFA, err)
+	}
+	event.UserID = userID
+
+	if err := svc.lockout.Check(ctx, userID, clientIP); err != nil {
+		return nil, apperror.New("ACCOUNT.116", ErrAccountLocked, err)
+	}
+
+	if err := svc.mfaService.Verify(ctx, userID, code); err != nil {
+		svc.registerLoginFailure(ctx, userID, clientIP)
+		return nil, apperror.New("ACCOUNT.111", ErrVerifyMFA, err)
+	}
+
+	if err := svc.lockout.RegisterSuccess(ctx, userID); err != nil {
+		return nil, apperror.New("ACCOUNT.117", ErrVerifyMFA, err)
+	}
+
+	// MFA token can be used only once
+	if err := svc.jwt.RevokeMFAToken(mfaToken); err != nil {
+		return nil, apperror.New("ACCOUNT.112", ErrVerifyMFA, err)
+	}
+
+	user, err := svc.usersService.GetByID(ctx, userID)
+	if errors.Is(err, services.ErrUserNotExist) {
+		// deleted users can cancel deletion by completing login during grace period
+		user, err = svc.deletedUserByID(ctx, userID)
+	}
+
+	if err != nil {
+		return nil, apperror.New("ACCOUNT.113", ErrVerifyMFA, err)
+	}
+
+	if user.IsDeleted() {
+		if err := svc.restoreAccount(ctx, user.ID, clientIP, userAgent); err != nil {
+			return nil, apperror.New("ACCOUNT.118", ErrVerifyMFA, err)
+		}
+	}
+
+	// get access token scope
+	rolesArr, err := svc.scope(ctx, user)
+	if err != nil {
+		return nil, apperror.New("ACCOUNT.114", ErrVerifyMFA, err)
+	}
+
+	// authenticate user
+	auth, err = svc.authenticate(ctx, user.ID, clientIP, userAgent, rolesArr...)
+	if err != nil {
+		return nil, apperror.New("ACCOUNT.115", ErrVerifyMFA, err)
+	}
+
+	return auth, nil
+}
+
+// EnrollMFA creates new TOTP secret for given user
+func (svc *accountService) EnrollMFA(ctx context.Context, userID uint64) (*models.MFAEnrollment, error) {
+	user, err := svc.usersService.GetByID(ctx, userID)
+	if err != nil {
+		return nil, apperror.New("ACCOUNT.120", ErrEnrollMFA, err)
+	}
+
+	enrollment, err := svc.mfaService.Enroll(ctx, user.ID, user.Email)
+	if err != nil {
+		return nil, apperror.New("ACCOUNT.121", ErrEnrollMFA, err)
+	}
+
+	return &models.MFAEnrollment{Secret: enrollment.Secret, URL: enrollment.URL}, nil
+}
+
+// EnableMFA enables MFA for given user after validating TOTP code and returns recovery codes
+func (svc *accountService) EnableMFA(ctx context.Context, userID uint64, code string) (*models.RecoveryCodes, error) {
+	codes, err := svc.mfaService.Enable(ctx, userID, code)
+	if err != nil {
+		return nil, apperror.New("ACCOUNT.130", ErrEnableMFA, err)
+	}
+
+	return &models.RecoveryCodes{Codes: codes}, nil
+}
+
+// DisableMFA disables MFA for given user after validating TOTP or recovery code
+func (svc *accountService) DisableMFA(ctx context.Context, userID uint64, code string) error {
+	if err := svc.mfaService.Disable(ctx, userID, code); err != nil {
+		return apperror.New("ACCOUNT.140", ErrDisableMFA, err)
+	}
+
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces MFA recovery codes for given user after validating TOTP or recovery code
+func (svc *accountService) RegenerateRecoveryCodes(ctx context.Context, userID uint64, code string) (*models.RecoveryCodes, error) {
+	codes, err := svc.mfaService.RegenerateRecoveryCodes(ctx, userID, code)
+	if err != nil {
+		return nil, apperror.New("ACCOUNT.150", ErrRecoveryCodes, err)
+	}
+
+	return &models.RecoveryCodes{Codes: codes}, nil
+}
+
+// GetActivity returns authentication events of given user
+func (svc *accountService) GetActivity(ctx context.Context, userID uint64, paginator *paging.Paginator) ([]*audit.Event, error) {
+	events, err := svc.auditService.Find(ctx, &audit.EventFilter{UserID: userID}, paginator)
+	if err != nil {
+		return nil, apperror.New("ACCOUNT.160", ErrFetchActivity, err)
+	}
+
+	return events, nil
+}
+
+// RequestAccountDeletion sends account deletion confirmation token to given user
+func (svc *accountService) RequestAccountDeletion(ctx context.Context, userID uint64) error {
+	user, err := svc.usersService.GetByID(ctx, userID)
+	if err != nil {
+		return apperror.New("ACCOUNT.170", ErrRequestAccountDeletion, err)
+	}
+
+	token, err := svc.tokensService.CreateDeleteAccountToken(ctx, user.ID, "")
+	if err != nil {
+		return apperror.New("ACCOUNT.171", ErrRequestAccountDeletion, err)
+	}
+
+	msg := &notify.Message{
+		Kind:    notify.KindAccountDeletion,
+		To:      user.Email,
+		Subject: "Account deletion",
+		Body:    fmt.Sprintf("Use the following token to confirm deletion of your account: %s", token.Token),
+		Data:    map[string]string{"token": token.Token},
+	}
+
+	if err := svc.notifier.Send(ctx, msg); err != nil {
+		return apperror.New("ACCOUNT.172", ErrRequestAccountDeletion, err)
+	}
+
+	return nil
+}
+
+// ConfirmAccountDeletion deletes account of given user identified by account deletion token.
+// Account can be restored by logging in until grace period expires
+func (svc *accountService) ConfirmAccountDeletion(ctx context.Context, userID uint64, accessToken string, token string, clientIP string, userAgent string) (err error) {
+	event := &audit.Event{Event: audit.EventAccountDelete, UserID: userID, ClientIP: clientIP, UserAgent: userAgent}
+	defer func() {
+		svc.recordEvent(ctx, event, err)
+	}()
+
+	tokenObj, err := svc.tokensService.GetDeleteAccountToken(ctx, token)
+	if err != nil {
+		return apperror.New("ACCOUNT.180", ErrDeleteAccount, err)
+	}
+
+	if tokenObj.UserID != userID {
+		return apperror.New("ACCOUNT.181", ErrDeleteAccount, ErrTokenOwner)
+	}
+
+	if err := svc.usersService.SoftDelete(ctx, userID); err != nil {
+		return apperror.New("ACCOUNT.182", ErrDeleteAccount, err)
+	}
+
+	// terminate all sessions, account deletion token is removed as well
+	if err := svc.tokensService.DeleteByUserID(ctx, userID); err != nil {
+		return apperror.New("ACCOUNT.183", ErrDeleteAccount, err)
+	}
+
+	providers, err := svc.authService.GetByUserID(ctx, userID)
+	if err != nil {
+		return apperror.New("ACCOUNT.184", ErrDeleteAccount, err)
+	}
+
+	// password is kept until account is anonymized, so user can cancel deletion by logging in
+	for _, provider := range providers {
+		if provider == auth.AuthLocal {
+			continue
+		}
+		if err := svc.authService.Delete(ctx, userID, provider); err != nil {
+			return apperror.New("ACCOUNT.185", ErrDeleteAccount, err)
+		}
+	}
+
+	if err := svc.apiKeysService.RemoveAll(ctx, userID); err != nil {
+		return apperror.New("ACCOUNT.187", ErrDeleteAccount, err)
+	}
+
+	if err := svc.jwt.RevokeAccessToken(accessToken); err != nil {
+		return apperror.New("ACCOUNT.186", ErrDeleteAccount, err)
+	}
+
+	return nil
+}
+
+// PurgeDeletedAccounts removes personal information of accounts deleted before grace period
+func (svc *accountService) PurgeDeletedAccounts(ctx context.Context) error {
+	users, err := svc.usersService.GetDeletedBefore(ctx, time.Now().Add(-svc.cfg.AccountDeletionGracePeriod()))
+	if err != nil {
+		return apperror.New("ACCOUNT.190", ErrPurgeAccounts, err)
+	}
+
+	for _, user := range users {
+		if err := svc.anonymizeAccount(ctx, user.ID); err != nil {
+			return apperror.New("ACCOUNT.191", ErrPurgeAccounts, err)
+		}
+	}
+
+	return nil
+}
+
+// anonymizeAccount removes credentials and personal information of deleted user
+func (svc *accountService) anonymizeAccount(ctx context.Context, userID uint64) (err error) {
+	event := &audit.Event{Event: audit.EventAccountAnonymize, UserID: userID}
+	defer func() {
+		svc.recordEvent(ctx, event, err)
+	}()
+
+	if err := svc.tokensService.DeleteByUserID(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := svc.authService.DeleteAll(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := svc.mfaService.Remove(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := svc.apiKeysService.RemoveAll(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := svc.auditService.Anonymize(ctx, userID); err != nil {
+		return err
+	}
+
+	return svc.usersService.Anonymize(ctx, userID)
+}
+
+// CreateAPIKey creates API key of given user limited to given scopes.
+// Plain key is returned only once
+func (svc *accountService) CreateAPIKey(ctx context.Context, userID uint64, name string, scopes []string, expiresAt *time.Time, clientIP string, userAgent string) (key *apikeys.CreatedAPIKey, err error) {
+	event := &audit.Event{Event: audit.EventAPIKeyCreate, UserID: userID, ClientIP: clientIP, UserAgent: userAgent}
+	defer func() {
+		svc.recordEvent(ctx, event, err)
+	}()
+
+	key, err = svc.apiKeysService.Create(ctx, userID, name, scopes, expiresAt)
+	if err != nil {
+		return nil, apperror.New("ACCOUNT.200", ErrCreateAPIKey, err)
+	}
+
+	// plain key is never recorded, prefix is enough to identify the key
+	if metaErr := event.SetMeta(map[string]interface{}{"id": key.ID, "prefix": key.Prefix, "scopes": key.Scopes}); metaErr != nil {
+		svc.requestLogger(ctx).Error(metaErr)
+	}
+
+	return key, nil
+}
+
+// GetAPIKeys returns API keys of given user
+func (svc *accountService) GetAPIKeys(ctx context.Context, userID uint64) ([]*apikeys.APIKey, error) {
+	keys, err := svc.apiKeysService.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, apperror.New("ACCOUNT.210", ErrFetchAPIKeys, err)
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey revokes API key of given user
+func (svc *accountService) RevokeAPIKey(ctx context.Context, userID uint64, id uint64, clientIP string, userAgent string) (err error) {
+	event := &audit.Event{Event: audit.EventAPIKeyRevoke, UserID: userID, ClientIP: clientIP, UserAgent: userAgent}
+	if metaErr := event.SetMeta(map[string]interface{}{"id": id}); metaErr != nil {
+		svc.requestLogger(ctx).Error(metaErr)
+	}
+	defer func() {
+		svc.recordEvent(ctx, event, err)
+	}()
+
+	if err := svc.apiKeysService.Revoke(ctx, userID, id); err != nil {
+		return apperror.New("ACCOUNT.220", ErrRevokeAPIKey, err)
+	}
+
+	return nil
+}
+
+// StartExternalLogin starts login with given external identity provider
+// and returns redirect to provider together with state token of the login
+func (svc *accountService) StartExternalLogin(ctx context.Context, provider string) (*models.ExternalLogin, error) {
+	login, err := svc.startExternalLogin(ctx, provider, map[string]string{})
+	if err != nil {
+		return nil, apperror.New("ACCOUNT.230", ErrStartExternalLogin, err)
+	}
+	return login, nil
+}
+
+// startExternalLogin returns redirect to given identity provider and state token holding given values
+func (svc *accountService) startExternalLogin(ctx context.Context, provider string, values map[string]string) (*models.ExternalLogin, error) {
+	values["provider"] = provider
+
+	// state protects callback against CSRF, nonce binds ID token to this login and verifier is PKCE secret
+	for _, key := range []string{"state", "nonce", "verifier"} {
+		value, err := randomString(32)
+		if err != nil {
+			return nil, err
+		}
+		values[key] = value
+	}
+
+	redirectURL, err := svc.federation.AuthorizationURL(ctx, provider, values["state"], values["nonce"], values["verifier"])
+	if err != nil {
+		return nil, err
+	}
+
+	stateToken, err := svc.jwt.GenerateStateToken(values, externalLoginLifetime)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ExternalLogin{RedirectURL: redirectURL, StateToken: stateToken}, nil
+}
+
+// CompleteExternalLogin logs in user identified by external identity provider using authorization code
+// the provider redirected back with. User is registered when provider identity is not linked to any account
+func (svc *accountService) CompleteExternalLogin(ctx context.Context, provider string, stateToken string, state string, code string, clientIP string, userAgent string) (auth *models.Auth, err error) {
+	event := &audit.Event{Event: audit.EventLogin, ClientIP: clientIP, UserAgent: userAgent}
+	if metaErr := event.SetMeta(map[string]interface{}{"provider": provider}); metaErr != nil {
+		svc.requestLogger(ctx).Error(metaErr)
+	}
+	defer func() {
+		// identity was verified, but login is completed only after MFA step
+		if auth != nil && auth.MFAToken != "" {
+			event.Event = audit.EventMFAChallenge
+		}
+		svc.recordEvent(ctx, event, err)
+	}()
+
+	values, err := svc.jwt.VerifyStateToken(stateToken)
+	if err != nil {
+		return nil, apperror.New("ACCOUNT.240", ErrExternalLogin, err)
+	}
+
+	if values["provider"] != provider || state == "" || subtle.ConstantTimeCompare([]byte(values["state"]), []byte(state)) != 1 {
+		return nil, apperror.New("ACCOUNT.241", ErrExternalLogin, ErrExternalLoginState)
+	}
+
+	identity, err := svc.federation.Exchange(ctx, provider, code, values["verifier"], values["nonce"])
+	if err != nil {
+		return nil, apperror.New("ACCOUNT.242", ErrExternalLogin, err)
+	}
+
+	// login started by StartProviderLink links identity to account of the user who started it
+	if values["link"] != "" {
+		event.Event = audit.EventProviderLink
+		event.UserID, err = strconv.ParseUint(values["link"], 10, 64)
+		if err != nil {
+			return nil, apperror.New("ACCOUNT.248", ErrLinkProvider, err)
+		}
+
+		if err = svc.linkProvider(ctx, event.UserID, identity); err != nil {
+			return nil, apperror.New("ACCOUNT.249", ErrLinkProvider, err)
+		}
+		return nil, nil
+	}
+
+	user, registered, err := svc.externalUser(ctx, identity, clientIP, userAgent)
+	if err != nil {
+		return nil, apperror.New("ACCOUNT.243", ErrExternalLogin, err)
+	}
+	event.UserID = user.ID
+
+	// get access token scope
+	rolesArr, err := svc.scope(ctx, user)
+	if err != nil {
+		if registered && errors.Is(err, ErrEmailNotConfirmed) {
+			// user is registered, but can not login until email is confirmed
+			return nil, nil
+		}
+		return nil, apperror.New("ACCOUNT.244", ErrExternalLogin, err)
+	}
+
+	mfaEnabled, err := svc.mfaService.IsEnabled(ctx, user.ID)
+	if err != nil {
+		return nil, apperror.New("ACCOUNT.245", ErrExternalLogin, err)
+	}
+
+	// identity provider replaces password, so users with MFA enabled still have to complete second step
+	if mfaEnabled {
+		mfaToken, err := svc.jwt.GenerateMFAToken(user.ID)
+		if err != nil {
+			return nil, apperror.New("ACCOUNT.246", ErrExternalLogin, err)
+		}
+		return &models.Auth{MFAToken: mfaToken}, nil
+	}
+
+	// authenticate user
+	auth, err = svc.authenticate(ctx, user.ID, clientIP, userAgent, rolesArr...)
+	if err != nil {
+		return nil, apperror.New("ACCOUNT.247", ErrExternalLogin, err)
+	}
+
+	return auth, nil
+}
+
+// externalUser returns user linked to given external identity, user is registered when identity is not linked yet.
+// Returns true if user was registered
+func (svc *accountService) externalUser(ctx context.Context, identity *oidc.Identity, clientIP string, userAgent string) (*userModels.User, bool, error) {
+	userID, err := svc.authService.AuthenticateSocial(ctx, identity.Subject, identity.Provider)
+	if errors.Is(err, auth.ErrSocialAuthNotExist) {
+		user, err := svc.registerExternalUser(ctx, identity, clientIP, userAgent)
+		return user, true, err
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	user, err := svc.usersService.GetByID(ctx, userID)
+	return user, false, err
+}
+
+// registerExternalUser registers user with given external identity and links the identity to the account.
+// Existing accounts are never linked automatically, as email ownership at provider does not prove account ownership
+func (svc *accountService) registerExternalUser(ctx context.Context, identity *oidc.Identity, clientIP string, userAgent string) (user *userModels.User, err error) {
+	event := &audit.Event{Event: audit.EventRegister, ClientIP: clientIP, UserAgent: userAgent}
+	if metaErr := event.SetMeta(map[string]interface{}{"provider": identity.Provider}); metaErr != nil {
+		svc.requestLogger(ctx).Error(metaErr)
+	}
+	defer func() {
+		svc.recordEvent(ctx, event, err)
+	}()
+
+	if identity.Email == "" {
+		return nil, ErrExternalEmailMissing
+	}
+
+	// deleted accounts keep their email until they are anonymized
+	_, err = svc.usersService.GetByEmail(ctx, identity.Email)
+	if err == nil {
+		return nil, ErrExternalAccountExists
+	}
+	if errors.Is(err, services.ErrUserNotExist) {
+		_, err = svc.usersService.GetDeletedByEmail(ctx, identity.Email)
+		if err == nil {
+			return nil, ErrExternalAccountExists
+		}
+	}
+	if !errors.Is(err, services.ErrUserNotExist) {
+		return nil, err
+	}
+
+	user, err = svc.usersService.Create(ctx, identity.FirstName, identity.LastName, identity.Email)
+	if err != nil {
+		return nil, err
+	}
+	event.UserID = user.ID
+
+	defaultRole := uint64(roleModels.UserRoleUser)
+
+	//assign default role
+	err = svc.rolesAdminService.AssignUser(ctx, 0, &roleModels.Assignment{UserID: user.ID, RoleID: defaultRole}, clientIP, userAgent)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = svc.authService.CreateSocial(ctx, user.ID, identity.Subject, identity.Provider); err != nil {
+		return nil, err
+	}
+
+	// email verified by provider does not need to be confirmed again
+	if !identity.EmailVerified {
+		if err = svc.sendEmailConfirmation(ctx, user); err != nil {
+			return nil, err
+		}
+		return user, nil
+	}
+
+	if err = svc.usersService.ConfirmEmail(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	return svc.usersService.GetByID(ctx, user.ID)
+}
+
+// linkProvider links given external identity to account of given user.
+// Identity can be linked only to one account and each provider only once per account
+func (svc *accountService) linkProvider(ctx context.Context, userID uint64, identity *oidc.Identity) error {
+	_, err := svc.authService.AuthenticateSocial(ctx, identity.Subject, identity.Provider)
+	if err == nil {
+		return ErrProviderLinked
+	}
+	if !errors.Is(err, auth.ErrSocialAuthNotExist) {
+		return err
+	}
+
+	providers, err := svc.authService.GetByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, provider := range providers {
+		if provider == identity.Provider {
+			return ErrProviderLinked
+		}
+	}
+
+	return svc.authService.CreateSocial(ctx, userID, identity.Subject, identity.Provider)
+}
+
+// Reauthenticate confirms identity of logged in user with password or MFA code
+// and returns short lived token required for changes of linked authentication providers
+func (svc *accountService) Reauthenticate(ctx context.Context, userID uint64, password string, code string, clientIP string, userAgent string) (reauth *models.Reauthentication, err error) {
+	event := &audit.Event{Event: audit.EventReauthenticate, UserID: userID, ClientIP: clientIP, UserAgent: userAgent}
+	defer func() {
+		svc.recordEvent(ctx, event, err)
+	}()
+
+	// reauthentication can be used for guessing password, so it is limited as login
+	if err := svc.lockout.Check(ctx, userID, clientIP); err != nil {
+		return nil, apperror.New("ACCOUNT.250", ErrAccountLocked, err)
+	}
+
+	if password != "" {
+		err = svc.authService.AuthenticateLocal(ctx, userID, password)
+	} else {
+		if metaErr := event.SetMeta(map[string]interface{}{"mfa": true}); metaErr != nil {
+			svc.requestLogger(ctx).Error(metaErr)
+		}
+		err = svc.mfaService.Verify(ctx, userID, code)
+	}
+
+	if err != nil {
+		svc.registerLoginFailure(ctx, userID, clientIP)
+		return nil, apperror.New("ACCOUNT.251", ErrReauthenticate, err)
+	}
+
+	if err := svc.lockout.RegisterSuccess(ctx, userID); err != nil {
+		return nil, apperror.New("ACCOUNT.252", ErrReauthenticate, err)
+	}
+
+	token, err := svc.jwt.GenerateStateToken(map[string]string{
+		"purpose": reauthPurpose,
+		"userId":  strconv.FormatUint(userID, 10),
+	}, reauthLifetime)
+	if err != nil {
+		return nil, apperror.New("ACCOUNT.253", ErrReauthenticate, err)
+	}
+
+	return &models.Reauthentication{ReauthToken: token}, nil
+}
+
+// verifyReauthentication checks that given reauthentication token was recently issued to given user
+func (svc *accountService) verifyReauthentication(userID uint64, reauthToken string) error {
+	values, err := svc.jwt.VerifyStateToken(reauthToken)
+	if err != nil {
+		return ErrReauthenticationRequired
+	}
+
+	if values["purpose"] != reauthPurpose || values["userId"] != strconv.FormatUint(userID, 10) {
+		return ErrReauthenticationRequired
+	}
+	return nil
+}
+
+// GetProviders returns authentication providers linked to given user and identity providers available for linking
+func (svc *accountService) GetProviders(ctx context.Context, userID uint64) ([]*models.Provider, error) {
+	linked, err := svc.authService.GetProviders(ctx, userID)
+	if err != nil {
+		return nil, apperror.New("ACCOUNT.260", ErrFetchProviders, err)
+	}
+
+	providers := make([]*models.Provider, 0, len(linked))
+	isLinked := map[string]bool{}
+	for _, p := range linked {
+		linkedAt := p.CreatedAt
+		providers = append(providers, &models.Provider{Name: p.Provider, Linked: true, LinkedAt: &linkedAt})
+		isLinked[p.Provider] = true
+	}
+
+	for _, name := range svc.federation.Providers() {
+		if !isLinked[name] {
+			providers = append(providers, &models.Provider{Name: name})
+		}
+	}
+
+	return providers, nil
+}
+
+// StartProviderLink starts login with given external identity provider which links provider identity to account
+// of given user. Reauthentication token issued by Reauthenticate is required
+func (svc *accountService) StartProviderLink(ctx context.Context, userID uint64, provider string, reauthToken string) (*models.ExternalLogin, error) {
+	if err := svc.verifyReauthentication(userID, reauthToken); err != nil {
+		return nil, apperror.New("ACCOUNT.270", ErrLinkProvider, err)
+	}
+
+	providers, err := svc.authService.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, apperror.New("ACCOUNT.271", ErrLinkProvider, err)
+	}
+
+	for _, p := range providers {
+		if p == provider {
+			return nil, apperror.New("ACCOUNT.272", ErrLinkProvider, ErrProviderLinked)
+		}
+	}
+
+	login, err := svc.startExternalLogin(ctx, provider, map[string]string{"link": strconv.FormatUint(userID, 10)})
+	if err != nil {
+		return nil, apperror.New("ACCOUNT.273", ErrLinkProvider, err)
+	}
+
+	return login, nil
+}
+
+// UnlinkProvider removes given external identity provider from account of given user.
+// Reauthentication token issued by Reauthenticate is required, last provider of the account can not be removed
+func (svc *accountService) UnlinkProvider(ctx context.Context, userID uint64, provider string, reauthToken string, clientIP string, userAgent string) (err error) {
+	event := &audit.Event{Event: audit.EventProviderUnlink, UserID: userID, ClientIP: clientIP, UserAgent: userAgent}
+	if metaErr := event.SetMeta(map[string]interface{}{"provider": provider}); metaErr != nil {
+		svc.requestLogger(ctx).Error(metaErr)
+	}
+	defer func() {
+		svc.recordEvent(ctx, event, err)
+	}()
+
+	if err := svc.verifyReauthentication(userID, reauthToken); err != nil {
+		return apperror.New("ACCOUNT.280", ErrUnlinkProvider, err)
+	}
+
+	// password is changed by password reset, it is not unlinked
+	if provider == auth.AuthLocal {
+		return apperror.New("ACCOUNT.281", ErrUnlinkProvider, auth.ErrUnsupportedProvider)
+	}
+
+	// providers stay locked until request transaction ends, so concurrent requests can not unlink remaining ones
+	providers, err := svc.authService.LockByUserID(ctx, userID)
+	if err != nil {
+		return apperror.New("ACCOUNT.282", ErrUnlinkProvider, err)
+	}
+
+	linked := false
+	for _, p := range providers {
+		linked = linked || p == provider
+	}
+
+	if !linked {
+		return apperror.New("ACCOUNT.283", ErrUnlinkProvider, ErrProviderNotLinked)
+	}
+
+	// user would not be able to login anymore
+	if len(providers) == 1 {
+		return apperror.New("ACCOUNT.284", ErrUnlinkProvider, ErrLastCredential)
+	}
+
+	if err := svc.authService.Delete(ctx, userID, provider); err != nil {
+		return apperror.New("ACCOUNT.285", ErrUnlinkProvider, err)
+	}
+
+	return nil
+}
+
+// randomString returns URL safe random string generated from given number of random bytes
+func randomString(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
 }
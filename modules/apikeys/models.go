@@ -0,0 +1,28 @@
+package apikeys
+
+import "time"
+
+// APIKey model holds hashed API key of a user.
+// Plain key is shown only once when key is created
+type APIKey struct {
+	ID         uint64     `json:"id"`
+	UserID     uint64     `json:"-"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	KeyHash    string     `json:"-"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  time.Time  `json:"expiresAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+// IsExpired checks if API key expired
+func (k *APIKey) IsExpired() bool {
+	return !time.Now().Before(k.ExpiresAt)
+}
+
+// CreatedAPIKey holds newly created API key together with its plain value
+type CreatedAPIKey struct {
+	*APIKey
+	Key string `json:"key"`
+}
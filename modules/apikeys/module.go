@@ -0,0 +1,38 @@
+package apikeys
+
+import (
+	"api/modules/roles"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// Module -
+type Module struct {
+}
+
+// NewModule creates new API keys Module instance
+func NewModule() *Module {
+	return &Module{}
+}
+
+func (m *Module) ProvideImports() []flow.Provider {
+	return []flow.Provider{
+		flow.NewProvider(NewAPIKeysRepository),
+	}
+}
+
+func (m *Module) ProvideExports() []flow.Provider {
+	return []flow.Provider{
+		flow.NewProvider(NewAPIKeysService),
+	}
+}
+
+func (m *Module) ProvideModules() []flow.Provider {
+	return []flow.Provider{
+		flow.NewProvider(roles.NewModule),
+	}
+}
+
+func (m *Module) ProvideRouters() []flow.Provider {
+	return []flow.Provider{}
+}
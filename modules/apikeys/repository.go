@@ -0,0 +1,250 @@
+package apikeys
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"api/providers/db"
+)
+
+// APIKeysRepository interface
+type APIKeysRepository interface {
+	// APIKeysRepository interface implementation signature
+	APIKeysRepository() string
+
+	// GetByUserID returns API keys of given user
+	GetByUserID(ctx context.Context, userID uint64) ([]*APIKey, error)
+
+	// GetByKeyHash returns API key with given hash.
+	// Keys of deleted users are not returned
+	GetByKeyHash(ctx context.Context, keyHash string) (*APIKey, error)
+
+	// Create stores given API key
+	Create(ctx context.Context, key *APIKey) error
+
+	// UpdateLastUsedAt sets last use time of API key with given id
+	UpdateLastUsedAt(ctx context.Context, id uint64, lastUsedAt time.Time) error
+
+	// Delete removes API key with given id owned by given user.
+	// It returns false when there is no such key
+	Delete(ctx context.Context, userID uint64, id uint64) (bool, error)
+
+	// DeleteByKeyHash removes API key with given hash.
+	// It returns false when there is no such key
+	DeleteByKeyHash(ctx context.Context, keyHash string) (bool, error)
+
+	// DeleteByUserID removes all API keys of given user
+	DeleteByUserID(ctx context.Context, userID uint64) error
+}
+
+// NewAPIKeysRepository creates APIKeysRepository interface implementation
+func NewAPIKeysRepository(store db.Store) APIKeysRepository {
+	return &apiKeysRepository{
+		store: store,
+	}
+}
+
+type apiKeysRepository struct {
+	store db.Store
+}
+
+func (r *apiKeysRepository) APIKeysRepository() string {
+	return "apiKeysRepository"
+}
+
+func (r *apiKeysRepository) getTx(ctx context.Context) (*sql.Tx, bool, error) {
+	var err error
+	// get transaction from context
+	tx, ok := db.TxFromContext(ctx)
+	if !ok {
+		// create new transaction
+		tx, err = r.store.Begin()
+		if err != nil {
+			return nil, false, err
+		}
+		return tx, true, nil
+	}
+	return tx, false, nil
+}
+
+func (apiKeysRepository) closeTx(tx *sql.Tx, shouldCommit bool, hasError bool) {
+	if shouldCommit {
+		if hasError {
+			tx.Rollback()
+			return
+		}
+		tx.Commit()
+	}
+}
+
+// GetByUserID returns API keys of given user
+func (r *apiKeysRepository) GetByUserID(ctx context.Context, userID uint64) ([]*APIKey, error) {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := "SELECT id, user_id, name, prefix, key_hash, scopes, expires_at, last_used_at, created_at FROM api_keys WHERE user_id = ? ORDER BY id"
+
+	keys := make([]*APIKey, 0)
+
+	// execute query statement
+	rows, err := tx.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	// loop over results
+	for rows.Next() {
+		var scopes string
+		model := new(APIKey)
+		// scan row to model
+		if err = rows.Scan(&model.ID, &model.UserID, &model.Name, &model.Prefix, &model.KeyHash, &scopes, &model.ExpiresAt, &model.LastUsedAt, &model.CreatedAt); err != nil {
+			return nil, err
+		}
+		model.Scopes = strings.Fields(scopes)
+
+		keys = append(keys, model)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// GetByKeyHash returns API key with given hash.
+// Keys of deleted users are not returned
+func (r *apiKeysRepository) GetByKeyHash(ctx context.Context, keyHash string) (*APIKey, error) {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := `
+		SELECT 
+			k.id, k.user_id, k.name, k.prefix, k.key_hash, k.scopes, k.expires_at, k.last_used_at, k.created_at 
+		FROM 
+			api_keys k 
+			INNER JOIN users u ON u.id = k.user_id 
+		WHERE 
+			k.key_hash = ? AND u.deleted_at IS NULL`
+
+	// create empty model object
+	var scopes string
+	model := new(APIKey)
+
+	// execute query statement and scan row to model
+	err = tx.QueryRow(query, keyHash).Scan(&model.ID, &model.UserID, &model.Name, &model.Prefix, &model.KeyHash, &scopes, &model.ExpiresAt, &model.LastUsedAt, &model.CreatedAt)
+
+	if err != nil && err == sql.ErrNoRows {
+		err = nil
+		return nil, nil
+	}
+	model.Scopes = strings.Fields(scopes)
+
+	return model, err
+}
+
+// Create stores given API key
+func (r *apiKeysRepository) Create(ctx context.Context, key *APIKey) error {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := "INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, expires_at) VALUES(?,?,?,?,?,?)"
+
+	result, err := tx.Exec(query, key.UserID, key.Name, key.Prefix, key.KeyHash, strings.Join(key.Scopes, " "), key.ExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	key.ID = uint64(lastID)
+	key.CreatedAt = time.Now()
+
+	return err
+}
+
+// UpdateLastUsedAt sets last use time of API key with given id
+func (r *apiKeysRepository) UpdateLastUsedAt(ctx context.Context, id uint64, lastUsedAt time.Time) error {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := "UPDATE api_keys SET last_used_at = ? WHERE id = ?"
+	_, err = tx.Exec(query, lastUsedAt, id)
+	return err
+}
+
+// Delete removes API key with given id owned by given user.
+// It returns false when there is no such key
+func (r *apiKeysRepository) Delete(ctx context.Context, userID uint64, id uint64) (bool, error) {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := "DELETE FROM api_keys WHERE id = ? AND user_id = ?"
+	result, err := tx.Exec(query, id, userID)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// DeleteByKeyHash removes API key with given hash.
+// It returns false when there is no such key
+func (r *apiKeysRepository) DeleteByKeyHash(ctx context.Context, keyHash string) (bool, error) {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := "DELETE FROM api_keys WHERE key_hash = ?"
+	result, err := tx.Exec(query, keyHash)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// DeleteByUserID removes all API keys of given user
+func (r *apiKeysRepository) DeleteByUserID(ctx context.Context, userID uint64) error {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := "DELETE FROM api_keys WHERE user_id = ?"
+	_, err = tx.Exec(query, userID)
+	return err
+}
@@ -0,0 +1,287 @@
+package apikeys
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	roleServices "api/modules/roles/services"
+	"api/pkg/apperror"
+	"api/providers/config"
+	"api/providers/jwt"
+)
+
+const (
+	// keyPrefix is prepended to generated keys, so leaked keys are easy to recognize
+	keyPrefix = "ak_"
+
+	// keyBytes is number of random bytes in generated key
+	keyBytes = 32
+
+	// displayPrefixLength is number of leading key characters stored in plain text to identify the key
+	displayPrefixLength = 11
+
+	// lastUsedPrecision is minimal time between updates of key last use time
+	lastUsedPrecision = time.Minute
+)
+
+var (
+	// ErrCreateAPIKey error is returned when API key could not be created
+	ErrCreateAPIKey = errors.New("unable to create api key")
+
+	// ErrFetchAPIKeys error is returned when API keys could not be retrieved
+	ErrFetchAPIKeys = errors.New("unable to fetch api keys")
+
+	// ErrRevokeAPIKey error is returned when API key could not be revoked
+	ErrRevokeAPIKey = errors.New("unable to revoke api key")
+
+	// ErrVerifyAPIKey error is returned when API key could not be verified
+	ErrVerifyAPIKey = errors.New("unable to verify api key")
+
+	// ErrRemoveAPIKeys error is returned when API keys of user could not be removed
+	ErrRemoveAPIKeys = errors.New("unable to remove api keys")
+
+	// ErrAPIKeyNotExist error is returned when API key does not exist
+	ErrAPIKeyNotExist = errors.New("api key does not exist")
+
+	// ErrInvalidAPIKey error is returned when presented API key is not known
+	ErrInvalidAPIKey = errors.New("invalid api key")
+
+	// ErrAPIKeyExpired error is returned when presented API key expired
+	ErrAPIKeyExpired = errors.New("api key expired")
+
+	// ErrScopeNotGranted error is returned when API key scope is not granted to its owner
+	ErrScopeNotGranted = errors.New("scope is not granted to user")
+
+	// ErrInvalidExpiration error is returned when API key expiration is in the past or exceeds maximal lifetime
+	ErrInvalidExpiration = errors.New("invalid api key expiration")
+)
+
+// APIKeysService interface
+type APIKeysService interface {
+	// APIKeysService returns service implementation signature
+	APIKeysService() string
+
+	// Create creates API key of given user with given scopes.
+	// Scopes have to be granted to the user, key expires at given time or after configured lifetime
+	Create(ctx context.Context, userID uint64, name string, scopes []string, expiresAt *time.Time) (*CreatedAPIKey, error)
+
+	// GetByUserID returns API keys of given user
+	GetByUserID(ctx context.Context, userID uint64) ([]*APIKey, error)
+
+	// Revoke removes API key with given id owned by given user
+	Revoke(ctx context.Context, userID uint64, id uint64) error
+
+	// RevokeKey removes given API key, it is used when key is revoked by presenting it
+	RevokeKey(ctx context.Context, key string) error
+
+	// RemoveAll removes all API keys of given user.
+	// It is used when user account is removed
+	RemoveAll(ctx context.Context, userID uint64) error
+
+	// VerifyAPIKey returns id of the user owning given API key and scopes of the key.
+	// Scopes which are no longer granted to the user are left out
+	VerifyAPIKey(ctx context.Context, key string) (uint64, []string, error)
+}
+
+// NewAPIKeysService creates APIKeysService interface implementation
+func NewAPIKeysService(apiKeysRepository APIKeysRepository, rolesService roleServices.RolesService, cfg config.AppConfig) APIKeysService {
+	return &apiKeysService{
+		repo:         apiKeysRepository,
+		rolesService: rolesService,
+		cfg:          cfg,
+	}
+}
+
+type apiKeysService struct {
+	repo         APIKeysRepository
+	rolesService roleServices.RolesService
+	cfg          config.AppConfig
+}
+
+// APIKeysService returns service implementation signature
+func (apiKeysService) APIKeysService() string {
+	return "apiKeysService"
+}
+
+// Create creates API key of given user with given scopes.
+// Scopes have to be granted to the user, key expires at given time or after configured lifetime
+func (svc *apiKeysService) Create(ctx context.Context, userID uint64, name string, scopes []string, expiresAt *time.Time) (*CreatedAPIKey, error) {
+	granted, err := svc.grantedScope(ctx, userID)
+	if err != nil {
+		return nil, apperror.New("APIKEYS.000", ErrCreateAPIKey, err)
+	}
+
+	scope := jwt.ParseScope(strings.Join(scopes, " "))
+	for value := range scope {
+		if !granted.Has(value) {
+			return nil, apperror.New("APIKEYS.001", ErrCreateAPIKey, ErrScopeNotGranted)
+		}
+	}
+
+	now := time.Now()
+	maxExpiresAt := now.Add(svc.cfg.APIKeyLifetime())
+	if expiresAt == nil {
+		expiresAt = &maxExpiresAt
+	}
+
+	if !expiresAt.After(now) || expiresAt.After(maxExpiresAt) {
+		return nil, apperror.New("APIKEYS.002", ErrCreateAPIKey, ErrInvalidExpiration)
+	}
+
+	plain, err := generateKey()
+	if err != nil {
+		return nil, apperror.New("APIKEYS.003", ErrCreateAPIKey, err)
+	}
+
+	key := &APIKey{
+		UserID:    userID,
+		Name:      name,
+		Prefix:    plain[:displayPrefixLength],
+		KeyHash:   hashKey(plain),
+		Scopes:    scope.Values(),
+		ExpiresAt: expiresAt.UTC(),
+	}
+	if err := svc.repo.Create(ctx, key); err != nil {
+		return nil, apperror.New("APIKEYS.004", ErrCreateAPIKey, err)
+	}
+
+	return &CreatedAPIKey{APIKey: key, Key: plain}, nil
+}
+
+// GetByUserID returns API keys of given user
+func (svc *apiKeysService) GetByUserID(ctx context.Context, userID uint64) ([]*APIKey, error) {
+	keys, err := svc.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, apperror.New("APIKEYS.010", ErrFetchAPIKeys, err)
+	}
+
+	return keys, nil
+}
+
+// Revoke removes API key with given id owned by given user
+func (svc *apiKeysService) Revoke(ctx context.Context, userID uint64, id uint64) error {
+	deleted, err := svc.repo.Delete(ctx, userID, id)
+	if err != nil {
+		return apperror.New("APIKEYS.020", ErrRevokeAPIKey, err)
+	}
+
+	if !deleted {
+		return apperror.New("APIKEYS.021", ErrRevokeAPIKey, ErrAPIKeyNotExist)
+	}
+
+	return nil
+}
+
+// RevokeKey removes given API key, it is used when key is revoked by presenting it
+func (svc *apiKeysService) RevokeKey(ctx context.Context, plain string) error {
+	if !strings.HasPrefix(plain, keyPrefix) {
+		return apperror.New("APIKEYS.050", ErrRevokeAPIKey, ErrInvalidAPIKey)
+	}
+
+	deleted, err := svc.repo.DeleteByKeyHash(ctx, hashKey(plain))
+	if err != nil {
+		return apperror.New("APIKEYS.051", ErrRevokeAPIKey, err)
+	}
+
+	if !deleted {
+		return apperror.New("APIKEYS.052", ErrRevokeAPIKey, ErrInvalidAPIKey)
+	}
+
+	return nil
+}
+
+// RemoveAll removes all API keys of given user.
+// It is used when user account is removed
+func (svc *apiKeysService) RemoveAll(ctx context.Context, userID uint64) error {
+	if err := svc.repo.DeleteByUserID(ctx, userID); err != nil {
+		return apperror.New("APIKEYS.030", ErrRemoveAPIKeys, err)
+	}
+
+	return nil
+}
+
+// VerifyAPIKey returns id of the user owning given API key and scopes of the key.
+// Scopes which are no longer granted to the user are left out
+func (svc *apiKeysService) VerifyAPIKey(ctx context.Context, plain string) (uint64, []string, error) {
+	if !strings.HasPrefix(plain, keyPrefix) {
+		return 0, nil, apperror.New("APIKEYS.040", ErrVerifyAPIKey, ErrInvalidAPIKey)
+	}
+
+	key, err := svc.repo.GetByKeyHash(ctx, hashKey(plain))
+	if err != nil {
+		return 0, nil, apperror.New("APIKEYS.041", ErrVerifyAPIKey, err)
+	}
+
+	if key == nil {
+		return 0, nil, apperror.New("APIKEYS.042", ErrVerifyAPIKey, ErrInvalidAPIKey)
+	}
+
+	if key.IsExpired() {
+		return 0, nil, apperror.New("APIKEYS.043", ErrVerifyAPIKey, ErrAPIKeyExpired)
+	}
+
+	granted, err := svc.grantedScope(ctx, key.UserID)
+	if err != nil {
+		return 0, nil, apperror.New("APIKEYS.044", ErrVerifyAPIKey, err)
+	}
+
+	scopes := make([]string, 0, len(key.Scopes))
+	for _, scope := range key.Scopes {
+		if granted.Has(scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	now := time.Now()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > lastUsedPrecision {
+		if err := svc.repo.UpdateLastUsedAt(ctx, key.ID, now); err != nil {
+			return 0, nil, apperror.New("APIKEYS.045", ErrVerifyAPIKey, err)
+		}
+	}
+
+	return key.UserID, scopes, nil
+}
+
+// grantedScope returns scope currently granted to given user: effective roles and their permissions
+func (svc *apiKeysService) grantedScope(ctx context.Context, userID uint64) (jwt.Scope, error) {
+	roles, err := svc.rolesService.GetEffectiveRoles(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions, err := svc.rolesService.GetUserPermissions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	scope := jwt.Scope{jwt.ScopeAuthorized: {}}
+	for _, role := range roles {
+		scope[role.Name] = struct{}{}
+	}
+	for _, permission := range permissions {
+		scope[permission] = struct{}{}
+	}
+	return scope, nil
+}
+
+// generateKey generates random API key
+func generateKey() (string, error) {
+	b := make([]byte, keyBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return keyPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashKey returns hash of given API key.
+// API keys are random, so fast hash function is sufficient
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,91 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	// EventLogin is recorded on every login attempt
+	EventLogin = "login"
+
+	// EventMFAChallenge is recorded when user with MFA enabled provided valid password
+	EventMFAChallenge = "mfa_challenge"
+
+	// EventRegister is recorded when user registers
+	EventRegister = "register"
+
+	// EventRefresh is recorded when auth tokens are refreshed
+	EventRefresh = "refresh"
+
+	// EventPasswordChange is recorded when user password is changed
+	EventPasswordChange = "password_change"
+
+	// EventRoleChange is recorded when role is assigned to or removed from user
+	EventRoleChange = "role_change"
+
+	// EventAccountDelete is recorded when user confirms account deletion
+	EventAccountDelete = "account_delete"
+
+	// EventAccountRestore is recorded when user cancels account deletion by logging in
+	EventAccountRestore = "account_restore"
+
+	// EventAccountAnonymize is recorded when personal information of deleted user is removed
+	EventAccountAnonymize = "account_anonymize"
+
+	// EventKeyRotation is recorded when administrator rotates token signing keys
+	EventKeyRotation = "key_rotation"
+
+	// EventAPIKeyCreate is recorded when user creates API key
+	EventAPIKeyCreate = "api_key_create"
+
+	// EventAPIKeyRevoke is recorded when user revokes API key
+	EventAPIKeyRevoke = "api_key_revoke"
+
+	// EventOAuthConsent is recorded when user approves authorization request of OAuth client
+	EventOAuthConsent = "oauth_consent"
+
+	// EventReauthenticate is recorded when logged in user confirms identity with password or MFA code
+	EventReauthenticate = "reauthenticate"
+
+	// EventProviderLink is recorded when user links external identity provider to account
+	EventProviderLink = "provider_link"
+
+	// EventProviderUnlink is recorded when user unlinks external identity provider from account
+	EventProviderUnlink = "provider_unlink"
+)
+
+// Event model holds single authentication event
+type Event struct {
+	ID        uint64    `json:"id"`
+	UserID    uint64    `json:"userId,omitempty"`
+	Event     string    `json:"event"`
+	Success   bool      `json:"success"`
+	ClientIP  string    `json:"clientIp"`
+	UserAgent string    `json:"userAgent"`
+	RequestID string    `json:"requestId"`
+	ErrorCode string    `json:"errorCode,omitempty"`
+	Meta      string    `json:"meta,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// SetMeta encodes given event details to event meta
+func (e *Event) SetMeta(meta map[string]interface{}) error {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	e.Meta = string(data)
+	return nil
+}
+
+// EventFilter holds criteria for events listing.
+// Zero values are not used for filtering
+type EventFilter struct {
+	UserID   uint64
+	Event    string
+	Success  *bool
+	ClientIP string
+	From     *time.Time
+	To       *time.Time
+}
@@ -0,0 +1,32 @@
+package audit
+
+import "github.com/go-flow/flow/v2"
+
+// Module -
+type Module struct {
+}
+
+// NewModule creates new Audit Module instance
+func NewModule() *Module {
+	return &Module{}
+}
+
+func (m *Module) ProvideImports() []flow.Provider {
+	return []flow.Provider{
+		flow.NewProvider(NewAuditRepository),
+	}
+}
+
+func (m *Module) ProvideExports() []flow.Provider {
+	return []flow.Provider{
+		flow.NewProvider(NewAuditService),
+	}
+}
+
+func (m *Module) ProvideModules() []flow.Provider {
+	return []flow.Provider{}
+}
+
+func (m *Module) ProvideRouters() []flow.Provider {
+	return []flow.Provider{}
+}
@@ -0,0 +1,272 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"api/providers/db"
+)
+
+// orderColumns holds columns events listing can be ordered by
+var orderColumns = map[string]string{
+	"id":        "id",
+	"event":     "event",
+	"createdAt": "created_at",
+}
+
+// AuditRepository interface
+type AuditRepository interface {
+	// AuditRepository interface implementation signature
+	AuditRepository() string
+
+	// Create stores event
+	Create(ctx context.Context, event *Event) error
+
+	// Count returns number of events matching given filter
+	Count(ctx context.Context, filter *EventFilter) (int, error)
+
+	// GetAll returns events matching given filter and pagination params
+	GetAll(ctx context.Context, filter *EventFilter, page int, perPage int, orderBy string, orderDir string) ([]*Event, error)
+
+	// AnonymizeByUserID removes client information from all events of given user
+	AnonymizeByUserID(ctx context.Context, userID uint64) error
+}
+
+// NewAuditRepository creates AuditRepository interface implementation
+func NewAuditRepository(store db.Store) AuditRepository {
+	return &auditRepository{
+		store: store,
+	}
+}
+
+type auditRepository struct {
+	store db.Store
+}
+
+func (r *auditRepository) AuditRepository() string {
+	return "auditRepository"
+}
+
+func (r *auditRepository) getTx(ctx context.Context) (*sql.Tx, bool, error) {
+	var err error
+	// get transaction from context
+	tx, ok := db.TxFromContext(ctx)
+	if !ok {
+		// create new transaction
+		tx, err = r.store.Begin()
+		if err != nil {
+			return nil, false, err
+		}
+		return tx, true, nil
+	}
+	return tx, false, nil
+}
+
+func (auditRepository) closeTx(tx *sql.Tx, shouldCommit bool, hasError bool) {
+	if shouldCommit {
+		if hasError {
+			tx.Rollback()
+			return
+		}
+		tx.Commit()
+	}
+}
+
+// Create stores event
+func (r *auditRepository) Create(ctx context.Context, event *Event) error {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	var userID sql.NullInt64
+	if event.UserID > 0 {
+		userID = sql.NullInt64{Int64: int64(event.UserID), Valid: true}
+	}
+
+	var meta sql.NullString
+	if event.Meta != "" {
+		meta = sql.NullString{String: event.Meta, Valid: true}
+	}
+
+	query := "INSERT INTO auth_events (user_id, event, success, client_ip, user_agent, request_id, error_code, meta) VALUES(?,?,?,?,?,?,?,?)"
+
+	result, err := tx.Exec(query, userID, event.Event, event.Success, event.ClientIP, event.UserAgent, event.RequestID, event.ErrorCode, meta)
+	if err != nil {
+		return err
+	}
+
+	lastID, err := result.LastInsertId()
+
+	if lastID > 0 {
+		event.ID = uint64(lastID)
+		event.CreatedAt = time.Now()
+	}
+
+	return err
+}
+
+// Count returns number of events matching given filter
+func (r *auditRepository) Count(ctx context.Context, filter *EventFilter) (int, error) {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	wc, args := r.whereClause(filter)
+	query := "SELECT COUNT(id) as count FROM auth_events" + wc
+
+	var count int
+	err = tx.QueryRow(query, args...).Scan(&count)
+
+	return count, err
+}
+
+// GetAll returns events matching given filter and pagination params
+func (r *auditRepository) GetAll(ctx context.Context, filter *EventFilter, page int, perPage int, orderBy string, orderDir string) ([]*Event, error) {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	offset := (page - 1) * perPage
+
+	column, ok := orderColumns[orderBy]
+	if !ok {
+		column = "created_at"
+	}
+
+	if strings.ToUpper(orderDir) != "ASC" {
+		orderDir = "DESC"
+	}
+
+	wc, args := r.whereClause(filter)
+
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			user_id,
+			event,
+			success,
+			client_ip,
+			user_agent,
+			request_id,
+			error_code,
+			meta,
+			created_at
+		FROM
+			auth_events
+		%s
+		ORDER BY %s %s, id %s
+		LIMIT %d OFFSET %d`,
+		wc, column, orderDir, orderDir, perPage, offset)
+
+	// execute query statement
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]*Event, 0, perPage)
+	// loop over results
+	for rows.Next() {
+		model := new(Event)
+		var userID sql.NullInt64
+		var meta sql.NullString
+
+		// scan row to model
+		if err = rows.Scan(
+			&model.ID,
+			&userID,
+			&model.Event,
+			&model.Success,
+			&model.ClientIP,
+			&model.UserAgent,
+			&model.RequestID,
+			&model.ErrorCode,
+			&meta,
+			&model.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		model.UserID = uint64(userID.Int64)
+		model.Meta = meta.String
+		events = append(events, model)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// AnonymizeByUserID removes client information from all events of given user
+func (r *auditRepository) AnonymizeByUserID(ctx context.Context, userID uint64) error {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := "UPDATE auth_events SET client_ip = '', user_agent = '' WHERE user_id = ?"
+	_, err = tx.Exec(query, userID)
+	return err
+}
+
+// whereClause builds WHERE clause and its arguments for given filter
+func (auditRepository) whereClause(filter *EventFilter) (string, []interface{}) {
+	if filter == nil {
+		return "", nil
+	}
+
+	conditions := []string{}
+	args := []interface{}{}
+
+	if filter.UserID > 0 {
+		conditions = append(conditions, "user_id = ?")
+		args = append(args, filter.UserID)
+	}
+
+	if filter.Event != "" {
+		conditions = append(conditions, "event = ?")
+		args = append(args, filter.Event)
+	}
+
+	if filter.Success != nil {
+		conditions = append(conditions, "success = ?")
+		args = append(args, *filter.Success)
+	}
+
+	if filter.ClientIP != "" {
+		conditions = append(conditions, "client_ip = ?")
+		args = append(args, filter.ClientIP)
+	}
+
+	if filter.From != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, *filter.From)
+	}
+
+	if filter.To != nil {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, *filter.To)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
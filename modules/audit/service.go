@@ -0,0 +1,110 @@
+package audit
+
+import (
+	"context"
+	"errors"
+
+	"api/pkg/apperror"
+	"api/pkg/paging"
+	"api/providers/db"
+	"api/providers/log"
+)
+
+var (
+	// ErrRecordEvent error is returned when event could not be recorded
+	ErrRecordEvent = errors.New("unable to record auth event")
+
+	// ErrFetchEvents error is returned when events could not be fetched
+	ErrFetchEvents = errors.New("unable to fetch auth events")
+
+	// ErrAnonymizeEvents error is returned when events could not be anonymized
+	ErrAnonymizeEvents = errors.New("unable to anonymize auth events")
+)
+
+// AuditService interface
+type AuditService interface {
+	// AuditService returns service implementation signature
+	AuditService() string
+
+	// Record stores given event.
+	// Event is outcome of an operation, so err is used to set event success and error code
+	Record(ctx context.Context, event *Event, err error) error
+
+	// Find retrieves events for given filter and pagination params
+	Find(ctx context.Context, filter *EventFilter, paginator *paging.Paginator) ([]*Event, error)
+
+	// Anonymize removes client information from all events of given user
+	Anonymize(ctx context.Context, userID uint64) error
+}
+
+// NewAuditService creates AuditService interface implementation
+func NewAuditService(auditRepository AuditRepository) AuditService {
+	return &auditService{
+		repo: auditRepository,
+	}
+}
+
+type auditService struct {
+	repo AuditRepository
+}
+
+// AuditService returns service implementation signature
+func (auditService) AuditService() string {
+	return "auditService"
+}
+
+// Record stores given event.
+// Event is outcome of an operation, so err is used to set event success and error code
+func (svc *auditService) Record(ctx context.Context, event *Event, err error) error {
+	event.Success = err == nil
+
+	var appErr *apperror.Error
+	if errors.As(err, &appErr) {
+		event.ErrorCode = appErr.Code()
+	}
+
+	if id, ok := log.RequestIDFromContext(ctx); ok {
+		event.RequestID = id
+	}
+
+	// successful events are stored with the operation, so they are rolled back with it.
+	// Failed operations roll back request transaction, so their events are stored outside of it
+	if err != nil {
+		ctx = db.DetachTxContext(ctx)
+	}
+
+	if err := svc.repo.Create(ctx, event); err != nil {
+		return apperror.New("AUDIT.000", ErrRecordEvent, err)
+	}
+
+	return nil
+}
+
+// Find retrieves events for given filter and pagination params
+func (svc *auditService) Find(ctx context.Context, filter *EventFilter, paginator *paging.Paginator) ([]*Event, error) {
+	events, err := svc.repo.GetAll(ctx, filter, paginator.Page, paginator.PerPage, paginator.OrderBy, paginator.OrderDir)
+	if err != nil {
+		return nil, apperror.New("AUDIT.010", ErrFetchEvents, err)
+	}
+
+	count, err := svc.repo.Count(ctx, filter)
+	if err != nil {
+		return nil, apperror.New("AUDIT.011", ErrFetchEvents, err)
+	}
+
+	paginator.TotalEntriesSize = count
+	paginator.CurrentEntriesSize = len(events)
+	paginator.TotalPages = paginator.TotalEntriesSize / paginator.PerPage
+	if paginator.TotalEntriesSize%paginator.PerPage > 0 {
+		paginator.TotalPages = paginator.TotalPages + 1
+	}
+	return events, nil
+}
+
+// Anonymize removes client information from all events of given user
+func (svc *auditService) Anonymize(ctx context.Context, userID uint64) error {
+	if err := svc.repo.AnonymizeByUserID(ctx, userID); err != nil {
+		return apperror.New("AUDIT.020", ErrAnonymizeEvents, err)
+	}
+	return nil
+}
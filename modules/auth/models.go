@@ -2,7 +2,8 @@ package auth
 
 import "time"
 
-// AuthProvider model
+// AuthProvider model.
+// Hash holds password hash for local provider and user identifier at provider for social ones
 type AuthProvider struct {
 	UserID    uint64
 	Provider  string
@@ -17,6 +17,9 @@ type AuthRepository interface {
 	//GetByID returns Auth for given user
 	GetByID(ctx context.Context, provider string, userID uint64) (*AuthProvider, error)
 
+	// GetByUID returns Auth for given user identifier at provider
+	GetByUID(ctx context.Context, provider string, uid string) (*AuthProvider, error)
+
 	// Create creates auth object
 	Create(ctx context.Context, auth *AuthProvider) error
 
@@ -34,6 +37,9 @@ type AuthRepository interface {
 
 	// GetByUserID returns all Auth strategies for given user
 	GetByUserID(ctx context.Context, userID uint64) ([]*AuthProvider, error)
+
+	// LockByUserID returns all Auth strategies for given user and locks them until transaction ends
+	LockByUserID(ctx context.Context, userID uint64) ([]*AuthProvider, error)
 }
 
 // NewAuthRepository creates AuthRepository interface implementation
@@ -101,6 +107,31 @@ func (r *authRepository) GetByID(ctx context.Context, provider string, userID ui
 	return model, err
 }
 
+// GetByUID returns Auth for given user identifier at provider
+func (r *authRepository) GetByUID(ctx context.Context, provider string, uid string) (*AuthProvider, error) {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := "SELECT provider, user_id, uid, created_at, updated_at FROM auth_providers WHERE provider = ? AND uid = ? "
+
+	// create empty model object
+	model := new(AuthProvider)
+
+	// execute query statement and scan row to model
+	err = tx.QueryRow(query, provider, uid).Scan(&model.Provider, &model.UserID, &model.Hash, &model.CreatedAt, &model.UpdatedAt)
+
+	if err != nil && err == sql.ErrNoRows {
+		err = nil // set err to nil
+		return nil, nil
+	}
+
+	return model, err
+}
+
 // Create creates auth object
 func (r *authRepository) Create(ctx context.Context, auth *AuthProvider) error {
 
@@ -174,6 +205,18 @@ func (r *authRepository) DeleteByUserID(ctx context.Context, userID uint64) erro
 
 // GetByUserID returns all Auth strategies for given user
 func (r *authRepository) GetByUserID(ctx context.Context, userID uint64) ([]*AuthProvider, error) {
+	query := "SELECT provider, user_id, uid, created_at, updated_at FROM auth_providers WHERE user_id = ?"
+	return r.queryByUserID(ctx, query, userID)
+}
+
+// LockByUserID returns all Auth strategies for given user and locks them until transaction ends
+func (r *authRepository) LockByUserID(ctx context.Context, userID uint64) ([]*AuthProvider, error) {
+	query := "SELECT provider, user_id, uid, created_at, updated_at FROM auth_providers WHERE user_id = ? FOR UPDATE"
+	return r.queryByUserID(ctx, query, userID)
+}
+
+// queryByUserID executes given query selecting Auth strategies of given user
+func (r *authRepository) queryByUserID(ctx context.Context, query string, userID uint64) ([]*AuthProvider, error) {
 	tx, shouldCommit, err := r.getTx(ctx)
 	if err != nil {
 		return nil, err
@@ -181,8 +224,6 @@ func (r *authRepository) GetByUserID(ctx context.Context, userID uint64) ([]*Aut
 
 	defer r.closeTx(tx, shouldCommit, err != nil)
 
-	query := "SELECT provider, user_id, uid, created_at, updated_at FROM auth_providers WHERE user_id = ?"
-
 	// create empty model object
 	authProviders := make([]*AuthProvider, 0)
 
@@ -67,7 +67,7 @@ type AuthService interface {
 	// CreateLocal creates local (password) authentication strategy for user
 	CreateLocal(ctx context.Context, userID uint64, password string) error
 
-	// CreateSocial creates social authentication  strategy for given user and social provider
+	// CreateSocial creates social authentication strategy linking given user to user identifier at external provider
 	CreateSocial(ctx context.Context, userID uint64, uid, providerName string) error
 
 	// ResetLocal  sets new password  for local authentication strategy
@@ -76,8 +76,8 @@ type AuthService interface {
 	// AuthenticateLocal authenticates user for local (password) strategy
 	AuthenticateLocal(ctx context.Context, userID uint64, password string) error
 
-	// AuthenticateSocial authenticates user for social strategy
-	AuthenticateSocial(ctx context.Context, userID uint64, uid, provider string) error
+	// AuthenticateSocial returns id of the user linked to given user identifier at external provider
+	AuthenticateSocial(ctx context.Context, uid, provider string) (uint64, error)
 
 	// ComparePassword compares password against given hash
 	ComparePassword(ctx context.Context, passwordHash string, password string) error
@@ -88,8 +88,18 @@ type AuthService interface {
 	// GetByUserID returns all authentication strategies for given user
 	GetByUserID(ctx context.Context, userID uint64) ([]string, error)
 
+	// LockByUserID returns all authentication strategies for given user and locks them until request transaction ends,
+	// so concurrent requests can not remove them at the same time
+	LockByUserID(ctx context.Context, userID uint64) ([]string, error)
+
+	// GetProviders returns all authentication strategies for given user including time they were created
+	GetProviders(ctx context.Context, userID uint64) ([]*AuthProvider, error)
+
 	// DeleteAll removes all authentication strategies for given user
 	DeleteAll(ctx context.Context, userID uint64) error
+
+	// Delete removes authentication strategy for given user and provider
+	Delete(ctx context.Context, userID uint64, providerName string) error
 }
 
 // NewAuthService creates AuthService interface implementation
@@ -129,16 +139,16 @@ func (svc *authService) CreateLocal(ctx context.Context, userID uint64, password
 	return nil
 }
 
-// CreateSocial creates social authentication  strategy for given user and social provider
-func (svc *authService) CreateSocial(ctx context.Context, userID uint64, hash, providerName string) error {
-	if providerName != AuthApple && providerName != AuthFacebook && providerName != AuthGoogle {
+// CreateSocial creates social authentication strategy linking given user to user identifier at external provider
+func (svc *authService) CreateSocial(ctx context.Context, userID uint64, uid, providerName string) error {
+	if !isSocialProvider(providerName) {
 		return apperror.New("AUTH.010", ErrCreateSocialAuth, ErrUnsupportedProvider)
 	}
 
 	provider := &AuthProvider{
 		UserID:   userID,
 		Provider: providerName,
-		Hash:     hash,
+		Hash:     uid,
 	}
 
 	if err := svc.repo.Create(ctx, provider); err != nil {
@@ -190,26 +200,28 @@ func (svc *authService) AuthenticateLocal(ctx context.Context, userID uint64, pa
 	return err
 }
 
-// AuthenticateSocial authenticates user for social strategy
-func (svc *authService) AuthenticateSocial(ctx context.Context, userID uint64, hash, providerName string) error {
-	if providerName != AuthApple && providerName != AuthFacebook && providerName != AuthGoogle {
-		return apperror.New("AUTH.110", ErrAuthentication, ErrUnsupportedProvider)
+// AuthenticateSocial returns id of the user linked to given user identifier at external provider
+func (svc *authService) AuthenticateSocial(ctx context.Context, uid, providerName string) (uint64, error) {
+	if !isSocialProvider(providerName) {
+		return 0, apperror.New("AUTH.110", ErrAuthentication, ErrUnsupportedProvider)
 	}
 
-	provider, err := svc.repo.GetByID(ctx, providerName, userID)
+	provider, err := svc.repo.GetByUID(ctx, providerName, uid)
 	if err != nil {
-		return apperror.New("AUTH.120", ErrAuthentication, err)
+		return 0, apperror.New("AUTH.120", ErrAuthentication, err)
 	}
 
 	if provider == nil {
-		return apperror.New("AUTH.130", ErrAuthentication, ErrSocialAuthNotExist)
+		return 0, apperror.New("AUTH.130", ErrAuthentication, ErrSocialAuthNotExist)
 	}
 
-	if err = svc.ComparePassword(ctx, provider.Hash, hash); err != nil {
-		return apperror.New("AUTH.140", ErrAuthentication, err)
-	}
+	return provider.UserID, nil
+}
 
-	return err
+// isSocialProvider reports whether given provider name can be used for social authentication strategy.
+// Social providers are configured identity providers, so any name except local one is accepted
+func isSocialProvider(providerName string) bool {
+	return providerName != "" && providerName != AuthLocal
 }
 
 // ComparePassword compares password against given hash
@@ -244,6 +256,30 @@ func (svc *authService) GetByUserID(ctx context.Context, userID uint64) ([]strin
 	return str, nil
 }
 
+// LockByUserID returns all authentication strategies for given user and locks them until request transaction ends
+func (svc *authService) LockByUserID(ctx context.Context, userID uint64) ([]string, error) {
+	providers, err := svc.repo.LockByUserID(ctx, userID)
+	if err != nil {
+		return nil, apperror.New("AUTH.210", ErrFetchAuthProviders, err)
+	}
+	str := []string{}
+
+	for _, p := range providers {
+		str = append(str, p.Provider)
+	}
+
+	return str, nil
+}
+
+// GetProviders returns all authentication strategies for given user including time they were created
+func (svc *authService) GetProviders(ctx context.Context, userID uint64) ([]*AuthProvider, error) {
+	providers, err := svc.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, apperror.New("AUTH.200", ErrFetchAuthProviders, err)
+	}
+	return providers, nil
+}
+
 // DeleteAll removes all authentication strategies for given user
 func (svc *authService) DeleteAll(ctx context.Context, userID uint64) error {
 	if err := svc.repo.DeleteByUserID(ctx, userID); err != nil {
@@ -251,3 +287,11 @@ func (svc *authService) DeleteAll(ctx context.Context, userID uint64) error {
 	}
 	return nil
 }
+
+// Delete removes authentication strategy for given user and provider
+func (svc *authService) Delete(ctx context.Context, userID uint64, providerName string) error {
+	if err := svc.repo.DeleteByID(ctx, providerName, userID); err != nil {
+		return apperror.New("AUTH.190", ErrDeleteAuthProviders, err)
+	}
+	return nil
+}
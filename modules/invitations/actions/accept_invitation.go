@@ -0,0 +1,68 @@
+package actions
+
+import (
+	"api/modules/invitations/services"
+	"api/pkg/apperror"
+	"api/pkg/userip"
+	"api/providers/binding"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// AcceptInvitation request object
+type AcceptInvitation struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=3"`
+}
+
+type AcceptInvitationAction struct {
+	vm                 vm.Transformer
+	binder             binding.Binder
+	invitationsService services.InvitationsService
+}
+
+func NewAcceptInvitationAction(vm vm.Transformer, binder binding.Binder, invitationsService services.InvitationsService) *AcceptInvitationAction {
+	return &AcceptInvitationAction{
+		vm:                 vm,
+		binder:             binder,
+		invitationsService: invitationsService,
+	}
+}
+
+func (a *AcceptInvitationAction) Method() string {
+	return http.MethodPost
+}
+
+func (a *AcceptInvitationAction) Path() string {
+	return "/invitations/accept"
+}
+
+func (a *AcceptInvitationAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle accepts invitation
+// @Summary Accepts invitation, sets user password and assigns invitation roles
+// @Description After invitation is accepted user can login with email and password
+// @Produce json
+// @Tags invitations
+// @Param req body AcceptInvitation true "Accept Invitation Request"
+// @Success 200 {object} vm.Response
+// @Failure 400 {object} vm.ResponseError
+// @Router /account/invitations/accept [post]
+func (a *AcceptInvitationAction) Handle(r *http.Request) flow.Response {
+	var reqObj AcceptInvitation
+	if err := a.binder.Bind(r, &reqObj); err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	err := a.invitationsService.Accept(r.Context(), reqObj.Token, reqObj.Password, userip.Get(r), r.UserAgent())
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, nil)
+}
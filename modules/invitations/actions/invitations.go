@@ -0,0 +1,50 @@
+package actions
+
+import (
+	"api/modules/invitations/services"
+	"api/providers/vm"
+	"net/http"
+
+	"github.com/go-flow/flow/v2"
+)
+
+type InvitationsAction struct {
+	vm                 vm.Transformer
+	invitationsService services.InvitationsService
+}
+
+func NewInvitationsAction(vm vm.Transformer, invitationsService services.InvitationsService) *InvitationsAction {
+	return &InvitationsAction{
+		vm:                 vm,
+		invitationsService: invitationsService,
+	}
+}
+
+func (a *InvitationsAction) Method() string {
+	return http.MethodGet
+}
+
+func (a *InvitationsAction) Path() string {
+	return "/invitations"
+}
+
+func (a *InvitationsAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle lists invitations
+// @Summary Lists pending invitations
+// @Produce json
+// @Tags invitations
+// @Security BearerAuth
+// @Success 200 {array} models.Invitation
+// @Failure 400 {object} vm.ResponseError
+// @Router /users/invitations [get]
+func (a *InvitationsAction) Handle(r *http.Request) flow.Response {
+	invitations, err := a.invitationsService.GetAll(r.Context())
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, invitations)
+}
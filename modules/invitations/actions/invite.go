@@ -0,0 +1,80 @@
+package actions
+
+import (
+	"api/modules/invitations/services"
+	roleModels "api/modules/roles/models"
+	"api/pkg/apperror"
+	"api/providers/binding"
+	"api/providers/jwt"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// Invite request object
+type Invite struct {
+	Email     string   `json:"email" binding:"required,email"`
+	FirstName string   `json:"firstName"`
+	LastName  string   `json:"lastName"`
+	RoleIDs   []uint64 `json:"roleIds"`
+}
+
+type InviteAction struct {
+	vm                 vm.Transformer
+	binder             binding.Binder
+	auth               jwt.TokenAuth
+	invitationsService services.InvitationsService
+}
+
+func NewInviteAction(vm vm.Transformer, binder binding.Binder, auth jwt.TokenAuth, invitationsService services.InvitationsService) *InviteAction {
+	return &InviteAction{
+		vm:                 vm,
+		binder:             binder,
+		auth:               auth,
+		invitationsService: invitationsService,
+	}
+}
+
+func (a *InviteAction) Method() string {
+	return http.MethodPost
+}
+
+func (a *InviteAction) Path() string {
+	return "/invitations"
+}
+
+func (a *InviteAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{
+		a.auth.RequirePermissions(roleModels.PermissionInvitationsWrite),
+	}
+}
+
+// Handle invites user
+// @Summary Creates pending user and sends invitation token to given email
+// @Produce json
+// @Tags invitations
+// @Security BearerAuth
+// @Param req body Invite true "Invite Request"
+// @Success 200 {object} models.Invitation
+// @Failure 400 {object} vm.ResponseError
+// @Router /users/invitations [post]
+func (a *InviteAction) Handle(r *http.Request) flow.Response {
+	var reqObj Invite
+	if err := a.binder.Bind(r, &reqObj); err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	userID, err := a.auth.RequestUserID(r)
+	if err != nil {
+		return a.vm.Error(http.StatusUnauthorized, err)
+	}
+
+	invitation, err := a.invitationsService.Invite(r.Context(), userID, reqObj.Email, reqObj.FirstName, reqObj.LastName, reqObj.RoleIDs)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, invitation)
+}
@@ -0,0 +1,65 @@
+package actions
+
+import (
+	"api/modules/invitations/services"
+	roleModels "api/modules/roles/models"
+	"api/pkg/apperror"
+	"api/providers/jwt"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-flow/flow/v2"
+)
+
+type ResendInvitationAction struct {
+	vm                 vm.Transformer
+	auth               jwt.TokenAuth
+	invitationsService services.InvitationsService
+}
+
+func NewResendInvitationAction(vm vm.Transformer, auth jwt.TokenAuth, invitationsService services.InvitationsService) *ResendInvitationAction {
+	return &ResendInvitationAction{
+		vm:                 vm,
+		auth:               auth,
+		invitationsService: invitationsService,
+	}
+}
+
+func (a *ResendInvitationAction) Method() string {
+	return http.MethodPost
+}
+
+func (a *ResendInvitationAction) Path() string {
+	return "/invitations/:id/resend"
+}
+
+func (a *ResendInvitationAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{
+		a.auth.RequirePermissions(roleModels.PermissionInvitationsWrite),
+	}
+}
+
+// Handle resends invitation
+// @Summary Issues new invitation token for pending user and sends it again
+// @Produce json
+// @Tags invitations
+// @Security BearerAuth
+// @Param id path int true "Invited User ID"
+// @Success 200 {object} models.Invitation
+// @Failure 400 {object} vm.ResponseError
+// @Router /users/invitations/{id}/resend [post]
+func (a *ResendInvitationAction) Handle(r *http.Request) flow.Response {
+	id, err := strconv.ParseUint(flow.ParamsFromContext(r.Context()).ByName("id"), 10, 64)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	invitation, err := a.invitationsService.Resend(r.Context(), id)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, invitation)
+}
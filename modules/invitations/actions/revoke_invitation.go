@@ -0,0 +1,64 @@
+package actions
+
+import (
+	"api/modules/invitations/services"
+	roleModels "api/modules/roles/models"
+	"api/pkg/apperror"
+	"api/providers/jwt"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-flow/flow/v2"
+)
+
+type RevokeInvitationAction struct {
+	vm                 vm.Transformer
+	auth               jwt.TokenAuth
+	invitationsService services.InvitationsService
+}
+
+func NewRevokeInvitationAction(vm vm.Transformer, auth jwt.TokenAuth, invitationsService services.InvitationsService) *RevokeInvitationAction {
+	return &RevokeInvitationAction{
+		vm:                 vm,
+		auth:               auth,
+		invitationsService: invitationsService,
+	}
+}
+
+func (a *RevokeInvitationAction) Method() string {
+	return http.MethodDelete
+}
+
+func (a *RevokeInvitationAction) Path() string {
+	return "/invitations/:id"
+}
+
+func (a *RevokeInvitationAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{
+		a.auth.RequirePermissions(roleModels.PermissionInvitationsWrite),
+	}
+}
+
+// Handle revokes invitation
+// @Summary Revokes invitation and removes pending user
+// @Produce json
+// @Tags invitations
+// @Security BearerAuth
+// @Param id path int true "Invited User ID"
+// @Success 200 {object} vm.Response
+// @Failure 400 {object} vm.ResponseError
+// @Router /users/invitations/{id} [delete]
+func (a *RevokeInvitationAction) Handle(r *http.Request) flow.Response {
+	id, err := strconv.ParseUint(flow.ParamsFromContext(r.Context()).ByName("id"), 10, 64)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	if err := a.invitationsService.Revoke(r.Context(), id); err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, nil)
+}
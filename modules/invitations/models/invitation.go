@@ -0,0 +1,49 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Invitation holds pending user invitation
+type Invitation struct {
+	UserID    uint64    `json:"userId"`
+	Email     string    `json:"email"`
+	FirstName string    `json:"firstName"`
+	LastName  string    `json:"lastName"`
+	RoleIDs   []uint64  `json:"roleIds"`
+	InvitedBy uint64    `json:"invitedBy"`
+	Expired   bool      `json:"expired"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// InvitationMeta holds invitation details stored in invitation token meta
+type InvitationMeta struct {
+	// RoleIDs holds roles assigned to user when invitation is accepted
+	RoleIDs []uint64 `json:"roleIds"`
+	// InvitedBy holds ID of the user who created invitation
+	InvitedBy uint64 `json:"invitedBy"`
+}
+
+// DecodeInvitationMeta decodes invitation details from token meta
+func DecodeInvitationMeta(meta string) (*InvitationMeta, error) {
+	m := new(InvitationMeta)
+	if meta == "" {
+		return m, nil
+	}
+
+	if err := json.Unmarshal([]byte(meta), m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Encode returns JSON encoded invitation meta
+func (m *InvitationMeta) Encode() (string, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
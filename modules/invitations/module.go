@@ -0,0 +1,49 @@
+package invitations
+
+import (
+	"api/modules/audit"
+	"api/modules/auth"
+	"api/modules/invitations/routers"
+	"api/modules/invitations/services"
+	"api/modules/roles"
+	"api/modules/tokens"
+	"api/modules/users"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// Module -
+type Module struct {
+}
+
+// NewModule creates new Invitations Module instance
+func NewModule() *Module {
+	return &Module{}
+}
+
+func (m *Module) ProvideImports() []flow.Provider {
+	return []flow.Provider{}
+}
+
+func (m *Module) ProvideExports() []flow.Provider {
+	return []flow.Provider{
+		flow.NewProvider(services.NewInvitationsService),
+	}
+}
+
+func (m *Module) ProvideModules() []flow.Provider {
+	return []flow.Provider{
+		flow.NewProvider(roles.NewModule),
+		flow.NewProvider(users.NewModule),
+		flow.NewProvider(auth.NewModule),
+		flow.NewProvider(tokens.NewModule),
+		flow.NewProvider(audit.NewModule),
+	}
+}
+
+func (m *Module) ProvideRouters() []flow.Provider {
+	return []flow.Provider{
+		flow.NewProvider(routers.NewAdminRouter),
+		flow.NewProvider(routers.NewPublicRouter),
+	}
+}
@@ -0,0 +1,44 @@
+package routers
+
+import (
+	"api/modules/invitations/actions"
+	roleModels "api/modules/roles/models"
+	"api/providers/jwt"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// AdminRouter handles invitation actions available only to administrators
+type AdminRouter struct {
+	auth jwt.TokenAuth
+}
+
+func NewAdminRouter(auth jwt.TokenAuth) *AdminRouter {
+	return &AdminRouter{
+		auth: auth,
+	}
+}
+
+func (r *AdminRouter) Path() string {
+	return "/users"
+}
+
+func (r *AdminRouter) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{
+		r.auth.AuthorizeRequest(jwt.ScopeAuthorized),
+		r.auth.RequirePermissions(roleModels.PermissionInvitationsRead),
+	}
+}
+
+func (r *AdminRouter) ProvideHandlers() []flow.Provider {
+	return []flow.Provider{
+		flow.NewProvider(actions.NewInviteAction),
+		flow.NewProvider(actions.NewInvitationsAction),
+		flow.NewProvider(actions.NewResendInvitationAction),
+		flow.NewProvider(actions.NewRevokeInvitationAction),
+	}
+}
+
+func (r *AdminRouter) RegisterSubRouters() bool {
+	return false
+}
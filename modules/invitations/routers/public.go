@@ -0,0 +1,33 @@
+package routers
+
+import (
+	"api/modules/invitations/actions"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// PublicRouter handles invitation actions available to invited users
+type PublicRouter struct {
+}
+
+func NewPublicRouter() *PublicRouter {
+	return &PublicRouter{}
+}
+
+func (r *PublicRouter) Path() string {
+	return "/account"
+}
+
+func (r *PublicRouter) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+func (r *PublicRouter) ProvideHandlers() []flow.Provider {
+	return []flow.Provider{
+		flow.NewProvider(actions.NewAcceptInvitationAction),
+	}
+}
+
+func (r *PublicRouter) RegisterSubRouters() bool {
+	return false
+}
@@ -0,0 +1,260 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"api/modules/auth"
+	"api/modules/invitations/models"
+	roleModels "api/modules/roles/models"
+	roleServices "api/modules/roles/services"
+	"api/modules/tokens"
+	userModels "api/modules/users/models"
+	"api/modules/users/services"
+	"api/pkg/apperror"
+	"api/providers/notify"
+)
+
+var (
+	// ErrInviteUser error is returned when user could not be invited
+	ErrInviteUser = errors.New("unable to invite user")
+
+	// ErrFetchInvitations error is returned when invitations could not be retrieved
+	ErrFetchInvitations = errors.New("unable to fetch invitations")
+
+	// ErrResendInvitation error is returned when invitation could not be resent
+	ErrResendInvitation = errors.New("unable to resend invitation")
+
+	// ErrRevokeInvitation error is returned when invitation could not be revoked
+	ErrRevokeInvitation = errors.New("unable to revoke invitation")
+
+	// ErrAcceptInvitation error is returned when invitation could not be accepted
+	ErrAcceptInvitation = errors.New("unable to accept invitation")
+
+	// ErrRoleNotExist error is returned when invitation references unknown role
+	ErrRoleNotExist = errors.New("role does not exist")
+)
+
+// InvitationsService interface
+type InvitationsService interface {
+	// InvitationsService returns interface implementation signature
+	InvitationsService() string
+
+	// Invite creates pending user with invitation token and sends invitation to given email
+	Invite(ctx context.Context, invitedBy uint64, email string, firstName string, lastName string, roleIDs []uint64) (*models.Invitation, error)
+
+	// GetAll returns all pending invitations
+	GetAll(ctx context.Context) ([]*models.Invitation, error)
+
+	// Resend issues new invitation token for pending user and sends it again
+	Resend(ctx context.Context, userID uint64) (*models.Invitation, error)
+
+	// Revoke removes invitation together with pending user
+	Revoke(ctx context.Context, userID uint64) error
+
+	// Accept sets password for invited user, assigns invitation roles and confirms user email
+	Accept(ctx context.Context, token string, password string, clientIP string, userAgent string) error
+}
+
+// NewInvitationsService creates InvitationsService implementation
+func NewInvitationsService(
+	rolesService roleServices.RolesService,
+	rolesAdminService roleServices.RolesAdminService,
+	usersService services.UsersService,
+	authService auth.AuthService,
+	tokensService tokens.TokensService,
+	notifier notify.Notifier) InvitationsService {
+	return &invitationsService{
+		rolesService:      rolesService,
+		rolesAdminService: rolesAdminService,
+		usersService:      usersService,
+		authService:       authService,
+		tokensService:     tokensService,
+		notifier:          notifier,
+	}
+}
+
+type invitationsService struct {
+	rolesService      roleServices.RolesService
+	rolesAdminService roleServices.RolesAdminService
+	usersService      services.UsersService
+	authService       auth.AuthService
+	tokensService     tokens.TokensService
+	notifier          notify.Notifier
+}
+
+// InvitationsService returns interface implementation signature
+func (svc *invitationsService) InvitationsService() string {
+	return "invitationsService"
+}
+
+// Invite creates pending user with invitation token and sends invitation to given email
+func (svc *invitationsService) Invite(ctx context.Context, invitedBy uint64, email string, firstName string, lastName string, roleIDs []uint64) (*models.Invitation, error) {
+	// make sure all roles exist before user is created
+	for _, roleID := range roleIDs {
+		role, err := svc.rolesService.GetByID(ctx, roleID)
+		if err != nil {
+			return nil, apperror.New("INVITATIONS.000", ErrInviteUser, err)
+		}
+		if role == nil {
+			return nil, apperror.New("INVITATIONS.001", ErrInviteUser, ErrRoleNotExist)
+		}
+	}
+
+	// create pending user, user can not login until invitation is accepted
+	user, err := svc.usersService.Create(ctx, firstName, lastName, email)
+	if err != nil {
+		return nil, apperror.New("INVITATIONS.002", ErrInviteUser, err)
+	}
+
+	meta, err := (&models.InvitationMeta{RoleIDs: roleIDs, InvitedBy: invitedBy}).Encode()
+	if err != nil {
+		return nil, apperror.New("INVITATIONS.003", ErrInviteUser, err)
+	}
+
+	invitation, err := svc.send(ctx, user, meta)
+	if err != nil {
+		return nil, apperror.New("INVITATIONS.004", ErrInviteUser, err)
+	}
+
+	return invitation, nil
+}
+
+// GetAll returns all pending invitations
+func (svc *invitationsService) GetAll(ctx context.Context) ([]*models.Invitation, error) {
+	tokens, err := svc.tokensService.GetInviteTokens(ctx)
+	if err != nil {
+		return nil, apperror.New("INVITATIONS.010", ErrFetchInvitations, err)
+	}
+
+	invitations := make([]*models.Invitation, 0, len(tokens))
+	for _, t := range tokens {
+		user, err := svc.usersService.GetByID(ctx, t.UserID)
+		if err != nil {
+			return nil, apperror.New("INVITATIONS.011", ErrFetchInvitations, err)
+		}
+
+		invitation, err := svc.invitation(user, t)
+		if err != nil {
+			return nil, apperror.New("INVITATIONS.012", ErrFetchInvitations, err)
+		}
+
+		invitations = append(invitations, invitation)
+	}
+
+	return invitations, nil
+}
+
+// Resend issues new invitation token for pending user and sends it again
+func (svc *invitationsService) Resend(ctx context.Context, userID uint64) (*models.Invitation, error) {
+	token, err := svc.tokensService.GetUserInviteToken(ctx, userID)
+	if err != nil {
+		return nil, apperror.New("INVITATIONS.020", ErrResendInvitation, err)
+	}
+
+	user, err := svc.usersService.GetByID(ctx, userID)
+	if err != nil {
+		return nil, apperror.New("INVITATIONS.021", ErrResendInvitation, err)
+	}
+
+	// new token replaces previous one and keeps invitation details
+	invitation, err := svc.send(ctx, user, token.Meta)
+	if err != nil {
+		return nil, apperror.New("INVITATIONS.022", ErrResendInvitation, err)
+	}
+
+	return invitation, nil
+}
+
+// Revoke removes invitation together with pending user
+func (svc *invitationsService) Revoke(ctx context.Context, userID uint64) error {
+	if _, err := svc.tokensService.GetUserInviteToken(ctx, userID); err != nil {
+		return apperror.New("INVITATIONS.030", ErrRevokeInvitation, err)
+	}
+
+	// pending user never had credentials, so it is removed with all tokens
+	if err := svc.usersService.Delete(ctx, userID); err != nil {
+		return apperror.New("INVITATIONS.031", ErrRevokeInvitation, err)
+	}
+
+	return nil
+}
+
+// Accept sets password for invited user, assigns invitation roles and confirms user email
+func (svc *invitationsService) Accept(ctx context.Context, token string, password string, clientIP string, userAgent string) error {
+	tokenObj, err := svc.tokensService.GetInviteToken(ctx, token)
+	if err != nil {
+		return apperror.New("INVITATIONS.040", ErrAcceptInvitation, err)
+	}
+
+	meta, err := models.DecodeInvitationMeta(tokenObj.Meta)
+	if err != nil {
+		return apperror.New("INVITATIONS.041", ErrAcceptInvitation, err)
+	}
+
+	if err := svc.authService.CreateLocal(ctx, tokenObj.UserID, password); err != nil {
+		return apperror.New("INVITATIONS.042", ErrAcceptInvitation, err)
+	}
+
+	for _, roleID := range meta.RoleIDs {
+		assignment := &roleModels.Assignment{UserID: tokenObj.UserID, RoleID: roleID}
+		if err := svc.rolesAdminService.AssignUser(ctx, 0, assignment, clientIP, userAgent); err != nil {
+			return apperror.New("INVITATIONS.043", ErrAcceptInvitation, err)
+		}
+	}
+
+	// invitation was delivered to user email, so it is confirmed
+	if err := svc.usersService.ConfirmEmail(ctx, tokenObj.UserID); err != nil {
+		return apperror.New("INVITATIONS.044", ErrAcceptInvitation, err)
+	}
+
+	// invitation token can be used only once
+	if err := svc.tokensService.Delete(ctx, tokenObj); err != nil {
+		return apperror.New("INVITATIONS.045", ErrAcceptInvitation, err)
+	}
+
+	return nil
+}
+
+// send creates invitation token for given user and sends it to user email
+func (svc *invitationsService) send(ctx context.Context, user *userModels.User, meta string) (*models.Invitation, error) {
+	token, err := svc.tokensService.CreateInviteToken(ctx, user.ID, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	err = svc.notifier.Send(ctx, &notify.Message{
+		Kind:    notify.KindInvitation,
+		To:      user.Email,
+		Subject: "You have been invited",
+		Body:    fmt.Sprintf("Use the following token to accept invitation and set your password: %s", token.Token),
+		Data:    map[string]string{"token": token.Token},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.invitation(user, token)
+}
+
+// invitation builds invitation model from pending user and invitation token
+func (svc *invitationsService) invitation(user *userModels.User, token *tokens.Token) (*models.Invitation, error) {
+	meta, err := models.DecodeInvitationMeta(token.Meta)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Invitation{
+		UserID:    user.ID,
+		Email:     user.Email,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		RoleIDs:   meta.RoleIDs,
+		InvitedBy: meta.InvitedBy,
+		Expired:   time.Now().After(token.ExpiresAt),
+		ExpiresAt: token.ExpiresAt,
+		CreatedAt: token.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,62 @@
+package actions
+
+import (
+	"api/modules/keys/models"
+	"api/modules/keys/services"
+	"api/pkg/userip"
+	"api/providers/jwt"
+	"api/providers/vm"
+	"net/http"
+
+	"github.com/go-flow/flow/v2"
+)
+
+type RotateKeysAction struct {
+	vm          vm.Transformer
+	auth        jwt.TokenAuth
+	keysService services.KeysService
+}
+
+func NewRotateKeysAction(vm vm.Transformer, auth jwt.TokenAuth, keysService services.KeysService) *RotateKeysAction {
+	return &RotateKeysAction{
+		vm:          vm,
+		auth:        auth,
+		keysService: keysService,
+	}
+}
+
+func (a *RotateKeysAction) Method() string {
+	return http.MethodPost
+}
+
+func (a *RotateKeysAction) Path() string {
+	return "/rotate"
+}
+
+func (a *RotateKeysAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle rotates token signing keys
+// @Summary Generates new token signing key, which becomes active after activation delay
+// @Description New key is published in JWKS right away, so clients cache it before tokens are signed with it. Previous keys stay in JWKS and keep verifying issued tokens until they are removed from keys directory.
+// @Description Other instances pick up the new key on next keys reload
+// @Produce json
+// @Tags keys
+// @Security BearerAuth
+// @Success 200 {object} models.RotatedKey
+// @Failure 400 {object} vm.ResponseError
+// @Router /keys/rotate [post]
+func (a *RotateKeysAction) Handle(r *http.Request) flow.Response {
+	adminID, err := a.auth.RequestUserID(r)
+	if err != nil {
+		return a.vm.Error(http.StatusUnauthorized, err)
+	}
+
+	kid, err := a.keysService.Rotate(r.Context(), adminID, userip.Get(r), r.UserAgent())
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, &models.RotatedKey{Kid: kid})
+}
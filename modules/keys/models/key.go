@@ -0,0 +1,6 @@
+package models
+
+// RotatedKey response object
+type RotatedKey struct {
+	Kid string `json:"kid"`
+}
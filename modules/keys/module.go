@@ -0,0 +1,40 @@
+package keys
+
+import (
+	"api/modules/audit"
+	"api/modules/keys/routers"
+	"api/modules/keys/services"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// Module -
+type Module struct {
+}
+
+// NewModule creates new Keys Module instance
+func NewModule() *Module {
+	return &Module{}
+}
+
+func (m *Module) ProvideImports() []flow.Provider {
+	return []flow.Provider{}
+}
+
+func (m *Module) ProvideExports() []flow.Provider {
+	return []flow.Provider{
+		flow.NewProvider(services.NewKeysService),
+	}
+}
+
+func (m *Module) ProvideModules() []flow.Provider {
+	return []flow.Provider{
+		flow.NewProvider(audit.NewModule),
+	}
+}
+
+func (m *Module) ProvideRouters() []flow.Provider {
+	return []flow.Provider{
+		flow.NewProvider(routers.NewRouter),
+	}
+}
@@ -0,0 +1,43 @@
+package routers
+
+import (
+	"api/modules/keys/actions"
+	roleModels "api/modules/roles/models"
+	"api/providers/jwt"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// Router handles signing keys administration actions
+type Router struct {
+	auth jwt.TokenAuth
+}
+
+func NewRouter(auth jwt.TokenAuth) *Router {
+	return &Router{
+		auth: auth,
+	}
+}
+
+// Path defined http path for router
+func (r *Router) Path() string {
+	return "/keys"
+}
+
+// Middlewares provides list of middlewares used by the router
+func (r *Router) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{
+		r.auth.AuthorizeRequest(jwt.ScopeAuthorized),
+		r.auth.RequirePermissions(roleModels.PermissionKeysWrite),
+	}
+}
+
+func (r *Router) ProvideHandlers() []flow.Provider {
+	return []flow.Provider{
+		flow.NewProvider(actions.NewRotateKeysAction),
+	}
+}
+
+func (r *Router) RegisterSubRouters() bool {
+	return false
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"api/modules/audit"
+	"api/pkg/apperror"
+	"api/providers/jwt"
+	"api/providers/log"
+)
+
+// ErrRotateKeys error is returned when signing keys could not be rotated
+var ErrRotateKeys = errors.New("unable to rotate signing keys")
+
+// KeysService interface manages token signing keys
+type KeysService interface {
+	// KeysService returns service implementation signature
+	KeysService() string
+
+	// Rotate generates new signing key, which becomes active after activation delay, and records key rotation.
+	// Returns kid of the new key
+	Rotate(ctx context.Context, adminID uint64, clientIP string, userAgent string) (string, error)
+}
+
+// NewKeysService creates KeysService interface implementation
+func NewKeysService(auth jwt.TokenAuth, auditService audit.AuditService, logger log.Logger) KeysService {
+	return &keysService{
+		auth:         auth,
+		auditService: auditService,
+		logger:       logger,
+	}
+}
+
+type keysService struct {
+	auth         jwt.TokenAuth
+	auditService audit.AuditService
+	logger       log.Logger
+}
+
+func (svc *keysService) KeysService() string {
+	return "keysService"
+}
+
+// Rotate generates new signing key, which becomes active after activation delay, and records key rotation.
+// Returns kid of the new key
+func (svc *keysService) Rotate(ctx context.Context, adminID uint64, clientIP string, userAgent string) (kid string, err error) {
+	defer func() {
+		svc.recordRotation(ctx, adminID, kid, clientIP, userAgent, err)
+	}()
+
+	kid, err = svc.auth.RotateKeys()
+	if err != nil {
+		return "", apperror.New("KEYS.000", ErrRotateKeys, err)
+	}
+
+	return kid, nil
+}
+
+// recordRotation stores key rotation event.
+// Failure to record event is logged, so it does not change outcome of the operation
+func (svc *keysService) recordRotation(ctx context.Context, adminID uint64, kid string, clientIP string, userAgent string, err error) {
+	logger := svc.logger
+	if l, ok := log.FromContext(ctx); ok {
+		logger = l
+	}
+
+	event := &audit.Event{Event: audit.EventKeyRotation, UserID: adminID, ClientIP: clientIP, UserAgent: userAgent}
+	if metaErr := event.SetMeta(map[string]interface{}{"kid": kid}); metaErr != nil {
+		logger.Error(metaErr)
+	}
+
+	if recErr := svc.auditService.Record(ctx, event, err); recErr != nil {
+		logger.Error(recErr)
+	}
+}
@@ -0,0 +1,33 @@
+package mfa
+
+import "time"
+
+// MFA model holds TOTP configuration of a user
+type MFA struct {
+	UserID       uint64
+	Secret       string
+	LastUsedStep int64
+	EnabledAt    *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// IsEnabled returns true if user completed MFA enrolment
+func (m *MFA) IsEnabled() bool {
+	return m.EnabledAt != nil
+}
+
+// RecoveryCode model holds hash of single use recovery code
+type RecoveryCode struct {
+	ID        uint64
+	UserID    uint64
+	CodeHash  string
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// Enrollment holds data required to register TOTP secret in authenticator app
+type Enrollment struct {
+	Secret string
+	URL    string
+}
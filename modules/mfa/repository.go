@@ -0,0 +1,242 @@
+package mfa
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"api/providers/db"
+)
+
+// MFARepository interface
+type MFARepository interface {
+	// MFARepository interface implementation signature
+	MFARepository() string
+
+	// GetByUserID returns MFA configuration for given user
+	GetByUserID(ctx context.Context, userID uint64) (*MFA, error)
+
+	// Create creates MFA configuration
+	Create(ctx context.Context, mfa *MFA) error
+
+	// Update MFA configuration
+	Update(ctx context.Context, mfa *MFA) error
+
+	// UseStep stores given TOTP time step as last used one.
+	// It returns false when given or later step was already used
+	UseStep(ctx context.Context, userID uint64, step int64) (bool, error)
+
+	// DeleteByUserID removes MFA configuration for given user
+	DeleteByUserID(ctx context.Context, userID uint64) error
+
+	// CreateRecoveryCodes stores given recovery codes
+	CreateRecoveryCodes(ctx context.Context, codes []*RecoveryCode) error
+
+	// UseRecoveryCode marks unused recovery code with given hash as used.
+	// It returns false when there is no such unused code
+	UseRecoveryCode(ctx context.Context, userID uint64, codeHash string) (bool, error)
+
+	// DeleteRecoveryCodes removes all recovery codes for given user
+	DeleteRecoveryCodes(ctx context.Context, userID uint64) error
+}
+
+// NewMFARepository creates MFARepository interface implementation
+func NewMFARepository(store db.Store) MFARepository {
+	return &mfaRepository{
+		store: store,
+	}
+}
+
+type mfaRepository struct {
+	store db.Store
+}
+
+func (r *mfaRepository) MFARepository() string {
+	return "mfaRepository"
+}
+
+func (r *mfaRepository) getTx(ctx context.Context) (*sql.Tx, bool, error) {
+	var err error
+	// get transaction from context
+	tx, ok := db.TxFromContext(ctx)
+	if !ok {
+		// create new transaction
+		tx, err = r.store.Begin()
+		if err != nil {
+			return nil, false, err
+		}
+		return tx, true, nil
+	}
+	return tx, false, nil
+}
+
+func (mfaRepository) closeTx(tx *sql.Tx, shouldCommit bool, hasError bool) {
+	if shouldCommit {
+		if hasError {
+			tx.Rollback()
+			return
+		}
+		tx.Commit()
+	}
+}
+
+// GetByUserID returns MFA configuration for given user
+func (r *mfaRepository) GetByUserID(ctx context.Context, userID uint64) (*MFA, error) {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := "SELECT user_id, secret, last_used_step, enabled_at, created_at, updated_at FROM users_mfa WHERE user_id = ?"
+
+	// create empty model object
+	model := new(MFA)
+
+	// execute query statement and scan row to model
+	err = tx.QueryRow(query, userID).Scan(&model.UserID, &model.Secret, &model.LastUsedStep, &model.EnabledAt, &model.CreatedAt, &model.UpdatedAt)
+
+	if err != nil && err == sql.ErrNoRows {
+		err = nil
+		return nil, nil
+	}
+
+	return model, err
+}
+
+// Create creates MFA configuration
+func (r *mfaRepository) Create(ctx context.Context, mfa *MFA) error {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := "INSERT INTO users_mfa (user_id, secret, last_used_step, enabled_at) VALUES(?,?,?,?)"
+
+	_, err = tx.Exec(query, mfa.UserID, mfa.Secret, mfa.LastUsedStep, mfa.EnabledAt)
+	if err != nil {
+		return err
+	}
+
+	mfa.CreatedAt = time.Now()
+	mfa.UpdatedAt = mfa.CreatedAt
+
+	return err
+}
+
+// Update MFA configuration
+func (r *mfaRepository) Update(ctx context.Context, mfa *MFA) error {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := "UPDATE users_mfa SET secret = ?, last_used_step = ?, enabled_at = ? WHERE user_id = ?"
+	_, err = tx.Exec(query, mfa.Secret, mfa.LastUsedStep, mfa.EnabledAt, mfa.UserID)
+	mfa.UpdatedAt = time.Now()
+	return err
+}
+
+// UseStep stores given TOTP time step as last used one.
+// It returns false when given or later step was already used
+func (r *mfaRepository) UseStep(ctx context.Context, userID uint64, step int64) (bool, error) {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := "UPDATE users_mfa SET last_used_step = ? WHERE user_id = ? AND last_used_step < ?"
+	result, err := tx.Exec(query, step, userID, step)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// DeleteByUserID removes MFA configuration for given user
+func (r *mfaRepository) DeleteByUserID(ctx context.Context, userID uint64) error {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := "DELETE FROM users_mfa WHERE user_id = ?"
+	_, err = tx.Exec(query, userID)
+	return err
+}
+
+// CreateRecoveryCodes stores given recovery codes
+func (r *mfaRepository) CreateRecoveryCodes(ctx context.Context, codes []*RecoveryCode) error {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := "INSERT INTO mfa_recovery_codes (user_id, code_hash) VALUES(?,?)"
+
+	for _, code := range codes {
+		var result sql.Result
+		result, err = tx.Exec(query, code.UserID, code.CodeHash)
+		if err != nil {
+			return err
+		}
+
+		var lastID int64
+		lastID, err = result.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		code.ID = uint64(lastID)
+		code.CreatedAt = time.Now()
+	}
+
+	return err
+}
+
+// UseRecoveryCode marks unused recovery code with given hash as used.
+// It returns false when there is no such unused code
+func (r *mfaRepository) UseRecoveryCode(ctx context.Context, userID uint64, codeHash string) (bool, error) {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := "UPDATE mfa_recovery_codes SET used_at = NOW() WHERE user_id = ? AND code_hash = ? AND used_at IS NULL LIMIT 1"
+	result, err := tx.Exec(query, userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// DeleteRecoveryCodes removes all recovery codes for given user
+func (r *mfaRepository) DeleteRecoveryCodes(ctx context.Context, userID uint64) error {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := "DELETE FROM mfa_recovery_codes WHERE user_id = ?"
+	_, err = tx.Exec(query, userID)
+	return err
+}
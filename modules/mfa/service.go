@@ -0,0 +1,300 @@
+package mfa
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"api/pkg/apperror"
+	"api/providers/config"
+)
+
+// recoveryCodesCount is number of recovery codes generated for user
+const recoveryCodesCount = 10
+
+var (
+	// ErrEnrollMFA error is returned when MFA enrolment could not be started
+	ErrEnrollMFA = errors.New("unable to enroll mfa")
+
+	// ErrEnableMFA error is returned when MFA could not be enabled
+	ErrEnableMFA = errors.New("unable to enable mfa")
+
+	// ErrDisableMFA error is returned when MFA could not be disabled
+	ErrDisableMFA = errors.New("unable to disable mfa")
+
+	// ErrVerifyMFA error is returned when MFA code could not be verified
+	ErrVerifyMFA = errors.New("unable to verify mfa code")
+
+	// ErrFetchMFA error is returned when MFA configuration could not be fetched
+	ErrFetchMFA = errors.New("unable to get mfa configuration")
+
+	// ErrGenerateRecoveryCodes error is returned when recovery codes could not be generated
+	ErrGenerateRecoveryCodes = errors.New("unable to generate recovery codes")
+
+	// ErrMFAEnabled error is returned when MFA is already enabled for user
+	ErrMFAEnabled = errors.New("mfa is already enabled")
+
+	// ErrMFANotEnabled error is returned when MFA is not enabled for user
+	ErrMFANotEnabled = errors.New("mfa is not enabled")
+
+	// ErrMFANotEnrolled error is returned when MFA enrolment was not started
+	ErrMFANotEnrolled = errors.New("mfa enrolment was not started")
+
+	// ErrInvalidCode error is returned when TOTP or recovery code is not valid
+	ErrInvalidCode = errors.New("invalid mfa code")
+)
+
+// MFAService interface
+type MFAService interface {
+	// MFAService returns service implementation signature
+	MFAService() string
+
+	// IsEnabled checks if user has MFA enabled
+	IsEnabled(ctx context.Context, userID uint64) (bool, error)
+
+	// Enroll creates new TOTP secret for user.
+	// MFA is not enabled until user confirms secret with valid code
+	Enroll(ctx context.Context, userID uint64, account string) (*Enrollment, error)
+
+	// Enable enables MFA after validating code generated from enrolled secret
+	// and returns new recovery codes
+	Enable(ctx context.Context, userID uint64, code string) ([]string, error)
+
+	// Verify validates TOTP code or consumes single use recovery code
+	Verify(ctx context.Context, userID uint64, code string) error
+
+	// Disable removes MFA configuration and recovery codes after validating given code
+	Disable(ctx context.Context, userID uint64, code string) error
+
+	// RegenerateRecoveryCodes replaces user recovery codes after validating given code
+	RegenerateRecoveryCodes(ctx context.Context, userID uint64, code string) ([]string, error)
+
+	// Remove removes MFA configuration and recovery codes without code validation.
+	// It is used when user account is removed
+	Remove(ctx context.Context, userID uint64) error
+}
+
+// NewMFAService creates MFAService interface implementation
+func NewMFAService(mfaRepository MFARepository, cfg config.AppConfig) MFAService {
+	return &mfaService{
+		repo: mfaRepository,
+		cfg:  cfg,
+	}
+}
+
+type mfaService struct {
+	repo MFARepository
+	cfg  config.AppConfig
+}
+
+// MFAService returns service implementation signature
+func (mfaService) MFAService() string {
+	return "mfaService"
+}
+
+// IsEnabled checks if user has MFA enabled
+func (svc *mfaService) IsEnabled(ctx context.Context, userID uint64) (bool, error) {
+	model, err := svc.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		return false, apperror.New("MFA.000", ErrFetchMFA, err)
+	}
+
+	return model != nil && model.IsEnabled(), nil
+}
+
+// Enroll creates new TOTP secret for user.
+// MFA is not enabled until user confirms secret with valid code
+func (svc *mfaService) Enroll(ctx context.Context, userID uint64, account string) (*Enrollment, error) {
+	model, err := svc.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, apperror.New("MFA.010", ErrEnrollMFA, err)
+	}
+
+	if model != nil && model.IsEnabled() {
+		return nil, apperror.New("MFA.011", ErrEnrollMFA, ErrMFAEnabled)
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, apperror.New("MFA.012", ErrEnrollMFA, err)
+	}
+
+	// restart pending enrolment with new secret
+	if model != nil {
+		model.Secret = secret
+		model.LastUsedStep = 0
+		err = svc.repo.Update(ctx, model)
+	} else {
+		err = svc.repo.Create(ctx, &MFA{UserID: userID, Secret: secret})
+	}
+
+	if err != nil {
+		return nil, apperror.New("MFA.013", ErrEnrollMFA, err)
+	}
+
+	return &Enrollment{
+		Secret: secret,
+		URL:    provisioningURL(svc.cfg.MFAIssuer(), account, secret),
+	}, nil
+}
+
+// Enable enables MFA after validating code generated from enrolled secret
+// and returns new recovery codes
+func (svc *mfaService) Enable(ctx context.Context, userID uint64, code string) ([]string, error) {
+	model, err := svc.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, apperror.New("MFA.020", ErrEnableMFA, err)
+	}
+
+	if model == nil {
+		return nil, apperror.New("MFA.021", ErrEnableMFA, ErrMFANotEnrolled)
+	}
+
+	if model.IsEnabled() {
+		return nil, apperror.New("MFA.022", ErrEnableMFA, ErrMFAEnabled)
+	}
+
+	if err := svc.verifyTOTP(ctx, model, code); err != nil {
+		return nil, apperror.New("MFA.023", ErrEnableMFA, err)
+	}
+
+	now := time.Now()
+	model.EnabledAt = &now
+	if err := svc.repo.Update(ctx, model); err != nil {
+		return nil, apperror.New("MFA.024", ErrEnableMFA, err)
+	}
+
+	codes, err := svc.generateRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, apperror.New("MFA.025", ErrEnableMFA, err)
+	}
+
+	return codes, nil
+}
+
+// Verify validates TOTP code or consumes single use recovery code
+func (svc *mfaService) Verify(ctx context.Context, userID uint64, code string) error {
+	model, err := svc.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		return apperror.New("MFA.030", ErrVerifyMFA, err)
+	}
+
+	if model == nil || !model.IsEnabled() {
+		return apperror.New("MFA.031", ErrVerifyMFA, ErrMFANotEnabled)
+	}
+
+	// TOTP codes are numeric, everything else is treated as recovery code
+	if len(code) == totpDigits {
+		if err := svc.verifyTOTP(ctx, model, code); err != nil {
+			return apperror.New("MFA.032", ErrVerifyMFA, err)
+		}
+		return nil
+	}
+
+	used, err := svc.repo.UseRecoveryCode(ctx, userID, hashRecoveryCode(code))
+	if err != nil {
+		return apperror.New("MFA.033", ErrVerifyMFA, err)
+	}
+
+	if !used {
+		return apperror.New("MFA.034", ErrVerifyMFA, ErrInvalidCode)
+	}
+
+	return nil
+}
+
+// Disable removes MFA configuration and recovery codes after validating given code
+func (svc *mfaService) Disable(ctx context.Context, userID uint64, code string) error {
+	if err := svc.Verify(ctx, userID, code); err != nil {
+		return apperror.New("MFA.040", ErrDisableMFA, err)
+	}
+
+	if err := svc.repo.DeleteRecoveryCodes(ctx, userID); err != nil {
+		return apperror.New("MFA.041", ErrDisableMFA, err)
+	}
+
+	if err := svc.repo.DeleteByUserID(ctx, userID); err != nil {
+		return apperror.New("MFA.042", ErrDisableMFA, err)
+	}
+
+	return nil
+}
+
+// Remove removes MFA configuration and recovery codes without code validation.
+// It is used when user account is removed
+func (svc *mfaService) Remove(ctx context.Context, userID uint64) error {
+	if err := svc.repo.DeleteRecoveryCodes(ctx, userID); err != nil {
+		return apperror.New("MFA.060", ErrDisableMFA, err)
+	}
+
+	if err := svc.repo.DeleteByUserID(ctx, userID); err != nil {
+		return apperror.New("MFA.061", ErrDisableMFA, err)
+	}
+
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces user recovery codes after validating given code
+func (svc *mfaService) RegenerateRecoveryCodes(ctx context.Context, userID uint64, code string) ([]string, error) {
+	if err := svc.Verify(ctx, userID, code); err != nil {
+		return nil, apperror.New("MFA.050", ErrGenerateRecoveryCodes, err)
+	}
+
+	codes, err := svc.generateRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, apperror.New("MFA.051", ErrGenerateRecoveryCodes, err)
+	}
+
+	return codes, nil
+}
+
+// verifyTOTP validates TOTP code and marks its time step as used,
+// so the same code can not be replayed
+func (svc *mfaService) verifyTOTP(ctx context.Context, model *MFA, code string) error {
+	step, ok, err := validateTOTP(model.Secret, code, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return ErrInvalidCode
+	}
+
+	used, err := svc.repo.UseStep(ctx, model.UserID, step)
+	if err != nil {
+		return err
+	}
+
+	if !used {
+		return ErrInvalidCode
+	}
+
+	model.LastUsedStep = step
+	return nil
+}
+
+// generateRecoveryCodes replaces user recovery codes with new ones
+// and returns their plain text values
+func (svc *mfaService) generateRecoveryCodes(ctx context.Context, userID uint64) ([]string, error) {
+	if err := svc.repo.DeleteRecoveryCodes(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	plain := make([]string, 0, recoveryCodesCount)
+	codes := make([]*RecoveryCode, 0, recoveryCodesCount)
+	for i := 0; i < recoveryCodesCount; i++ {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+
+		plain = append(plain, code)
+		codes = append(codes, &RecoveryCode{UserID: userID, CodeHash: hashRecoveryCode(code)})
+	}
+
+	if err := svc.repo.CreateRecoveryCodes(ctx, codes); err != nil {
+		return nil, err
+	}
+
+	return plain, nil
+}
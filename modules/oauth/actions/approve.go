@@ -0,0 +1,87 @@
+package actions
+
+import (
+	"api/modules/oauth/models"
+	"api/modules/oauth/services"
+	"api/pkg/apperror"
+	"api/pkg/userip"
+	"api/providers/binding"
+	"api/providers/jwt"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// Approve request object
+type Approve struct {
+	Approved bool `json:"approved"`
+}
+
+type ApproveAction struct {
+	vm           vm.Transformer
+	auth         jwt.TokenAuth
+	binder       binding.Binder
+	oauthService services.OAuthService
+}
+
+func NewApproveAction(vm vm.Transformer, auth jwt.TokenAuth, binder binding.Binder, oauthService services.OAuthService) *ApproveAction {
+	return &ApproveAction{
+		vm:           vm,
+		auth:         auth,
+		binder:       binder,
+		oauthService: oauthService,
+	}
+}
+
+func (a *ApproveAction) Method() string {
+	return http.MethodPost
+}
+
+func (a *ApproveAction) Path() string {
+	return "/authorize"
+}
+
+func (a *ApproveAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle approves or denies authorization request
+// @Summary Approves or denies authorization code request of authenticated user
+// @Description Authorization request is passed in query string, same as for GET request.
+// @Description Returned redirect URI holds authorization code or access_denied error and state,
+// @Description consent page redirects browser to it
+// @Produce json
+// @Tags oauth
+// @Security BearerAuth
+// @Param response_type query string true "Response type (code)"
+// @Param client_id query string true "Client ID"
+// @Param redirect_uri query string true "Registered redirect URI"
+// @Param scope query string false "Space separated scope, client allow-list when omitted"
+// @Param state query string false "Opaque value returned to client"
+// @Param code_challenge query string true "PKCE code challenge"
+// @Param code_challenge_method query string true "PKCE code challenge method (S256)"
+// @Param req body Approve true "Approve Request"
+// @Success 200 {object} vm.Response{data=models.ConsentRedirect}
+// @Failure 400 {object} vm.ResponseError
+// @Failure 401 {object} vm.ResponseError
+// @Router /oauth/authorize [post]
+func (a *ApproveAction) Handle(r *http.Request) flow.Response {
+	userID, err := a.auth.RequestUserID(r)
+	if err != nil {
+		return a.vm.Error(http.StatusUnauthorized, err)
+	}
+
+	var reqObj Approve
+	if err := a.binder.Bind(r, &reqObj); err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	redirect, err := a.oauthService.Approve(r.Context(), userID, models.NewAuthorizationRequest(r.URL.Query()), reqObj.Approved, userip.Get(r), r.UserAgent())
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, redirect)
+}
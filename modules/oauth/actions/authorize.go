@@ -0,0 +1,70 @@
+package actions
+
+import (
+	"api/modules/oauth/models"
+	"api/modules/oauth/services"
+	"api/providers/jwt"
+	"api/providers/vm"
+	"net/http"
+
+	"github.com/go-flow/flow/v2"
+)
+
+type AuthorizeAction struct {
+	vm           vm.Transformer
+	auth         jwt.TokenAuth
+	oauthService services.OAuthService
+}
+
+func NewAuthorizeAction(vm vm.Transformer, auth jwt.TokenAuth, oauthService services.OAuthService) *AuthorizeAction {
+	return &AuthorizeAction{
+		vm:           vm,
+		auth:         auth,
+		oauthService: oauthService,
+	}
+}
+
+func (a *AuthorizeAction) Method() string {
+	return http.MethodGet
+}
+
+func (a *AuthorizeAction) Path() string {
+	return "/authorize"
+}
+
+func (a *AuthorizeAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle validates authorization request and returns consent user is asked to approve
+// @Summary Validates authorization code request (RFC 6749, RFC 7636) of authenticated user
+// @Description Consent page shows returned client and scope to the user and approves or denies it with POST request.
+// @Description Requested scope has to be on client allow-list, only values granted to the user are returned.
+// @Description PKCE with S256 code challenge is required
+// @Produce json
+// @Tags oauth
+// @Security BearerAuth
+// @Param response_type query string true "Response type (code)"
+// @Param client_id query string true "Client ID"
+// @Param redirect_uri query string true "Registered redirect URI"
+// @Param scope query string false "Space separated scope, client allow-list when omitted"
+// @Param state query string false "Opaque value returned to client"
+// @Param code_challenge query string true "PKCE code challenge"
+// @Param code_challenge_method query string true "PKCE code challenge method (S256)"
+// @Success 200 {object} vm.Response{data=models.Consent}
+// @Failure 400 {object} vm.ResponseError
+// @Failure 401 {object} vm.ResponseError
+// @Router /oauth/authorize [get]
+func (a *AuthorizeAction) Handle(r *http.Request) flow.Response {
+	userID, err := a.auth.RequestUserID(r)
+	if err != nil {
+		return a.vm.Error(http.StatusUnauthorized, err)
+	}
+
+	consent, err := a.oauthService.Consent(r.Context(), userID, models.NewAuthorizationRequest(r.URL.Query()))
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, consent)
+}
@@ -0,0 +1,50 @@
+package actions
+
+import (
+	"api/modules/oauth/services"
+	"api/providers/vm"
+	"net/http"
+
+	"github.com/go-flow/flow/v2"
+)
+
+type ClientsAction struct {
+	vm           vm.Transformer
+	oauthService services.OAuthService
+}
+
+func NewClientsAction(vm vm.Transformer, oauthService services.OAuthService) *ClientsAction {
+	return &ClientsAction{
+		vm:           vm,
+		oauthService: oauthService,
+	}
+}
+
+func (a *ClientsAction) Method() string {
+	return http.MethodGet
+}
+
+func (a *ClientsAction) Path() string {
+	return "/"
+}
+
+func (a *ClientsAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle lists OAuth clients
+// @Summary Lists registered OAuth clients
+// @Produce json
+// @Tags oauth
+// @Security BearerAuth
+// @Success 200 {object} vm.Response{data=[]models.Client}
+// @Failure 400 {object} vm.ResponseError
+// @Router /oauth/clients/ [get]
+func (a *ClientsAction) Handle(r *http.Request) flow.Response {
+	clients, err := a.oauthService.GetClients(r.Context())
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, clients)
+}
@@ -0,0 +1,86 @@
+package actions
+
+import (
+	"api/modules/oauth/models"
+	"api/modules/oauth/services"
+	roleModels "api/modules/roles/models"
+	"api/pkg/apperror"
+	"api/providers/binding"
+	"api/providers/jwt"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// CreateClient request object
+type CreateClient struct {
+	Name         string   `json:"name" binding:"required,max=100"`
+	Public       bool     `json:"public"`
+	RedirectURIs []string `json:"redirectUris" binding:"max=10,dive,required,max=190"`
+	Scopes       []string `json:"scopes" binding:"max=20,dive,required,max=45"`
+	GrantTypes   []string `json:"grantTypes" binding:"max=3,dive,required"`
+}
+
+type CreateClientAction struct {
+	vm           vm.Transformer
+	auth         jwt.TokenAuth
+	binder       binding.Binder
+	oauthService services.OAuthService
+}
+
+func NewCreateClientAction(vm vm.Transformer, auth jwt.TokenAuth, binder binding.Binder, oauthService services.OAuthService) *CreateClientAction {
+	return &CreateClientAction{
+		vm:           vm,
+		auth:         auth,
+		binder:       binder,
+		oauthService: oauthService,
+	}
+}
+
+func (a *CreateClientAction) Method() string {
+	return http.MethodPost
+}
+
+func (a *CreateClientAction) Path() string {
+	return "/"
+}
+
+func (a *CreateClientAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{
+		a.auth.RequirePermissions(roleModels.PermissionClientsWrite),
+	}
+}
+
+// Handle registers OAuth client
+// @Summary Registers OAuth client
+// @Description Client secret is returned only once, it can not be retrieved later. Public clients (SPA, mobile apps)
+// @Description get no secret and can use only authorization_code and refresh_token grants.
+// @Description Scopes holds allow-list of roles and permissions client can request
+// @Produce json
+// @Tags oauth
+// @Security BearerAuth
+// @Param req body CreateClient true "Create Client Request"
+// @Success 200 {object} models.CreatedClient
+// @Failure 400 {object} vm.ResponseError
+// @Router /oauth/clients/ [post]
+func (a *CreateClientAction) Handle(r *http.Request) flow.Response {
+	var reqObj CreateClient
+	if err := a.binder.Bind(r, &reqObj); err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	client, err := a.oauthService.CreateClient(r.Context(), &models.Client{
+		Name:         reqObj.Name,
+		Public:       reqObj.Public,
+		RedirectURIs: reqObj.RedirectURIs,
+		Scopes:       reqObj.Scopes,
+		GrantTypes:   reqObj.GrantTypes,
+	})
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, client)
+}
@@ -0,0 +1,64 @@
+package actions
+
+import (
+	"api/modules/oauth/services"
+	roleModels "api/modules/roles/models"
+	"api/pkg/apperror"
+	"api/providers/jwt"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-flow/flow/v2"
+)
+
+type DeleteClientAction struct {
+	vm           vm.Transformer
+	auth         jwt.TokenAuth
+	oauthService services.OAuthService
+}
+
+func NewDeleteClientAction(vm vm.Transformer, auth jwt.TokenAuth, oauthService services.OAuthService) *DeleteClientAction {
+	return &DeleteClientAction{
+		vm:           vm,
+		auth:         auth,
+		oauthService: oauthService,
+	}
+}
+
+func (a *DeleteClientAction) Method() string {
+	return http.MethodDelete
+}
+
+func (a *DeleteClientAction) Path() string {
+	return "/:id"
+}
+
+func (a *DeleteClientAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{
+		a.auth.RequirePermissions(roleModels.PermissionClientsWrite),
+	}
+}
+
+// Handle deletes OAuth client
+// @Summary Deletes OAuth client
+// @Produce json
+// @Tags oauth
+// @Security BearerAuth
+// @Param id path int true "Client ID"
+// @Success 200 {object} vm.Response
+// @Failure 400 {object} vm.ResponseError
+// @Router /oauth/clients/{id} [delete]
+func (a *DeleteClientAction) Handle(r *http.Request) flow.Response {
+	id, err := strconv.ParseUint(flow.ParamsFromContext(r.Context()).ByName("id"), 10, 64)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	if err := a.oauthService.DeleteClient(r.Context(), id); err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, nil)
+}
@@ -0,0 +1,60 @@
+package actions
+
+import (
+	"net/http"
+
+	"api/modules/oauth/models"
+	"api/modules/oauth/services"
+
+	"github.com/go-flow/flow/v2"
+)
+
+type IntrospectAction struct {
+	oauthService services.OAuthService
+}
+
+func NewIntrospectAction(oauthService services.OAuthService) *IntrospectAction {
+	return &IntrospectAction{
+		oauthService: oauthService,
+	}
+}
+
+func (a *IntrospectAction) Method() string {
+	return http.MethodPost
+}
+
+func (a *IntrospectAction) Path() string {
+	return "/introspect"
+}
+
+func (a *IntrospectAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle describes access token, refresh token or API key
+// @Summary Introspects access token, refresh token or API key (RFC 7662)
+// @Description Invalid, expired and revoked tokens are reported as inactive.
+// @Description Confidential client authenticates with HTTP Basic authentication or client_id and client_secret parameters
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Tags oauth
+// @Security BasicAuth
+// @Param token formData string true "Token"
+// @Param token_type_hint formData string false "Token type hint (access_token, refresh_token, api_key)"
+// @Success 200 {object} models.Introspection
+// @Failure 400 {object} models.Error
+// @Failure 401 {object} models.Error
+// @Router /oauth/introspect [post]
+func (a *IntrospectAction) Handle(r *http.Request) flow.Response {
+	// public clients can not keep secret, so they could not protect introspection results
+	if client, ok := services.ClientFromContext(r.Context()); !ok || client.Public {
+		return flow.ResponseJSON(http.StatusUnauthorized, &models.Error{Error: models.ErrorInvalidClient})
+	}
+
+	token := r.PostFormValue("token")
+	if token == "" {
+		return flow.ResponseJSON(http.StatusBadRequest, &models.Error{Error: models.ErrorInvalidRequest, ErrorDescription: "token is required"})
+	}
+
+	return flow.ResponseJSON(http.StatusOK, a.oauthService.Introspect(r.Context(), token, r.PostFormValue("token_type_hint")))
+}
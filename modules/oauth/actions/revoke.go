@@ -0,0 +1,71 @@
+package actions
+
+import (
+	"errors"
+	"net/http"
+
+	"api/modules/oauth/models"
+	"api/modules/oauth/services"
+
+	"github.com/go-flow/flow/v2"
+)
+
+type RevokeAction struct {
+	oauthService services.OAuthService
+}
+
+func NewRevokeAction(oauthService services.OAuthService) *RevokeAction {
+	return &RevokeAction{
+		oauthService: oauthService,
+	}
+}
+
+func (a *RevokeAction) Method() string {
+	return http.MethodPost
+}
+
+func (a *RevokeAction) Path() string {
+	return "/revoke"
+}
+
+func (a *RevokeAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle revokes access token, refresh token or API key
+// @Summary Revokes access token, refresh token or API key (RFC 7009)
+// @Description Revoking refresh token terminates the whole session. Invalid tokens are ignored.
+// @Description Refresh tokens and client access tokens can be revoked only by client they were issued to.
+// @Description Confidential client authenticates with HTTP Basic authentication or client_id and client_secret parameters
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Tags oauth
+// @Security BasicAuth
+// @Param token formData string true "Token"
+// @Param token_type_hint formData string false "Token type hint (access_token, refresh_token, api_key)"
+// @Success 200
+// @Failure 400 {object} models.Error
+// @Failure 401 {object} models.Error
+// @Failure 503 {object} models.Error
+// @Router /oauth/revoke [post]
+func (a *RevokeAction) Handle(r *http.Request) flow.Response {
+	// public clients can not authenticate, so token ownership could not be verified
+	client, ok := services.ClientFromContext(r.Context())
+	if !ok || client.Public {
+		return flow.ResponseJSON(http.StatusUnauthorized, &models.Error{Error: models.ErrorInvalidClient})
+	}
+
+	token := r.PostFormValue("token")
+	if token == "" {
+		return flow.ResponseJSON(http.StatusBadRequest, &models.Error{Error: models.ErrorInvalidRequest, ErrorDescription: "token is required"})
+	}
+
+	if err := a.oauthService.Revoke(r.Context(), client, token, r.PostFormValue("token_type_hint")); err != nil {
+		if errors.Is(err, services.ErrTokenNotIssuedToClient) {
+			return flow.ResponseJSON(http.StatusBadRequest, &models.Error{Error: models.ErrorUnauthorizedClient, ErrorDescription: services.ErrTokenNotIssuedToClient.Error()})
+		}
+		return flow.ResponseJSON(http.StatusServiceUnavailable, &models.Error{Error: models.ErrorServerError})
+	}
+
+	return flow.ResponseJSON(http.StatusOK, struct{}{})
+}
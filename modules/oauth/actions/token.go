@@ -0,0 +1,94 @@
+package actions
+
+import (
+	"api/modules/oauth/models"
+	"api/modules/oauth/services"
+	"api/pkg/userip"
+	"errors"
+	"net/http"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// tokenErrors maps service errors to OAuth error codes returned by token endpoint
+var tokenErrors = []struct {
+	err  error
+	code string
+}{
+	{services.ErrInvalidRequest, models.ErrorInvalidRequest},
+	{services.ErrInvalidGrant, models.ErrorInvalidGrant},
+	{services.ErrUnauthorizedClient, models.ErrorUnauthorizedClient},
+	{services.ErrUnsupportedGrantType, models.ErrorUnsupportedGrantType},
+	{services.ErrInvalidScope, models.ErrorInvalidScope},
+}
+
+type TokenAction struct {
+	oauthService services.OAuthService
+}
+
+func NewTokenAction(oauthService services.OAuthService) *TokenAction {
+	return &TokenAction{
+		oauthService: oauthService,
+	}
+}
+
+func (a *TokenAction) Method() string {
+	return http.MethodPost
+}
+
+func (a *TokenAction) Path() string {
+	return "/token"
+}
+
+func (a *TokenAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle issues tokens to authenticated client
+// @Summary Issues tokens for authorization_code, refresh_token and client_credentials grants (RFC 6749)
+// @Description Confidential clients authenticate with HTTP Basic authentication or client_id and client_secret
+// @Description parameters, public clients send client_id only. Authorization code requires PKCE code verifier.
+// @Description Client credentials tokens identify the client, not a user
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Tags oauth
+// @Security BasicAuth
+// @Param grant_type formData string true "Grant type (authorization_code, refresh_token, client_credentials)"
+// @Param code formData string false "Authorization code"
+// @Param redirect_uri formData string false "Redirect URI used in authorization request"
+// @Param code_verifier formData string false "PKCE code verifier"
+// @Param refresh_token formData string false "Refresh token"
+// @Param scope formData string false "Space separated scope"
+// @Success 200 {object} models.TokenResponse
+// @Failure 400 {object} models.Error
+// @Failure 401 {object} models.Error
+// @Router /oauth/token [post]
+func (a *TokenAction) Handle(r *http.Request) flow.Response {
+	client, ok := services.ClientFromContext(r.Context())
+	if !ok {
+		return flow.ResponseJSON(http.StatusUnauthorized, &models.Error{Error: models.ErrorInvalidClient})
+	}
+
+	req := &models.TokenRequest{
+		GrantType:    r.PostFormValue("grant_type"),
+		Code:         r.PostFormValue("code"),
+		RedirectURI:  r.PostFormValue("redirect_uri"),
+		CodeVerifier: r.PostFormValue("code_verifier"),
+		RefreshToken: r.PostFormValue("refresh_token"),
+		Scope:        r.PostFormValue("scope"),
+		ClientIP:     userip.Get(r),
+		UserAgent:    r.UserAgent(),
+	}
+
+	res, err := a.oauthService.Token(r.Context(), client, req)
+	if err != nil {
+		for _, e := range tokenErrors {
+			if errors.Is(err, e.err) {
+				return flow.ResponseJSON(http.StatusBadRequest, &models.Error{Error: e.code, ErrorDescription: e.err.Error()})
+			}
+		}
+		return flow.ResponseJSON(http.StatusInternalServerError, &models.Error{Error: models.ErrorServerError})
+	}
+
+	return flow.ResponseJSON(http.StatusOK, res)
+}
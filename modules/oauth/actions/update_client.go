@@ -0,0 +1,103 @@
+package actions
+
+import (
+	"api/modules/oauth/services"
+	roleModels "api/modules/roles/models"
+	"api/pkg/apperror"
+	"api/providers/binding"
+	"api/providers/jwt"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// UpdateClient request object
+type UpdateClient struct {
+	Name         *string  `json:"name" binding:"omitempty,min=1,max=100"`
+	RedirectURIs []string `json:"redirectUris" binding:"omitempty,max=10,dive,required,max=190"`
+	Scopes       []string `json:"scopes" binding:"omitempty,max=20,dive,required,max=45"`
+	GrantTypes   []string `json:"grantTypes" binding:"omitempty,max=3,dive,required"`
+}
+
+type UpdateClientAction struct {
+	vm           vm.Transformer
+	auth         jwt.TokenAuth
+	binder       binding.Binder
+	oauthService services.OAuthService
+}
+
+func NewUpdateClientAction(vm vm.Transformer, auth jwt.TokenAuth, binder binding.Binder, oauthService services.OAuthService) *UpdateClientAction {
+	return &UpdateClientAction{
+		vm:           vm,
+		auth:         auth,
+		binder:       binder,
+		oauthService: oauthService,
+	}
+}
+
+func (a *UpdateClientAction) Method() string {
+	return http.MethodPut
+}
+
+func (a *UpdateClientAction) Path() string {
+	return "/:id"
+}
+
+func (a *UpdateClientAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{
+		a.auth.RequirePermissions(roleModels.PermissionClientsWrite),
+	}
+}
+
+// Handle updates OAuth client
+// @Summary Updates OAuth client, omitted fields are not changed
+// @Description Removing scope from allow-list does not revoke tokens already issued to client
+// @Produce json
+// @Tags oauth
+// @Security BearerAuth
+// @Param id path int true "Client ID"
+// @Param req body UpdateClient true "Update Client Request"
+// @Success 200 {object} models.Client
+// @Failure 400 {object} vm.ResponseError
+// @Router /oauth/clients/{id} [put]
+func (a *UpdateClientAction) Handle(r *http.Request) flow.Response {
+	id, err := strconv.ParseUint(flow.ParamsFromContext(r.Context()).ByName("id"), 10, 64)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	var reqObj UpdateClient
+	if err := a.binder.Bind(r, &reqObj); err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	client, err := a.oauthService.GetClient(r.Context(), id)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	if reqObj.Name != nil {
+		client.Name = *reqObj.Name
+	}
+
+	if reqObj.RedirectURIs != nil {
+		client.RedirectURIs = reqObj.RedirectURIs
+	}
+
+	if reqObj.Scopes != nil {
+		client.Scopes = reqObj.Scopes
+	}
+
+	if reqObj.GrantTypes != nil {
+		client.GrantTypes = reqObj.GrantTypes
+	}
+
+	if err := a.oauthService.UpdateClient(r.Context(), client); err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, client)
+}
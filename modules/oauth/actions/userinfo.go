@@ -0,0 +1,60 @@
+package actions
+
+import (
+	"api/modules/oauth/models"
+	userServices "api/modules/users/services"
+	"api/providers/jwt"
+	"api/providers/vm"
+	"net/http"
+
+	"github.com/go-flow/flow/v2"
+)
+
+type UserinfoAction struct {
+	vm           vm.Transformer
+	auth         jwt.TokenAuth
+	usersService userServices.UsersService
+}
+
+func NewUserinfoAction(vm vm.Transformer, auth jwt.TokenAuth, usersService userServices.UsersService) *UserinfoAction {
+	return &UserinfoAction{
+		vm:           vm,
+		auth:         auth,
+		usersService: usersService,
+	}
+}
+
+func (a *UserinfoAction) Method() string {
+	return http.MethodGet
+}
+
+func (a *UserinfoAction) Path() string {
+	return "/userinfo"
+}
+
+func (a *UserinfoAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle returns claims of authenticated user
+// @Summary Returns OpenID Connect standard claims of authenticated user
+// @Produce json
+// @Tags oauth
+// @Security BearerAuth
+// @Success 200 {object} models.Userinfo
+// @Failure 400 {object} vm.ResponseError
+// @Failure 401 {object} vm.ResponseError
+// @Router /oauth/userinfo [get]
+func (a *UserinfoAction) Handle(r *http.Request) flow.Response {
+	userID, err := a.auth.RequestUserID(r)
+	if err != nil {
+		return a.vm.Error(http.StatusUnauthorized, err)
+	}
+
+	user, err := a.usersService.GetByID(r.Context(), userID)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return flow.ResponseJSON(http.StatusOK, models.NewUserinfo(user))
+}
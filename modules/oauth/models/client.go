@@ -0,0 +1,213 @@
+package models
+
+import (
+	userModels "api/modules/users/models"
+	"api/providers/jwt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Userinfo holds OpenID Connect standard claims of authenticated user
+type Userinfo struct {
+	Sub           string `json:"sub"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+	GivenName     string `json:"given_name"`
+	FamilyName    string `json:"family_name"`
+	UpdatedAt     int64  `json:"updated_at"`
+}
+
+// NewUserinfo creates Userinfo claims for given user
+func NewUserinfo(user *userModels.User) *Userinfo {
+	return &Userinfo{
+		Sub:           strconv.FormatUint(user.ID, 10),
+		Email:         user.Email,
+		EmailVerified: user.IsEmailConfirmed(),
+		Name:          strings.TrimSpace(user.FirstName + " " + user.LastName),
+		GivenName:     user.FirstName,
+		FamilyName:    user.LastName,
+		UpdatedAt:     user.UpdatedAt.Unix(),
+	}
+}
+
+// Client model holds OAuth client registered to use authorization server endpoints.
+// Client secret is shown only once when client is created, public clients have no secret
+type Client struct {
+	ID           uint64    `json:"id"`
+	ClientID     string    `json:"clientId"`
+	SecretHash   string    `json:"-"`
+	Public       bool      `json:"public"`
+	Name         string    `json:"name"`
+	RedirectURIs []string  `json:"redirectUris"`
+	Scopes       []string  `json:"scopes"`
+	GrantTypes   []string  `json:"grantTypes"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// HasRedirectURI checks if given redirect URI is registered for client.
+// URIs are compared exactly, as required by OAuth security best practice
+func (c *Client) HasRedirectURI(uri string) bool {
+	for _, value := range c.RedirectURIs {
+		if value == uri {
+			return true
+		}
+	}
+	return false
+}
+
+// HasGrantType checks if client is allowed to use given grant type
+func (c *Client) HasGrantType(grantType string) bool {
+	for _, value := range c.GrantTypes {
+		if value == grantType {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsScope checks if all given scope values are on client scope allow-list
+func (c *Client) AllowsScope(scope ...string) bool {
+	allowed := jwt.ParseScope(strings.Join(c.Scopes, " "))
+	for _, value := range scope {
+		if !allowed.Has(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// CreatedClient holds newly created client together with its plain secret
+type CreatedClient struct {
+	*Client
+	ClientSecret string `json:"clientSecret,omitempty"`
+}
+
+// Introspection holds token introspection response as defined by RFC 7662.
+// Inactive tokens are described only by `active` member
+type Introspection struct {
+	Active    bool   `json:"active"`
+	TokenType string `json:"token_type,omitempty"`
+	Scope     string `json:"scope,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	Sub       string `json:"sub,omitempty"`
+	Exp       int64  `json:"exp,omitempty"`
+}
+
+// AuthorizationRequest holds parameters of authorization code request as defined by RFC 6749 and RFC 7636
+type AuthorizationRequest struct {
+	ResponseType        string
+	ClientID            string
+	RedirectURI         string
+	Scope               string
+	State               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+}
+
+// NewAuthorizationRequest reads authorization request from given query parameters
+func NewAuthorizationRequest(query url.Values) *AuthorizationRequest {
+	return &AuthorizationRequest{
+		ResponseType:        query.Get("response_type"),
+		ClientID:            query.Get("client_id"),
+		RedirectURI:         query.Get("redirect_uri"),
+		Scope:               query.Get("scope"),
+		State:               query.Get("state"),
+		CodeChallenge:       query.Get("code_challenge"),
+		CodeChallengeMethod: query.Get("code_challenge_method"),
+	}
+}
+
+// Consent describes authorization request user is asked to approve
+type Consent struct {
+	ClientID    string   `json:"clientId"`
+	ClientName  string   `json:"clientName"`
+	Scope       []string `json:"scope"`
+	RedirectURI string   `json:"redirectUri"`
+}
+
+// ConsentRedirect holds redirect URI with authorization response for client
+type ConsentRedirect struct {
+	RedirectURI string `json:"redirectUri"`
+}
+
+// AuthorizationCodeMeta holds authorization request the authorization code was issued for
+type AuthorizationCodeMeta struct {
+	ClientID      string `json:"clientId"`
+	RedirectURI   string `json:"redirectUri"`
+	Scope         string `json:"scope"`
+	CodeChallenge string `json:"codeChallenge"`
+}
+
+// TokenRequest holds parameters of token request as defined by RFC 6749
+type TokenRequest struct {
+	GrantType    string
+	Code         string
+	RedirectURI  string
+	CodeVerifier string
+	RefreshToken string
+	Scope        string
+	ClientIP     string
+	UserAgent    string
+}
+
+// TokenResponse holds successful token response as defined by RFC 6749
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	Scope        string `json:"scope"`
+}
+
+// Error holds OAuth error response as defined by RFC 6749
+type Error struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+const (
+	// GrantTypeAuthorizationCode exchanges authorization code for tokens
+	GrantTypeAuthorizationCode = "authorization_code"
+
+	// GrantTypeRefreshToken exchanges refresh token for new tokens
+	GrantTypeRefreshToken = "refresh_token"
+
+	// GrantTypeClientCredentials issues tokens to client itself
+	GrantTypeClientCredentials = "client_credentials"
+
+	// ResponseTypeCode requests authorization code
+	ResponseTypeCode = "code"
+
+	// CodeChallengeMethodS256 is the only supported PKCE code challenge method
+	CodeChallengeMethodS256 = "S256"
+)
+
+const (
+	// ErrorInvalidRequest is returned when required parameter is missing or malformed
+	ErrorInvalidRequest = "invalid_request"
+
+	// ErrorInvalidClient is returned when client authentication failed
+	ErrorInvalidClient = "invalid_client"
+
+	// ErrorInvalidGrant is returned when authorization code or refresh token is invalid, expired or issued to other client
+	ErrorInvalidGrant = "invalid_grant"
+
+	// ErrorUnauthorizedClient is returned when client is not allowed to use requested grant type
+	ErrorUnauthorizedClient = "unauthorized_client"
+
+	// ErrorUnsupportedGrantType is returned when requested grant type is not supported
+	ErrorUnsupportedGrantType = "unsupported_grant_type"
+
+	// ErrorInvalidScope is returned when requested scope is not allowed for client
+	ErrorInvalidScope = "invalid_scope"
+
+	// ErrorAccessDenied is returned to client when user denied authorization request
+	ErrorAccessDenied = "access_denied"
+
+	// ErrorServerError is returned when request could not be processed due to unexpected condition
+	ErrorServerError = "server_error"
+)
@@ -0,0 +1,55 @@
+package oauth
+
+import (
+	"api/modules/apikeys"
+	"api/modules/audit"
+	"api/modules/auth"
+	"api/modules/oauth/repositories"
+	"api/modules/oauth/routers"
+	"api/modules/oauth/services"
+	"api/modules/roles"
+	"api/modules/tokens"
+	"api/modules/users"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// Module -
+type Module struct {
+}
+
+// NewModule creates new OAuth Module instance
+func NewModule() *Module {
+	return &Module{}
+}
+
+func (m *Module) ProvideImports() []flow.Provider {
+	return []flow.Provider{
+		flow.NewProvider(repositories.NewClientsRepository),
+	}
+}
+
+func (m *Module) ProvideExports() []flow.Provider {
+	return []flow.Provider{
+		flow.NewProvider(services.NewOAuthService),
+	}
+}
+
+func (m *Module) ProvideModules() []flow.Provider {
+	return []flow.Provider{
+		flow.NewProvider(roles.NewModule),
+		flow.NewProvider(users.NewModule),
+		flow.NewProvider(auth.NewModule),
+		flow.NewProvider(tokens.NewModule),
+		flow.NewProvider(audit.NewModule),
+		flow.NewProvider(apikeys.NewModule),
+	}
+}
+
+func (m *Module) ProvideRouters() []flow.Provider {
+	return []flow.Provider{
+		flow.NewProvider(routers.NewRouter),
+		flow.NewProvider(routers.NewClientRouter),
+		flow.NewProvider(routers.NewClientsRouter),
+	}
+}
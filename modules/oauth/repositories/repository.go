@@ -0,0 +1,249 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"api/modules/oauth/models"
+	"api/providers/db"
+)
+
+// ClientsRepository interface
+type ClientsRepository interface {
+	// ClientsRepository interface implementation signature
+	ClientsRepository() string
+
+	// Get returns all registered clients
+	Get(ctx context.Context) ([]*models.Client, error)
+
+	// GetByID returns client with given id
+	GetByID(ctx context.Context, id uint64) (*models.Client, error)
+
+	// GetByClientID returns client with given client id
+	GetByClientID(ctx context.Context, clientID string) (*models.Client, error)
+
+	// Create stores given client
+	Create(ctx context.Context, client *models.Client) error
+
+	// Update stores changed name, redirect URIs, scopes and grant types of given client
+	Update(ctx context.Context, client *models.Client) error
+
+	// Delete removes client with given id.
+	// It returns false when there is no such client
+	Delete(ctx context.Context, id uint64) (bool, error)
+}
+
+// NewClientsRepository creates ClientsRepository interface implementation
+func NewClientsRepository(store db.Store) ClientsRepository {
+	return &clientsRepository{
+		store: store,
+	}
+}
+
+type clientsRepository struct {
+	store db.Store
+}
+
+func (r *clientsRepository) ClientsRepository() string {
+	return "clientsRepository"
+}
+
+func (r *clientsRepository) getTx(ctx context.Context) (*sql.Tx, bool, error) {
+	var err error
+	// get transaction from context
+	tx, ok := db.TxFromContext(ctx)
+	if !ok {
+		// create new transaction
+		tx, err = r.store.Begin()
+		if err != nil {
+			return nil, false, err
+		}
+		return tx, true, nil
+	}
+	return tx, false, nil
+}
+
+func (clientsRepository) closeTx(tx *sql.Tx, shouldCommit bool, hasError bool) {
+	if shouldCommit {
+		if hasError {
+			tx.Rollback()
+			return
+		}
+		tx.Commit()
+	}
+}
+
+// Get returns all registered clients
+func (r *clientsRepository) Get(ctx context.Context) ([]*models.Client, error) {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := "SELECT id, client_id, secret_hash, public, name, redirect_uris, scopes, grant_types, created_at, updated_at FROM oauth_clients ORDER BY id"
+
+	clients := make([]*models.Client, 0)
+
+	// execute query statement
+	rows, err := tx.Query(query)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	// loop over results
+	for rows.Next() {
+		model := new(models.Client)
+		var redirectURIs, scopes, grantTypes string
+		// scan row to model
+		if err = rows.Scan(&model.ID, &model.ClientID, &model.SecretHash, &model.Public, &model.Name, &redirectURIs, &scopes, &grantTypes, &model.CreatedAt, &model.UpdatedAt); err != nil {
+			return nil, err
+		}
+
+		model.RedirectURIs = strings.Fields(redirectURIs)
+		model.Scopes = strings.Fields(scopes)
+		model.GrantTypes = strings.Fields(grantTypes)
+
+		clients = append(clients, model)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return clients, nil
+}
+
+// GetByID returns client with given id
+func (r *clientsRepository) GetByID(ctx context.Context, id uint64) (*models.Client, error) {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := "SELECT id, client_id, secret_hash, public, name, redirect_uris, scopes, grant_types, created_at, updated_at FROM oauth_clients WHERE id = ?"
+
+	// create empty model object
+	model := new(models.Client)
+	var redirectURIs, scopes, grantTypes string
+
+	// execute query statement and scan row to model
+	err = tx.QueryRow(query, id).Scan(&model.ID, &model.ClientID, &model.SecretHash, &model.Public, &model.Name, &redirectURIs, &scopes, &grantTypes, &model.CreatedAt, &model.UpdatedAt)
+
+	if err != nil && err == sql.ErrNoRows {
+		err = nil
+		return nil, nil
+	}
+
+	model.RedirectURIs = strings.Fields(redirectURIs)
+	model.Scopes = strings.Fields(scopes)
+	model.GrantTypes = strings.Fields(grantTypes)
+
+	return model, err
+}
+
+// GetByClientID returns client with given client id
+func (r *clientsRepository) GetByClientID(ctx context.Context, clientID string) (*models.Client, error) {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := "SELECT id, client_id, secret_hash, public, name, redirect_uris, scopes, grant_types, created_at, updated_at FROM oauth_clients WHERE client_id = ?"
+
+	// create empty model object
+	model := new(models.Client)
+	var redirectURIs, scopes, grantTypes string
+
+	// execute query statement and scan row to model
+	err = tx.QueryRow(query, clientID).Scan(&model.ID, &model.ClientID, &model.SecretHash, &model.Public, &model.Name, &redirectURIs, &scopes, &grantTypes, &model.CreatedAt, &model.UpdatedAt)
+
+	if err != nil && err == sql.ErrNoRows {
+		err = nil
+		return nil, nil
+	}
+
+	model.RedirectURIs = strings.Fields(redirectURIs)
+	model.Scopes = strings.Fields(scopes)
+	model.GrantTypes = strings.Fields(grantTypes)
+
+	return model, err
+}
+
+// Create stores given client
+func (r *clientsRepository) Create(ctx context.Context, client *models.Client) error {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := "INSERT INTO oauth_clients (client_id, secret_hash, public, name, redirect_uris, scopes, grant_types) VALUES(?,?,?,?,?,?,?)"
+
+	result, err := tx.Exec(query, client.ClientID, client.SecretHash, client.Public, client.Name,
+		strings.Join(client.RedirectURIs, " "), strings.Join(client.Scopes, " "), strings.Join(client.GrantTypes, " "))
+	if err != nil {
+		return err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	client.ID = uint64(lastID)
+	client.CreatedAt = time.Now()
+	client.UpdatedAt = client.CreatedAt
+
+	return err
+}
+
+// Update stores changed name, redirect URIs, scopes and grant types of given client
+func (r *clientsRepository) Update(ctx context.Context, client *models.Client) error {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := "UPDATE oauth_clients SET name = ?, redirect_uris = ?, scopes = ?, grant_types = ? WHERE id = ?"
+
+	_, err = tx.Exec(query, client.Name, strings.Join(client.RedirectURIs, " "), strings.Join(client.Scopes, " "),
+		strings.Join(client.GrantTypes, " "), client.ID)
+	if err != nil {
+		return err
+	}
+
+	client.UpdatedAt = time.Now()
+	return nil
+}
+
+// Delete removes client with given id.
+// It returns false when there is no such client
+func (r *clientsRepository) Delete(ctx context.Context, id uint64) (bool, error) {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := "DELETE FROM oauth_clients WHERE id = ?"
+	result, err := tx.Exec(query, id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
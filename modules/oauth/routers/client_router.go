@@ -0,0 +1,72 @@
+package routers
+
+import (
+	"errors"
+	"net/http"
+
+	"api/modules/oauth/actions"
+	"api/modules/oauth/models"
+	"api/modules/oauth/services"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// ClientRouter handles OAuth actions called by registered clients.
+// Clients authenticate with HTTP Basic authentication or client_id and client_secret form parameters,
+// public clients send client_id only
+type ClientRouter struct {
+	oauthService services.OAuthService
+}
+
+func NewClientRouter(oauthService services.OAuthService) *ClientRouter {
+	return &ClientRouter{
+		oauthService: oauthService,
+	}
+}
+
+// Path defined http path for router
+func (r *ClientRouter) Path() string {
+	return "/oauth"
+}
+
+// Middlewares provides list of middlewares used by the router
+func (r *ClientRouter) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{
+		r.authenticateClient,
+	}
+}
+
+func (r *ClientRouter) ProvideHandlers() []flow.Provider {
+	return []flow.Provider{
+		flow.NewProvider(actions.NewTokenAction),
+		flow.NewProvider(actions.NewIntrospectAction),
+		flow.NewProvider(actions.NewRevokeAction),
+	}
+}
+
+func (r *ClientRouter) RegisterSubRouters() bool {
+	return false
+}
+
+// authenticateClient authenticates client with credentials given in request
+// and adds authenticated client to request context
+func (r *ClientRouter) authenticateClient(next flow.MiddlewareFunc) flow.MiddlewareFunc {
+	return func(w http.ResponseWriter, req *http.Request) flow.Response {
+		clientID, secret, ok := req.BasicAuth()
+		if !ok {
+			clientID, secret = req.PostFormValue("client_id"), req.PostFormValue("client_secret")
+		}
+
+		client, err := r.oauthService.AuthenticateClient(req.Context(), clientID, secret)
+		if errors.Is(err, services.ErrInvalidClient) {
+			w.Header().Set("WWW-Authenticate", `Basic realm="oauth"`)
+			return flow.ResponseJSON(http.StatusUnauthorized, &models.Error{Error: models.ErrorInvalidClient})
+		}
+
+		if err != nil {
+			return flow.ResponseJSON(http.StatusInternalServerError, &models.Error{Error: models.ErrorServerError})
+		}
+
+		return next(w, req.WithContext(services.NewClientContext(req.Context(), client)))
+	}
+}
@@ -0,0 +1,46 @@
+package routers
+
+import (
+	"api/modules/oauth/actions"
+	roleModels "api/modules/roles/models"
+	"api/providers/jwt"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// ClientsRouter handles OAuth clients administration actions
+type ClientsRouter struct {
+	auth jwt.TokenAuth
+}
+
+func NewClientsRouter(auth jwt.TokenAuth) *ClientsRouter {
+	return &ClientsRouter{
+		auth: auth,
+	}
+}
+
+// Path defined http path for router
+func (r *ClientsRouter) Path() string {
+	return "/oauth/clients"
+}
+
+// Middlewares provides list of middlewares used by the router
+func (r *ClientsRouter) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{
+		r.auth.AuthorizeRequest(jwt.ScopeAuthorized),
+		r.auth.RequirePermissions(roleModels.PermissionClientsRead),
+	}
+}
+
+func (r *ClientsRouter) ProvideHandlers() []flow.Provider {
+	return []flow.Provider{
+		flow.NewProvider(actions.NewClientsAction),
+		flow.NewProvider(actions.NewCreateClientAction),
+		flow.NewProvider(actions.NewUpdateClientAction),
+		flow.NewProvider(actions.NewDeleteClientAction),
+	}
+}
+
+func (r *ClientsRouter) RegisterSubRouters() bool {
+	return false
+}
@@ -0,0 +1,43 @@
+package routers
+
+import (
+	"api/modules/oauth/actions"
+	"api/providers/jwt"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// Router handles OAuth and OpenID Connect actions
+type Router struct {
+	auth jwt.TokenAuth
+}
+
+func NewRouter(auth jwt.TokenAuth) *Router {
+	return &Router{
+		auth: auth,
+	}
+}
+
+// Path defined http path for router
+func (r *Router) Path() string {
+	return "/oauth"
+}
+
+// Middlewares provides list of middlewares used by the router
+func (r *Router) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{
+		r.auth.AuthorizeRequest(jwt.ScopeAuthorized),
+	}
+}
+
+func (r *Router) ProvideHandlers() []flow.Provider {
+	return []flow.Provider{
+		flow.NewProvider(actions.NewUserinfoAction),
+		flow.NewProvider(actions.NewAuthorizeAction),
+		flow.NewProvider(actions.NewApproveAction),
+	}
+}
+
+func (r *Router) RegisterSubRouters() bool {
+	return false
+}
@@ -0,0 +1,20 @@
+package services
+
+import (
+	"context"
+
+	"api/modules/oauth/models"
+)
+
+type ClientKey struct{}
+
+// ClientFromContext returns authenticated client within given context
+func ClientFromContext(ctx context.Context) (*models.Client, bool) {
+	client, ok := ctx.Value(ClientKey{}).(*models.Client)
+	return client, ok
+}
+
+// NewClientContext creates context with authenticated client
+func NewClientContext(ctx context.Context, client *models.Client) context.Context {
+	return context.WithValue(ctx, ClientKey{}, client)
+}
@@ -0,0 +1,980 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"api/modules/apikeys"
+	"api/modules/audit"
+	"api/modules/oauth/models"
+	"api/modules/oauth/repositories"
+	roleServices "api/modules/roles/services"
+	"api/modules/tokens"
+	userServices "api/modules/users/services"
+	"api/pkg/apperror"
+	"api/providers/db"
+	"api/providers/jwt"
+	"api/providers/log"
+)
+
+const (
+	// TokenTypeHintAccessToken identifies access tokens in introspection and revocation requests
+	TokenTypeHintAccessToken = "access_token"
+
+	// TokenTypeHintRefreshToken identifies refresh tokens in introspection and revocation requests
+	TokenTypeHintRefreshToken = "refresh_token"
+
+	// TokenTypeHintAPIKey identifies API keys in introspection and revocation requests
+	TokenTypeHintAPIKey = "api_key"
+
+	// clientIDBytes is number of random bytes in generated client id
+	clientIDBytes = 16
+
+	// clientSecretBytes is number of random bytes in generated client secret
+	clientSecretBytes = 32
+
+	// minCodeVerifierLength and maxCodeVerifierLength bound PKCE code verifier and challenge length (RFC 7636)
+	minCodeVerifierLength = 43
+	maxCodeVerifierLength = 128
+)
+
+// tokenTypeHints holds supported token types in order they are tried when hint is not given
+var tokenTypeHints = []string{TokenTypeHintAccessToken, TokenTypeHintRefreshToken, TokenTypeHintAPIKey}
+
+var (
+	// ErrCreateClient error is returned when client could not be created
+	ErrCreateClient = errors.New("unable to create client")
+
+	// ErrFetchClients error is returned when clients could not be retrieved
+	ErrFetchClients = errors.New("unable to fetch clients")
+
+	// ErrDeleteClient error is returned when client could not be deleted
+	ErrDeleteClient = errors.New("unable to delete client")
+
+	// ErrAuthenticateClient error is returned when client could not be authenticated
+	ErrAuthenticateClient = errors.New("unable to authenticate client")
+
+	// ErrRevokeToken error is returned when token could not be revoked
+	ErrRevokeToken = errors.New("unable to revoke token")
+
+	// ErrTokenNotIssuedToClient error is returned when client revokes token issued to other client
+	ErrTokenNotIssuedToClient = errors.New("token was not issued to client")
+
+	// ErrFetchClient error is returned when client could not be retrieved
+	ErrFetchClient = errors.New("unable to fetch client")
+
+	// ErrUpdateClient error is returned when client could not be updated
+	ErrUpdateClient = errors.New("unable to update client")
+
+	// ErrAuthorize error is returned when authorization request could not be processed
+	ErrAuthorize = errors.New("unable to authorize client")
+
+	// ErrIssueTokens error is returned when token request could not be processed
+	ErrIssueTokens = errors.New("unable to issue tokens")
+
+	// ErrClientNotExist error is returned when client does not exist
+	ErrClientNotExist = errors.New("client does not exist")
+
+	// ErrInvalidClient error is returned when client credentials are not valid
+	ErrInvalidClient = errors.New("invalid client credentials")
+
+	// ErrInvalidRedirectURI error is returned when redirect URI is not absolute URL or it is not registered for client
+	ErrInvalidRedirectURI = errors.New("invalid redirect uri")
+
+	// ErrInvalidGrantTypes error is returned when client is registered with unsupported or conflicting grant types
+	ErrInvalidGrantTypes = errors.New("invalid grant types")
+
+	// ErrInvalidRequest error is returned when required parameter is missing or malformed
+	ErrInvalidRequest = errors.New("invalid request")
+
+	// ErrUnsupportedResponseType error is returned when authorization request asks for other response than code
+	ErrUnsupportedResponseType = errors.New("unsupported response type")
+
+	// ErrInvalidCodeChallenge error is returned when authorization request has no valid S256 code challenge
+	ErrInvalidCodeChallenge = errors.New("S256 code challenge is required")
+
+	// ErrUnauthorizedClient error is returned when client is not allowed to use requested grant type
+	ErrUnauthorizedClient = errors.New("client is not allowed to use grant type")
+
+	// ErrUnsupportedGrantType error is returned when requested grant type is not supported
+	ErrUnsupportedGrantType = errors.New("unsupported grant type")
+
+	// ErrInvalidGrant error is returned when authorization code or refresh token is invalid, expired or issued to other client
+	ErrInvalidGrant = errors.New("invalid grant")
+
+	// ErrInvalidScope error is returned when requested scope is not allowed for client or not granted to user
+	ErrInvalidScope = errors.New("invalid scope")
+)
+
+// supportedGrantTypes holds grant types clients can be registered with
+var supportedGrantTypes = []string{models.GrantTypeAuthorizationCode, models.GrantTypeRefreshToken, models.GrantTypeClientCredentials}
+
+// OAuthService interface
+type OAuthService interface {
+	// OAuthService returns service implementation signature
+	OAuthService() string
+
+	// CreateClient registers given client with generated client id, confidential clients get generated secret
+	CreateClient(ctx context.Context, client *models.Client) (*models.CreatedClient, error)
+
+	// GetClients returns all registered clients
+	GetClients(ctx context.Context) ([]*models.Client, error)
+
+	// GetClient returns client with given id
+	GetClient(ctx context.Context, id uint64) (*models.Client, error)
+
+	// UpdateClient stores changed name, redirect URIs, scopes and grant types of given client
+	UpdateClient(ctx context.Context, client *models.Client) error
+
+	// DeleteClient removes client with given id
+	DeleteClient(ctx context.Context, id uint64) error
+
+	// AuthenticateClient returns client with given client id when given secret matches.
+	// Public clients are identified by client id only
+	AuthenticateClient(ctx context.Context, clientID string, secret string) (*models.Client, error)
+
+	// Consent validates authorization request of given user and describes what user is asked to approve
+	Consent(ctx context.Context, userID uint64, req *models.AuthorizationRequest) (*models.Consent, error)
+
+	// Approve completes authorization request of given user. Approved request redirects client with
+	// authorization code, denied request redirects client with access_denied error
+	Approve(ctx context.Context, userID uint64, req *models.AuthorizationRequest, approved bool, clientIP string, userAgent string) (*models.ConsentRedirect, error)
+
+	// Token issues tokens to given authenticated client for authorization_code, refresh_token
+	// and client_credentials grants
+	Token(ctx context.Context, client *models.Client, req *models.TokenRequest) (*models.TokenResponse, error)
+
+	// Introspect describes given access token, refresh token or API key.
+	// Token of hinted type is tried first, invalid, expired and revoked tokens are reported as inactive
+	Introspect(ctx context.Context, token string, hint string) *models.Introspection
+
+	// Revoke revokes given access token, refresh token or API key on behalf of given authenticated client.
+	// Revoking refresh token terminates the whole session. Invalid tokens are ignored
+	Revoke(ctx context.Context, client *models.Client, token string, hint string) error
+}
+
+// NewOAuthService creates OAuthService interface implementation
+func NewOAuthService(
+	clientsRepository repositories.ClientsRepository,
+	rolesService roleServices.RolesService,
+	usersService userServices.UsersService,
+	tokensService tokens.TokensService,
+	apiKeysService apikeys.APIKeysService,
+	auditService audit.AuditService,
+	jwt jwt.TokenAuth,
+	logger log.Logger) OAuthService {
+	return &oauthService{
+		repo:           clientsRepository,
+		rolesService:   rolesService,
+		usersService:   usersService,
+		tokensService:  tokensService,
+		apiKeysService: apiKeysService,
+		auditService:   auditService,
+		jwt:            jwt,
+		logger:         logger,
+	}
+}
+
+type oauthService struct {
+	repo           repositories.ClientsRepository
+	rolesService   roleServices.RolesService
+	usersService   userServices.UsersService
+	tokensService  tokens.TokensService
+	apiKeysService apikeys.APIKeysService
+	auditService   audit.AuditService
+	jwt            jwt.TokenAuth
+	logger         log.Logger
+}
+
+// OAuthService returns service implementation signature
+func (oauthService) OAuthService() string {
+	return "oauthService"
+}
+
+// requestLogger returns request scoped logger when available
+func (svc *oauthService) requestLogger(ctx context.Context) log.Logger {
+	if l, ok := log.FromContext(ctx); ok {
+		return l
+	}
+	return svc.logger
+}
+
+// recordEvent stores authentication event.
+// Failure to record event is logged, so it does not change outcome of the operation
+func (svc *oauthService) recordEvent(ctx context.Context, event *audit.Event, err error) {
+	if recErr := svc.auditService.Record(ctx, event, err); recErr != nil {
+		svc.requestLogger(ctx).Error(recErr)
+	}
+}
+
+// CreateClient registers given client with generated client id, confidential clients get generated secret
+func (svc *oauthService) CreateClient(ctx context.Context, client *models.Client) (*models.CreatedClient, error) {
+	if err := validateClient(client); err != nil {
+		return nil, apperror.New("OAUTH.003", ErrCreateClient, err)
+	}
+
+	clientID, err := randomString(clientIDBytes)
+	if err != nil {
+		return nil, apperror.New("OAUTH.000", ErrCreateClient, err)
+	}
+	client.ClientID = clientID
+
+	created := &models.CreatedClient{Client: client}
+	if !client.Public {
+		created.ClientSecret, err = randomString(clientSecretBytes)
+		if err != nil {
+			return nil, apperror.New("OAUTH.001", ErrCreateClient, err)
+		}
+		client.SecretHash = hashSecret(created.ClientSecret)
+	}
+
+	if err := svc.repo.Create(ctx, client); err != nil {
+		return nil, apperror.New("OAUTH.002", ErrCreateClient, err)
+	}
+
+	return created, nil
+}
+
+// GetClients returns all registered clients
+func (svc *oauthService) GetClients(ctx context.Context) ([]*models.Client, error) {
+	clients, err := svc.repo.Get(ctx)
+	if err != nil {
+		return nil, apperror.New("OAUTH.010", ErrFetchClients, err)
+	}
+
+	return clients, nil
+}
+
+// GetClient returns client with given id
+func (svc *oauthService) GetClient(ctx context.Context, id uint64) (*models.Client, error) {
+	client, err := svc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, apperror.New("OAUTH.050", ErrFetchClient, err)
+	}
+
+	if client == nil {
+		return nil, apperror.New("OAUTH.051", ErrFetchClient, ErrClientNotExist)
+	}
+
+	return client, nil
+}
+
+// UpdateClient stores changed name, redirect URIs, scopes and grant types of given client
+func (svc *oauthService) UpdateClient(ctx context.Context, client *models.Client) error {
+	if err := validateClient(client); err != nil {
+		return apperror.New("OAUTH.060", ErrUpdateClient, err)
+	}
+
+	if err := svc.repo.Update(ctx, client); err != nil {
+		return apperror.New("OAUTH.061", ErrUpdateClient, err)
+	}
+
+	return nil
+}
+
+// DeleteClient removes client with given id
+func (svc *oauthService) DeleteClient(ctx context.Context, id uint64) error {
+	deleted, err := svc.repo.Delete(ctx, id)
+	if err != nil {
+		return apperror.New("OAUTH.020", ErrDeleteClient, err)
+	}
+
+	if !deleted {
+		return apperror.New("OAUTH.021", ErrDeleteClient, ErrClientNotExist)
+	}
+
+	return nil
+}
+
+// AuthenticateClient returns client with given client id when given secret matches.
+// Public clients are identified by client id only
+func (svc *oauthService) AuthenticateClient(ctx context.Context, clientID string, secret string) (*models.Client, error) {
+	if clientID == "" {
+		return nil, apperror.New("OAUTH.030", ErrAuthenticateClient, ErrInvalidClient)
+	}
+
+	client, err := svc.repo.GetByClientID(ctx, clientID)
+	if err != nil {
+		return nil, apperror.New("OAUTH.031", ErrAuthenticateClient, err)
+	}
+
+	if client == nil {
+		return nil, apperror.New("OAUTH.032", ErrAuthenticateClient, ErrInvalidClient)
+	}
+
+	if client.Public {
+		if secret != "" {
+			return nil, apperror.New("OAUTH.033", ErrAuthenticateClient, ErrInvalidClient)
+		}
+		return client, nil
+	}
+
+	if secret == "" || subtle.ConstantTimeCompare([]byte(client.SecretHash), []byte(hashSecret(secret))) != 1 {
+		return nil, apperror.New("OAUTH.034", ErrAuthenticateClient, ErrInvalidClient)
+	}
+
+	return client, nil
+}
+
+// Consent validates authorization request of given user and describes what user is asked to approve
+func (svc *oauthService) Consent(ctx context.Context, userID uint64, req *models.AuthorizationRequest) (*models.Consent, error) {
+	client, err := svc.repo.GetByClientID(ctx, req.ClientID)
+	if err != nil {
+		return nil, apperror.New("OAUTH.070", ErrAuthorize, err)
+	}
+
+	if client == nil {
+		return nil, apperror.New("OAUTH.071", ErrAuthorize, ErrClientNotExist)
+	}
+
+	// redirect URI is verified first, client is never redirected to unregistered URI
+	if !client.HasRedirectURI(req.RedirectURI) {
+		return nil, apperror.New("OAUTH.072", ErrAuthorize, ErrInvalidRedirectURI)
+	}
+
+	if req.ResponseType != models.ResponseTypeCode {
+		return nil, apperror.New("OAUTH.073", ErrAuthorize, ErrUnsupportedResponseType)
+	}
+
+	if !client.HasGrantType(models.GrantTypeAuthorizationCode) {
+		return nil, apperror.New("OAUTH.074", ErrAuthorize, ErrUnauthorizedClient)
+	}
+
+	// PKCE is required for all clients, so intercepted code can not be exchanged
+	if req.CodeChallengeMethod != models.CodeChallengeMethodS256 || !isValidCodeVerifier(req.CodeChallenge) {
+		return nil, apperror.New("OAUTH.075", ErrAuthorize, ErrInvalidCodeChallenge)
+	}
+
+	requested := strings.Fields(req.Scope)
+	if len(requested) == 0 {
+		requested = client.Scopes
+	}
+
+	if !client.AllowsScope(requested...) {
+		return nil, apperror.New("OAUTH.076", ErrAuthorize, ErrInvalidScope)
+	}
+
+	granted, err := svc.grantedScope(ctx, userID)
+	if err != nil {
+		return nil, apperror.New("OAUTH.077", ErrAuthorize, err)
+	}
+
+	// user can consent only to scope values granted to them
+	scope := intersectScope(requested, granted)
+	if len(scope) == 0 {
+		return nil, apperror.New("OAUTH.078", ErrAuthorize, ErrInvalidScope)
+	}
+
+	return &models.Consent{
+		ClientID:    client.ClientID,
+		ClientName:  client.Name,
+		Scope:       scope,
+		RedirectURI: req.RedirectURI,
+	}, nil
+}
+
+// Approve completes authorization request of given user. Approved request redirects client with
+// authorization code, denied request redirects client with access_denied error
+func (svc *oauthService) Approve(ctx context.Context, userID uint64, req *models.AuthorizationRequest, approved bool, clientIP string, userAgent string) (redirect *models.ConsentRedirect, err error) {
+	consent, err := svc.Consent(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	if req.State != "" {
+		params.Set("state", req.State)
+	}
+
+	if !approved {
+		params.Set("error", models.ErrorAccessDenied)
+		return newConsentRedirect(consent.RedirectURI, params)
+	}
+
+	event := &audit.Event{Event: audit.EventOAuthConsent, UserID: userID, ClientIP: clientIP, UserAgent: userAgent}
+	if metaErr := event.SetMeta(map[string]interface{}{"clientId": consent.ClientID, "scope": consent.Scope}); metaErr != nil {
+		svc.requestLogger(ctx).Error(metaErr)
+	}
+	defer func() {
+		svc.recordEvent(ctx, event, err)
+	}()
+
+	meta, err := json.Marshal(&models.AuthorizationCodeMeta{
+		ClientID:      consent.ClientID,
+		RedirectURI:   consent.RedirectURI,
+		Scope:         strings.Join(consent.Scope, " "),
+		CodeChallenge: req.CodeChallenge,
+	})
+	if err != nil {
+		return nil, apperror.New("OAUTH.080", ErrAuthorize, err)
+	}
+
+	code, err := svc.tokensService.CreateAuthorizationCode(ctx, userID, string(meta))
+	if err != nil {
+		return nil, apperror.New("OAUTH.081", ErrAuthorize, err)
+	}
+
+	params.Set("code", code.Token)
+	return newConsentRedirect(consent.RedirectURI, params)
+}
+
+// Token issues tokens to given authenticated client for authorization_code, refresh_token
+// and client_credentials grants
+func (svc *oauthService) Token(ctx context.Context, client *models.Client, req *models.TokenRequest) (*models.TokenResponse, error) {
+	switch req.GrantType {
+	case models.GrantTypeAuthorizationCode, models.GrantTypeRefreshToken, models.GrantTypeClientCredentials:
+	default:
+		return nil, apperror.New("OAUTH.100", ErrIssueTokens, ErrUnsupportedGrantType)
+	}
+
+	if !client.HasGrantType(req.GrantType) {
+		return nil, apperror.New("OAUTH.101", ErrIssueTokens, ErrUnauthorizedClient)
+	}
+
+	switch req.GrantType {
+	case models.GrantTypeAuthorizationCode:
+		return svc.exchangeAuthorizationCode(ctx, client, req)
+	case models.GrantTypeRefreshToken:
+		return svc.exchangeRefreshToken(ctx, client, req)
+	default:
+		return svc.issueClientToken(client, req)
+	}
+}
+
+// exchangeAuthorizationCode issues tokens for authorization code issued to given client.
+// Code can be exchanged only once, with code verifier matching code challenge of authorization request
+func (svc *oauthService) exchangeAuthorizationCode(ctx context.Context, client *models.Client, req *models.TokenRequest) (*models.TokenResponse, error) {
+	if req.Code == "" || req.RedirectURI == "" || req.CodeVerifier == "" {
+		return nil, apperror.New("OAUTH.110", ErrIssueTokens, ErrInvalidRequest)
+	}
+
+	code, err := svc.tokensService.GetAuthorizationCode(ctx, req.Code)
+	if err != nil {
+		return nil, apperror.New("OAUTH.111", ErrIssueTokens, ErrInvalidGrant)
+	}
+
+	meta := new(models.AuthorizationCodeMeta)
+	if err := json.Unmarshal([]byte(code.Meta), meta); err != nil {
+		return nil, apperror.New("OAUTH.112", ErrIssueTokens, err)
+	}
+
+	// other clients can not destroy the code by presenting it
+	if meta.ClientID != client.ClientID {
+		return nil, apperror.New("OAUTH.113", ErrIssueTokens, ErrInvalidGrant)
+	}
+
+	// code is single use, only request which removed it can exchange it. Code is removed outside of request
+	// transaction, which is rolled back on error, so wrong redirect URI or code verifier guess burns the code
+	consumed, err := svc.tokensService.ConsumeAuthorizationCode(db.DetachTxContext(ctx), code)
+	if err != nil {
+		return nil, apperror.New("OAUTH.114", ErrIssueTokens, err)
+	}
+
+	if !consumed {
+		return nil, apperror.New("OAUTH.115", ErrIssueTokens, ErrInvalidGrant)
+	}
+
+	if meta.RedirectURI != req.RedirectURI || !verifyCodeChallenge(req.CodeVerifier, meta.CodeChallenge) {
+		return nil, apperror.New("OAUTH.116", ErrIssueTokens, ErrInvalidGrant)
+	}
+
+	scope, err := svc.userScope(ctx, code.UserID, strings.Fields(meta.Scope))
+	if err != nil {
+		return nil, apperror.New("OAUTH.117", ErrIssueTokens, err)
+	}
+
+	res, err := svc.issueUserTokens(code.UserID, scope)
+	if err != nil {
+		return nil, apperror.New("OAUTH.118", ErrIssueTokens, err)
+	}
+
+	if !client.HasGrantType(models.GrantTypeRefreshToken) {
+		return res, nil
+	}
+
+	refreshMeta, err := (&tokens.RefreshTokenMeta{
+		ClientID:  client.ClientID,
+		Scope:     meta.Scope,
+		ClientIP:  req.ClientIP,
+		UserAgent: req.UserAgent,
+	}).Encode()
+	if err != nil {
+		return nil, apperror.New("OAUTH.119", ErrIssueTokens, err)
+	}
+
+	// create and store refresh token which starts new token family
+	token, err := svc.tokensService.CreateRefreshToken(ctx, code.UserID, refreshMeta, svc.jwt.SessionLifetime(scope...))
+	if err != nil {
+		return nil, apperror.New("OAUTH.120", ErrIssueTokens, err)
+	}
+
+	if res.RefreshToken, err = svc.jwt.GenerateRefreshToken(token.Token, token.ExpiresAt); err != nil {
+		return nil, apperror.New("OAUTH.121", ErrIssueTokens, err)
+	}
+
+	return res, nil
+}
+
+// exchangeRefreshToken rotates refresh token issued to given client and issues new tokens.
+// Requested scope can narrow, but never extend, scope consented by user
+func (svc *oauthService) exchangeRefreshToken(ctx context.Context, client *models.Client, req *models.TokenRequest) (*models.TokenResponse, error) {
+	value, err := svc.jwt.VerifyRefreshToken(req.RefreshToken)
+	if err != nil || value == "" {
+		return nil, apperror.New("OAUTH.122", ErrIssueTokens, ErrInvalidGrant)
+	}
+
+	token, err := svc.tokensService.GetRefreshToken(ctx, value)
+	if err != nil {
+		return nil, apperror.New("OAUTH.123", ErrIssueTokens, ErrInvalidGrant)
+	}
+
+	meta, err := token.RefreshTokenMeta()
+	if err != nil {
+		return nil, apperror.New("OAUTH.124", ErrIssueTokens, err)
+	}
+
+	if meta.ClientID != client.ClientID {
+		return nil, apperror.New("OAUTH.125", ErrIssueTokens, ErrInvalidGrant)
+	}
+
+	consented := strings.Fields(meta.Scope)
+	requested := strings.Fields(req.Scope)
+	if len(requested) == 0 {
+		requested = consented
+	}
+
+	if len(intersectScope(requested, jwt.ParseScope(meta.Scope))) != len(requested) {
+		return nil, apperror.New("OAUTH.126", ErrIssueTokens, ErrInvalidScope)
+	}
+
+	scope, err := svc.userScope(ctx, token.UserID, requested)
+	if err != nil {
+		return nil, apperror.New("OAUTH.127", ErrIssueTokens, err)
+	}
+
+	// exchange presented token for the new one within the same family
+	newToken, err := svc.tokensService.RotateRefreshToken(ctx, token, svc.jwt.SessionLifetime(scope...), req.ClientIP, req.UserAgent)
+	if err != nil {
+		if errors.Is(err, tokens.ErrRefreshTokenReused) {
+			svc.revokeReusedToken(ctx, token, meta)
+			return nil, apperror.New("OAUTH.128", ErrIssueTokens, ErrInvalidGrant)
+		}
+		return nil, apperror.New("OAUTH.129", ErrIssueTokens, err)
+	}
+
+	res, err := svc.issueUserTokens(token.UserID, scope)
+	if err != nil {
+		return nil, apperror.New("OAUTH.130", ErrIssueTokens, err)
+	}
+
+	if res.RefreshToken, err = svc.jwt.GenerateRefreshToken(newToken.Token, newToken.ExpiresAt); err != nil {
+		return nil, apperror.New("OAUTH.131", ErrIssueTokens, err)
+	}
+
+	return res, nil
+}
+
+// revokeReusedToken revokes whole family of reused refresh token.
+// Reused token indicates that token was stolen, so neither party can continue using the family
+func (svc *oauthService) revokeReusedToken(ctx context.Context, token *tokens.Token, meta *tokens.RefreshTokenMeta) {
+	logger := svc.requestLogger(ctx).WithFields(log.Fields{
+		"event":     "refresh_token_reuse",
+		"user_id":   token.UserID,
+		"token":     token.ID,
+		"family":    meta.Family,
+		"client_id": meta.ClientID,
+	})
+	logger.Warn("security-event")
+
+	// request transaction is rolled back on error response, so family is revoked outside of it
+	if err := svc.tokensService.RevokeRefreshTokenFamily(db.DetachTxContext(ctx), token.UserID, meta.Family); err != nil {
+		logger.Error(err)
+	}
+}
+
+// issueClientToken issues access token to client itself, refresh token is not issued
+func (svc *oauthService) issueClientToken(client *models.Client, req *models.TokenRequest) (*models.TokenResponse, error) {
+	if client.Public {
+		return nil, apperror.New("OAUTH.132", ErrIssueTokens, ErrUnauthorizedClient)
+	}
+
+	scope := strings.Fields(req.Scope)
+	if len(scope) == 0 {
+		scope = client.Scopes
+	}
+
+	if !client.AllowsScope(scope...) {
+		return nil, apperror.New("OAUTH.133", ErrIssueTokens, ErrInvalidScope)
+	}
+
+	accessToken, err := svc.jwt.GenerateClientAccessToken(client.ClientID, scope...)
+	if err != nil {
+		return nil, apperror.New("OAUTH.134", ErrIssueTokens, err)
+	}
+
+	return &models.TokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(svc.jwt.AccessTokenLifetime().Seconds()),
+		Scope:       strings.Join(scope, " "),
+	}, nil
+}
+
+// issueUserTokens generates access token with given scope for given user
+func (svc *oauthService) issueUserTokens(userID uint64, scope []string) (*models.TokenResponse, error) {
+	accessToken, err := svc.jwt.GenerateAccessToken(userID, scope...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(svc.jwt.AccessTokenLifetime(scope...).Seconds()),
+		Scope:       strings.Join(scope, " "),
+	}, nil
+}
+
+// userScope returns part of given consented scope which is still granted to given user.
+// Roles or permissions revoked from user after consent are no longer granted through the client
+func (svc *oauthService) userScope(ctx context.Context, userID uint64, consented []string) ([]string, error) {
+	// deleted users can not use tokens issued before account was removed
+	if _, err := svc.usersService.GetByID(ctx, userID); err != nil {
+		if errors.Is(err, userServices.ErrUserNotExist) {
+			return nil, ErrInvalidGrant
+		}
+		return nil, err
+	}
+
+	granted, err := svc.grantedScope(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	scope := intersectScope(consented, granted)
+	if len(scope) == 0 {
+		return nil, ErrInvalidScope
+	}
+	return scope, nil
+}
+
+// grantedScope returns scope granted to given user, holding effective roles and permissions
+func (svc *oauthService) grantedScope(ctx context.Context, userID uint64) (jwt.Scope, error) {
+	roles, err := svc.rolesService.GetEffectiveRoles(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions, err := svc.rolesService.GetUserPermissions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	scope := jwt.Scope{jwt.ScopeAuthorized: {}}
+	for _, role := range roles {
+		scope[role.Name] = struct{}{}
+	}
+	for _, permission := range permissions {
+		scope[permission] = struct{}{}
+	}
+	return scope, nil
+}
+
+// Introspect describes given access token, refresh token or API key.
+// Token of hinted type is tried first, invalid, expired and revoked tokens are reported as inactive
+func (svc *oauthService) Introspect(ctx context.Context, token string, hint string) *models.Introspection {
+	for _, typ := range orderTokenTypes(hint) {
+		var info *models.Introspection
+		switch typ {
+		case TokenTypeHintAccessToken:
+			info = svc.introspectAccessToken(token)
+		case TokenTypeHintRefreshToken:
+			info = svc.introspectRefreshToken(ctx, token)
+		case TokenTypeHintAPIKey:
+			info = svc.introspectAPIKey(ctx, token)
+		}
+
+		if info != nil {
+			return info
+		}
+	}
+
+	return &models.Introspection{Active: false}
+}
+
+// introspectAccessToken describes given access token, it returns nil for invalid token
+func (svc *oauthService) introspectAccessToken(token string) *models.Introspection {
+	if clientID, scope, err := svc.jwt.VerifyClientAccessToken(token); err == nil {
+		return &models.Introspection{
+			Active:    true,
+			TokenType: TokenTypeHintAccessToken,
+			Scope:     scope,
+			ClientID:  clientID,
+			Sub:       clientID,
+		}
+	}
+
+	userID, scope, err := svc.jwt.VerifyAccessToken(token)
+	if err != nil {
+		return nil
+	}
+
+	return &models.Introspection{
+		Active:    true,
+		TokenType: TokenTypeHintAccessToken,
+		Scope:     scope,
+		Sub:       strconv.FormatUint(userID, 10),
+	}
+}
+
+// introspectRefreshToken describes given refresh token, it returns nil for invalid or rotated token
+func (svc *oauthService) introspectRefreshToken(ctx context.Context, token string) *models.Introspection {
+	stored, err := svc.refreshToken(ctx, token)
+	if err != nil {
+		return nil
+	}
+
+	meta, err := stored.RefreshTokenMeta()
+	if err != nil {
+		return nil
+	}
+
+	return &models.Introspection{
+		Active:    true,
+		TokenType: TokenTypeHintRefreshToken,
+		Scope:     meta.Scope,
+		ClientID:  meta.ClientID,
+		Sub:       strconv.FormatUint(stored.UserID, 10),
+		Exp:       stored.ExpiresAt.Unix(),
+	}
+}
+
+// introspectAPIKey describes given API key, it returns nil for invalid or expired key
+func (svc *oauthService) introspectAPIKey(ctx context.Context, key string) *models.Introspection {
+	userID, scopes, err := svc.apiKeysService.VerifyAPIKey(ctx, key)
+	if err != nil {
+		return nil
+	}
+
+	return &models.Introspection{
+		Active:    true,
+		TokenType: TokenTypeHintAPIKey,
+		Scope:     strings.Join(scopes, " "),
+		Sub:       strconv.FormatUint(userID, 10),
+	}
+}
+
+// Revoke revokes given access token, refresh token or API key on behalf of given authenticated client.
+// Refresh tokens and client access tokens issued to other client are refused (RFC 7009 section 2.1),
+// user access tokens and API keys are not bound to any client.
+// Revoking refresh token terminates the whole session. Invalid tokens are ignored
+func (svc *oauthService) Revoke(ctx context.Context, client *models.Client, token string, hint string) error {
+	for _, typ := range orderTokenTypes(hint) {
+		switch typ {
+		case TokenTypeHintAccessToken:
+			if clientID, _, err := svc.jwt.VerifyClientAccessToken(token); err == nil {
+				if clientID != client.ClientID {
+					return apperror.New("OAUTH.044", ErrRevokeToken, ErrTokenNotIssuedToClient)
+				}
+			} else if _, _, err := svc.jwt.VerifyAccessToken(token); err != nil {
+				continue
+			}
+
+			if err := svc.jwt.RevokeAccessToken(token); err != nil {
+				return apperror.New("OAUTH.040", ErrRevokeToken, err)
+			}
+			return nil
+		case TokenTypeHintRefreshToken:
+			stored, err := svc.refreshToken(ctx, token)
+			if err != nil {
+				continue
+			}
+
+			meta, err := stored.RefreshTokenMeta()
+			if err != nil {
+				return apperror.New("OAUTH.041", ErrRevokeToken, err)
+			}
+
+			if meta.ClientID != client.ClientID {
+				return apperror.New("OAUTH.045", ErrRevokeToken, ErrTokenNotIssuedToClient)
+			}
+
+			if err := svc.tokensService.RevokeRefreshTokenFamily(ctx, stored.UserID, meta.Family); err != nil {
+				return apperror.New("OAUTH.042", ErrRevokeToken, err)
+			}
+			return nil
+		case TokenTypeHintAPIKey:
+			if err := svc.apiKeysService.RevokeKey(ctx, token); err != nil {
+				if errors.Is(err, apikeys.ErrInvalidAPIKey) {
+					continue
+				}
+				return apperror.New("OAUTH.043", ErrRevokeToken, err)
+			}
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// refreshToken returns stored token for given refresh token when it is neither rotated nor expired
+func (svc *oauthService) refreshToken(ctx context.Context, token string) (*tokens.Token, error) {
+	value, err := svc.jwt.VerifyRefreshToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := svc.tokensService.GetRefreshToken(ctx, value)
+	if err != nil {
+		return nil, err
+	}
+
+	meta, err := stored.RefreshTokenMeta()
+	if err != nil {
+		return nil, err
+	}
+
+	if meta.RotatedAt != nil || !time.Now().Before(stored.ExpiresAt) {
+		return nil, tokens.ErrFetchRefreshToken
+	}
+
+	return stored, nil
+}
+
+// orderTokenTypes returns supported token types with hinted type first.
+// Unknown hint is ignored, so all token types are tried
+func orderTokenTypes(hint string) []string {
+	types := []string{hint}
+	for _, typ := range tokenTypeHints {
+		if typ != hint {
+			types = append(types, typ)
+		}
+	}
+
+	if len(types) > len(tokenTypeHints) {
+		return types[1:]
+	}
+	return types
+}
+
+// validateClient checks redirect URIs and grant types of given client
+func validateClient(client *models.Client) error {
+	for _, grantType := range client.GrantTypes {
+		supported := false
+		for _, value := range supportedGrantTypes {
+			supported = supported || value == grantType
+		}
+
+		if !supported {
+			return ErrInvalidGrantTypes
+		}
+	}
+
+	// public clients can not keep secret, so they can not act on their own behalf
+	if client.Public && client.HasGrantType(models.GrantTypeClientCredentials) {
+		return ErrInvalidGrantTypes
+	}
+
+	// refresh tokens are issued only together with authorization code exchange
+	if client.HasGrantType(models.GrantTypeRefreshToken) && !client.HasGrantType(models.GrantTypeAuthorizationCode) {
+		return ErrInvalidGrantTypes
+	}
+
+	if client.HasGrantType(models.GrantTypeAuthorizationCode) && len(client.RedirectURIs) == 0 {
+		return ErrInvalidRedirectURI
+	}
+
+	// redirect URIs and scopes are stored space separated
+	for _, uri := range client.RedirectURIs {
+		u, err := url.Parse(uri)
+		if err != nil || !u.IsAbs() || u.Host == "" || u.Fragment != "" || strings.ContainsAny(uri, " \t\n") {
+			return ErrInvalidRedirectURI
+		}
+	}
+
+	for _, scope := range client.Scopes {
+		if len(strings.Fields(scope)) != 1 {
+			return ErrInvalidScope
+		}
+	}
+
+	return nil
+}
+
+// newConsentRedirect adds given authorization response parameters to redirect URI
+func newConsentRedirect(redirectURI string, params url.Values) (*models.ConsentRedirect, error) {
+	u, err := url.Parse(redirectURI)
+	if err != nil {
+		return nil, err
+	}
+
+	query := u.Query()
+	for key := range params {
+		query.Set(key, params.Get(key))
+	}
+	u.RawQuery = query.Encode()
+
+	return &models.ConsentRedirect{RedirectURI: u.String()}, nil
+}
+
+// intersectScope returns values of given scope which are granted, keeping their order
+func intersectScope(scope []string, granted jwt.Scope) []string {
+	result := make([]string, 0, len(scope))
+	for _, value := range scope {
+		if granted.Has(value) {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// isValidCodeVerifier checks PKCE code verifier or S256 code challenge format
+func isValidCodeVerifier(value string) bool {
+	if len(value) < minCodeVerifierLength || len(value) > maxCodeVerifierLength {
+		return false
+	}
+
+	for _, c := range value {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.ContainsRune("-._~", c)) {
+			return false
+		}
+	}
+	return true
+}
+
+// verifyCodeChallenge checks that given code verifier matches S256 code challenge
+func verifyCodeChallenge(verifier string, challenge string) bool {
+	if !isValidCodeVerifier(verifier) {
+		return false
+	}
+
+	sum := sha256.Sum256([]byte(verifier))
+	expected := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(challenge)) == 1
+}
+
+// randomString returns URL safe random string generated from given number of random bytes
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashSecret returns hash of given client secret.
+// Client secrets are random, so fast hash function is sufficient
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
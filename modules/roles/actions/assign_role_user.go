@@ -0,0 +1,92 @@
+package actions
+
+import (
+	"api/modules/roles/models"
+	"api/modules/roles/services"
+	"api/pkg/apperror"
+	"api/pkg/userip"
+	"api/providers/binding"
+	"api/providers/jwt"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// AssignRoleUser request object
+type AssignRoleUser struct {
+	UserID        uint64     `json:"userId" binding:"required"`
+	ResourceScope string     `json:"resourceScope" binding:"max=100"`
+	ExpiresAt     *time.Time `json:"expiresAt"`
+}
+
+type AssignRoleUserAction struct {
+	vm                vm.Transformer
+	binder            binding.Binder
+	auth              jwt.TokenAuth
+	rolesAdminService services.RolesAdminService
+}
+
+func NewAssignRoleUserAction(vm vm.Transformer, binder binding.Binder, auth jwt.TokenAuth, rolesAdminService services.RolesAdminService) *AssignRoleUserAction {
+	return &AssignRoleUserAction{
+		vm:                vm,
+		binder:            binder,
+		auth:              auth,
+		rolesAdminService: rolesAdminService,
+	}
+}
+
+func (a *AssignRoleUserAction) Method() string {
+	return http.MethodPost
+}
+
+func (a *AssignRoleUserAction) Path() string {
+	return "/:id/users"
+}
+
+func (a *AssignRoleUserAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{
+		a.auth.RequirePermissions(models.PermissionRolesWrite),
+	}
+}
+
+// Handle assigns role to user
+// @Summary Assigns role to user
+// @Description Assignment can be limited to resource scope (e.g. organisation ID) and expiration time.
+// @Description Expired assignments are ignored and periodically removed
+// @Produce json
+// @Tags roles
+// @Security BearerAuth
+// @Param id path int true "Role ID"
+// @Param req body AssignRoleUser true "Assign Role User Request"
+// @Success 200 {object} vm.Response
+// @Failure 400 {object} vm.ResponseError
+// @Router /roles/{id}/users [post]
+func (a *AssignRoleUserAction) Handle(r *http.Request) flow.Response {
+	id, err := strconv.ParseUint(flow.ParamsFromContext(r.Context()).ByName("id"), 10, 64)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	var reqObj AssignRoleUser
+	if err := a.binder.Bind(r, &reqObj); err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	adminID, err := a.auth.RequestUserID(r)
+	if err != nil {
+		return a.vm.Error(http.StatusUnauthorized, err)
+	}
+
+	assignment := &models.Assignment{UserID: reqObj.UserID, RoleID: id, ResourceScope: reqObj.ResourceScope, ExpiresAt: reqObj.ExpiresAt}
+
+	err = a.rolesAdminService.AssignUser(r.Context(), adminID, assignment, userip.Get(r), r.UserAgent())
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, nil)
+}
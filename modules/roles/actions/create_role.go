@@ -0,0 +1,77 @@
+package actions
+
+import (
+	"api/modules/roles/models"
+	"api/modules/roles/services"
+	"api/pkg/apperror"
+	"api/providers/binding"
+	"api/providers/jwt"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// CreateRole request object
+type CreateRole struct {
+	Name        string  `json:"name" binding:"required,max=45"`
+	Description string  `json:"description" binding:"max=255"`
+	ParentID    *uint64 `json:"parentId"`
+}
+
+type CreateRoleAction struct {
+	vm           vm.Transformer
+	auth         jwt.TokenAuth
+	binder       binding.Binder
+	rolesService services.RolesService
+}
+
+func NewCreateRoleAction(vm vm.Transformer, auth jwt.TokenAuth, binder binding.Binder, rolesService services.RolesService) *CreateRoleAction {
+	return &CreateRoleAction{
+		vm:           vm,
+		auth:         auth,
+		binder:       binder,
+		rolesService: rolesService,
+	}
+}
+
+func (a *CreateRoleAction) Method() string {
+	return http.MethodPost
+}
+
+func (a *CreateRoleAction) Path() string {
+	return "/"
+}
+
+func (a *CreateRoleAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{
+		a.auth.RequirePermissions(models.PermissionRolesWrite),
+	}
+}
+
+// Handle creates role
+// @Summary Creates role
+// @Description Role name is used as access token scope, so it has to be unique and can not contain whitespace or `:`.
+// @Description Names Authorized and Unconfirmed are reserved.
+// @Description Role inherits everything parent role can do
+// @Produce json
+// @Tags roles
+// @Security BearerAuth
+// @Param req body CreateRole true "Create Role Request"
+// @Success 200 {object} models.Role
+// @Failure 400 {object} vm.ResponseError
+// @Router /roles/ [post]
+func (a *CreateRoleAction) Handle(r *http.Request) flow.Response {
+	var reqObj CreateRole
+	if err := a.binder.Bind(r, &reqObj); err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	role := &models.Role{Name: reqObj.Name, Description: reqObj.Description, ParentID: reqObj.ParentID}
+	if err := a.rolesService.Create(r.Context(), role); err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, role)
+}
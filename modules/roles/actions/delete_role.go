@@ -0,0 +1,74 @@
+package actions
+
+import (
+	"api/modules/roles/models"
+	"api/modules/roles/services"
+	"api/pkg/apperror"
+	"api/providers/jwt"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-flow/flow/v2"
+)
+
+type DeleteRoleAction struct {
+	vm           vm.Transformer
+	auth         jwt.TokenAuth
+	rolesService services.RolesService
+}
+
+func NewDeleteRoleAction(vm vm.Transformer, auth jwt.TokenAuth, rolesService services.RolesService) *DeleteRoleAction {
+	return &DeleteRoleAction{
+		vm:           vm,
+		auth:         auth,
+		rolesService: rolesService,
+	}
+}
+
+func (a *DeleteRoleAction) Method() string {
+	return http.MethodDelete
+}
+
+func (a *DeleteRoleAction) Path() string {
+	return "/:id"
+}
+
+func (a *DeleteRoleAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{
+		a.auth.RequirePermissions(models.PermissionRolesWrite),
+	}
+}
+
+// Handle deletes role
+// @Summary Deletes role and removes it from all users
+// @Description Built-in roles (Admin, User) can not be deleted
+// @Produce json
+// @Tags roles
+// @Security BearerAuth
+// @Param id path int true "Role ID"
+// @Success 200 {object} vm.Response
+// @Failure 400 {object} vm.ResponseError
+// @Router /roles/{id} [delete]
+func (a *DeleteRoleAction) Handle(r *http.Request) flow.Response {
+	id, err := strconv.ParseUint(flow.ParamsFromContext(r.Context()).ByName("id"), 10, 64)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	role, err := a.rolesService.GetByID(r.Context(), id)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	if role == nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("ROLES.132", services.ErrDeleteRole, services.ErrRoleNotExist))
+	}
+
+	if err := a.rolesService.Delete(r.Context(), role); err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, nil)
+}
@@ -0,0 +1,63 @@
+package actions
+
+import (
+	"api/modules/roles/services"
+	"api/pkg/apperror"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-flow/flow/v2"
+)
+
+type RoleAction struct {
+	vm           vm.Transformer
+	rolesService services.RolesService
+}
+
+func NewRoleAction(vm vm.Transformer, rolesService services.RolesService) *RoleAction {
+	return &RoleAction{
+		vm:           vm,
+		rolesService: rolesService,
+	}
+}
+
+func (a *RoleAction) Method() string {
+	return http.MethodGet
+}
+
+func (a *RoleAction) Path() string {
+	return "/:id"
+}
+
+func (a *RoleAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle returns role
+// @Summary Returns role
+// @Produce json
+// @Tags roles
+// @Security BearerAuth
+// @Param id path int true "Role ID"
+// @Success 200 {object} models.Role
+// @Failure 400 {object} vm.ResponseError
+// @Router /roles/{id} [get]
+func (a *RoleAction) Handle(r *http.Request) flow.Response {
+	id, err := strconv.ParseUint(flow.ParamsFromContext(r.Context()).ByName("id"), 10, 64)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	role, err := a.rolesService.GetByID(r.Context(), id)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	if role == nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("ROLES.130", services.ErrFetchRole, services.ErrRoleNotExist))
+	}
+
+	return a.vm.Success(http.StatusOK, role)
+}
@@ -0,0 +1,59 @@
+package actions
+
+import (
+	"api/modules/roles/services"
+	"api/pkg/apperror"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-flow/flow/v2"
+)
+
+type RolePermissionsAction struct {
+	vm           vm.Transformer
+	rolesService services.RolesService
+}
+
+func NewRolePermissionsAction(vm vm.Transformer, rolesService services.RolesService) *RolePermissionsAction {
+	return &RolePermissionsAction{
+		vm:           vm,
+		rolesService: rolesService,
+	}
+}
+
+func (a *RolePermissionsAction) Method() string {
+	return http.MethodGet
+}
+
+func (a *RolePermissionsAction) Path() string {
+	return "/:id/permissions"
+}
+
+func (a *RolePermissionsAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle lists role permissions
+// @Summary Lists permissions granted to role
+// @Produce json
+// @Tags roles
+// @Security BearerAuth
+// @Param id path int true "Role ID"
+// @Success 200 {object} []models.Permission
+// @Failure 400 {object} vm.ResponseError
+// @Router /roles/{id}/permissions [get]
+func (a *RolePermissionsAction) Handle(r *http.Request) flow.Response {
+	id, err := strconv.ParseUint(flow.ParamsFromContext(r.Context()).ByName("id"), 10, 64)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	permissions, err := a.rolesService.GetPermissions(r.Context(), id)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, permissions)
+}
@@ -0,0 +1,64 @@
+package actions
+
+import (
+	"api/modules/roles/services"
+	"api/pkg/apperror"
+	"api/pkg/paging"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-flow/flow/v2"
+)
+
+type RoleUsersAction struct {
+	vm           vm.Transformer
+	rolesService services.RolesService
+}
+
+func NewRoleUsersAction(vm vm.Transformer, rolesService services.RolesService) *RoleUsersAction {
+	return &RoleUsersAction{
+		vm:           vm,
+		rolesService: rolesService,
+	}
+}
+
+func (a *RoleUsersAction) Method() string {
+	return http.MethodGet
+}
+
+func (a *RoleUsersAction) Path() string {
+	return "/:id/users"
+}
+
+func (a *RoleUsersAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle lists users with role
+// @Summary Lists users which have role assigned
+// @Produce json
+// @Tags roles
+// @Security BearerAuth
+// @Param id path int true "Role ID"
+// @Param page query int false "Page"
+// @Param per_page query int false "Results per page"
+// @Success 200 {object} paging.Model{results=[]models.RoleUser}
+// @Failure 400 {object} vm.ResponseError
+// @Router /roles/{id}/users [get]
+func (a *RoleUsersAction) Handle(r *http.Request) flow.Response {
+	id, err := strconv.ParseUint(flow.ParamsFromContext(r.Context()).ByName("id"), 10, 64)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	paginator := paging.NewPaginatorFromParams(r.URL.Query())
+
+	users, err := a.rolesService.GetUsers(r.Context(), id, paginator)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, &paging.Model{Results: users, Paginator: paginator})
+}
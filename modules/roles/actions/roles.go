@@ -0,0 +1,57 @@
+package actions
+
+import (
+	"api/modules/roles/services"
+	"api/pkg/paging"
+	"api/providers/vm"
+	"net/http"
+
+	"github.com/go-flow/flow/v2"
+)
+
+type RolesAction struct {
+	vm           vm.Transformer
+	rolesService services.RolesService
+}
+
+func NewRolesAction(vm vm.Transformer, rolesService services.RolesService) *RolesAction {
+	return &RolesAction{
+		vm:           vm,
+		rolesService: rolesService,
+	}
+}
+
+func (a *RolesAction) Method() string {
+	return http.MethodGet
+}
+
+func (a *RolesAction) Path() string {
+	return "/"
+}
+
+func (a *RolesAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle lists roles
+// @Summary Lists roles
+// @Produce json
+// @Tags roles
+// @Security BearerAuth
+// @Param page query int false "Page"
+// @Param per_page query int false "Results per page"
+// @Param order_by query string false "Order by (id, name, createdAt)"
+// @Param order_dir query string false "Order direction (asc, desc)"
+// @Success 200 {object} paging.Model{results=[]models.Role}
+// @Failure 400 {object} vm.ResponseError
+// @Router /roles/ [get]
+func (a *RolesAction) Handle(r *http.Request) flow.Response {
+	paginator := paging.NewPaginatorFromParams(r.URL.Query())
+
+	roles, err := a.rolesService.Find(r.Context(), paginator)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, &paging.Model{Results: roles, Paginator: paginator})
+}
@@ -0,0 +1,82 @@
+package actions
+
+import (
+	"api/modules/roles/models"
+	"api/modules/roles/services"
+	"api/pkg/apperror"
+	"api/pkg/userip"
+	"api/providers/jwt"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-flow/flow/v2"
+)
+
+type UnassignRoleUserAction struct {
+	vm                vm.Transformer
+	auth              jwt.TokenAuth
+	rolesAdminService services.RolesAdminService
+}
+
+func NewUnassignRoleUserAction(vm vm.Transformer, auth jwt.TokenAuth, rolesAdminService services.RolesAdminService) *UnassignRoleUserAction {
+	return &UnassignRoleUserAction{
+		vm:                vm,
+		auth:              auth,
+		rolesAdminService: rolesAdminService,
+	}
+}
+
+func (a *UnassignRoleUserAction) Method() string {
+	return http.MethodDelete
+}
+
+func (a *UnassignRoleUserAction) Path() string {
+	return "/:id/users/:userId"
+}
+
+func (a *UnassignRoleUserAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{
+		a.auth.RequirePermissions(models.PermissionRolesWrite),
+	}
+}
+
+// Handle removes role from user
+// @Summary Removes role from user
+// @Produce json
+// @Tags roles
+// @Security BearerAuth
+// @Param id path int true "Role ID"
+// @Param userId path int true "User ID"
+// @Param resourceScope query string false "Resource scope of the assignment, global assignment is removed when omitted"
+// @Success 200 {object} vm.Response
+// @Failure 400 {object} vm.ResponseError
+// @Router /roles/{id}/users/{userId} [delete]
+func (a *UnassignRoleUserAction) Handle(r *http.Request) flow.Response {
+	params := flow.ParamsFromContext(r.Context())
+
+	id, err := strconv.ParseUint(params.ByName("id"), 10, 64)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	userID, err := strconv.ParseUint(params.ByName("userId"), 10, 64)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	adminID, err := a.auth.RequestUserID(r)
+	if err != nil {
+		return a.vm.Error(http.StatusUnauthorized, err)
+	}
+
+	assignment := &models.Assignment{UserID: userID, RoleID: id, ResourceScope: r.URL.Query().Get("resourceScope")}
+
+	err = a.rolesAdminService.UnassignUser(r.Context(), adminID, assignment, userip.Get(r), r.UserAgent())
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, nil)
+}
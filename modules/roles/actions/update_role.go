@@ -0,0 +1,107 @@
+package actions
+
+import (
+	"api/modules/roles/models"
+	"api/modules/roles/services"
+	"api/pkg/apperror"
+	"api/providers/binding"
+	"api/providers/jwt"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// UpdateRole request object
+type UpdateRole struct {
+	Name        *string `json:"name" binding:"omitempty,min=1,max=45"`
+	Description *string `json:"description" binding:"omitempty,max=255"`
+	ParentID    *uint64 `json:"parentId"`
+}
+
+type UpdateRoleAction struct {
+	vm           vm.Transformer
+	auth         jwt.TokenAuth
+	binder       binding.Binder
+	rolesService services.RolesService
+}
+
+func NewUpdateRoleAction(vm vm.Transformer, auth jwt.TokenAuth, binder binding.Binder, rolesService services.RolesService) *UpdateRoleAction {
+	return &UpdateRoleAction{
+		vm:           vm,
+		auth:         auth,
+		binder:       binder,
+		rolesService: rolesService,
+	}
+}
+
+func (a *UpdateRoleAction) Method() string {
+	return http.MethodPut
+}
+
+func (a *UpdateRoleAction) Path() string {
+	return "/:id"
+}
+
+func (a *UpdateRoleAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{
+		a.auth.RequirePermissions(models.PermissionRolesWrite),
+	}
+}
+
+// Handle updates role
+// @Summary Updates role, omitted fields are not changed
+// @Description Built-in roles (Admin, User) can not be renamed. Name can not contain whitespace or `:`,
+// @Description names Authorized and Unconfirmed are reserved. Role can not inherit from itself,
+// @Description directly or through parent roles. Parent ID 0 removes role parent
+// @Produce json
+// @Tags roles
+// @Security BearerAuth
+// @Param id path int true "Role ID"
+// @Param req body UpdateRole true "Update Role Request"
+// @Success 200 {object} models.Role
+// @Failure 400 {object} vm.ResponseError
+// @Router /roles/{id} [put]
+func (a *UpdateRoleAction) Handle(r *http.Request) flow.Response {
+	id, err := strconv.ParseUint(flow.ParamsFromContext(r.Context()).ByName("id"), 10, 64)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	var reqObj UpdateRole
+	if err := a.binder.Bind(r, &reqObj); err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	role, err := a.rolesService.GetByID(r.Context(), id)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	if role == nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("ROLES.131", services.ErrUpdateRole, services.ErrRoleNotExist))
+	}
+
+	if reqObj.Name != nil {
+		role.Name = *reqObj.Name
+	}
+
+	if reqObj.Description != nil {
+		role.Description = *reqObj.Description
+	}
+
+	if reqObj.ParentID != nil {
+		role.ParentID = reqObj.ParentID
+		if *reqObj.ParentID == 0 {
+			role.ParentID = nil
+		}
+	}
+
+	if err := a.rolesService.Update(r.Context(), role); err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, role)
+}
@@ -1,12 +0,0 @@
-package roles
-
-import "time"
-
-// Role Entity
-type Role struct {
-	ID          uint64    `json:"id"`
-	Name        string    `json:"name" binding:"required"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-}
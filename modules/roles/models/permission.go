@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Permissions granted through roles and required by routers and actions
+const (
+	// PermissionUsersRead allows listing and viewing users
+	PermissionUsersRead = "users:read"
+	// PermissionUsersWrite allows updating, deleting, restoring and unlocking users
+	PermissionUsersWrite = "users:write"
+	// PermissionRolesRead allows listing and viewing roles
+	PermissionRolesRead = "roles:read"
+	// PermissionRolesWrite allows managing roles and role assignments
+	PermissionRolesWrite = "roles:write"
+	// PermissionInvitationsRead allows listing invitations
+	PermissionInvitationsRead = "invitations:read"
+	// PermissionInvitationsWrite allows inviting users and managing invitations
+	PermissionInvitationsWrite = "invitations:write"
+	// PermissionKeysWrite allows rotating token signing keys
+	PermissionKeysWrite = "keys:write"
+	// PermissionClientsRead allows listing OAuth clients
+	PermissionClientsRead = "clients:read"
+	// PermissionClientsWrite allows registering and removing OAuth clients
+	PermissionClientsWrite = "clients:write"
+)
+
+// Permission Entity
+type Permission struct {
+	ID          uint64    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
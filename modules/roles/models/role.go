@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// UserRole enum
+type UserRole uint64
+
+const (
+	// UserRoleAdmin admin role
+	UserRoleAdmin UserRole = 1
+	// UserRoleUser user role
+	UserRoleUser UserRole = 2
+)
+
+// Role Entity
+type Role struct {
+	ID          uint64    `json:"id"`
+	Name        string    `json:"name" binding:"required"`
+	Description string    `json:"description"`
+	ParentID    *uint64   `json:"parentId"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// IsBuiltIn checks if role is created by the application.
+// Built-in roles are referenced by ID, so they can not be renamed or deleted
+func (r *Role) IsBuiltIn() bool {
+	return r.ID == uint64(UserRoleAdmin) || r.ID == uint64(UserRoleUser)
+}
+
+// Assignment holds role assigned to user.
+// Assignment without resource scope applies globally, assignment without expiration time is permanent
+type Assignment struct {
+	UserID        uint64     `json:"userId"`
+	RoleID        uint64     `json:"roleId"`
+	ResourceScope string     `json:"resourceScope"`
+	ExpiresAt     *time.Time `json:"expiresAt"`
+	CreatedAt     time.Time  `json:"createdAt"`
+}
+
+// IsExpired checks if assignment expiration time has passed
+func (a *Assignment) IsExpired() bool {
+	return a.ExpiresAt != nil && !a.ExpiresAt.After(time.Now())
+}
+
+// RoleUser holds user which has role assigned
+type RoleUser struct {
+	UserID        uint64     `json:"userId"`
+	FirstName     string     `json:"firstName"`
+	LastName      string     `json:"lastName"`
+	Email         string     `json:"email"`
+	ResourceScope string     `json:"resourceScope"`
+	ExpiresAt     *time.Time `json:"expiresAt"`
+	AssignedAt    time.Time  `json:"assignedAt"`
+}
@@ -1,7 +1,10 @@
 package roles
 
 import (
-	"api/routers"
+	"api/modules/audit"
+	"api/modules/roles/repositories"
+	"api/modules/roles/routers"
+	"api/modules/roles/services"
 
 	"github.com/go-flow/flow/v2"
 )
@@ -17,18 +20,21 @@ func NewModule() *Module {
 
 func (m *Module) ProvideImports() []flow.Provider {
 	return []flow.Provider{
-		flow.NewProvider(NewRolesRepository),
+		flow.NewProvider(repositories.NewRolesRepository),
 	}
 }
 
 func (m *Module) ProvideExports() []flow.Provider {
 	return []flow.Provider{
-		flow.NewProvider(NewRolesService),
+		flow.NewProvider(services.NewRolesService),
+		flow.NewProvider(services.NewRolesAdminService),
 	}
 }
 
 func (m *Module) ProvideModules() []flow.Provider {
-	return []flow.Provider{}
+	return []flow.Provider{
+		flow.NewProvider(audit.NewModule),
+	}
 }
 
 func (m *Module) ProvideRouters() []flow.Provider {
@@ -0,0 +1,644 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"api/modules/roles/models"
+	"api/providers/db"
+)
+
+// orderColumns holds columns roles listing can be ordered by
+var orderColumns = map[string]string{
+	"id":        "id",
+	"name":      "name",
+	"createdAt": "created_at",
+}
+
+// RolesRepository interface
+type RolesRepository interface {
+	// RolesRepository returns service interface
+	RolesRepository() string
+
+	// Count returns number of records in database
+	Count(ctx context.Context) (int, error)
+
+	// Save saves given role object
+	Save(ctx context.Context, role *models.Role) error
+
+	// Update updates role object
+	Update(ctx context.Context, role *models.Role) error
+
+	// Create role
+	Create(ctx context.Context, role *models.Role) error
+
+	// GetByID returns role object from database for given id
+	GetByID(ctx context.Context, id uint64) (*models.Role, error)
+
+	// GetByName returns role object from database for given name
+	GetByName(ctx context.Context, name string) (*models.Role, error)
+
+	// GetAll returns all Role objects for given params
+	GetAll(ctx context.Context, page int, perPage int, orderBy string, orderDir string) ([]*models.Role, error)
+
+	// Delete role from database
+	Delete(ctx context.Context, role *models.Role) error
+
+	// Delete role from database
+	DeleteByID(ctx context.Context, id uint64) error
+
+	// GetByUserID returns roles assigned to user globally, expired assignments are ignored
+	GetByUserID(ctx context.Context, userID uint64) ([]*models.Role, error)
+
+	// GetByUserIDInScope returns roles assigned to user globally or within given resource scope,
+	// expired assignments are ignored
+	GetByUserIDInScope(ctx context.Context, userID uint64, resourceScope string) ([]*models.Role, error)
+
+	// Assign assignes role to user
+	Assign(ctx context.Context, userID uint64, roleID uint64) error
+
+	// Unassign removes role from user
+	Unassign(ctx context.Context, userID uint64, roleID uint64) error
+
+	// GetAssignment returns active role assignment for given user, role and resource scope
+	GetAssignment(ctx context.Context, userID uint64, roleID uint64, resourceScope string) (*models.Assignment, error)
+
+	// CreateAssignment stores role assignment, expired assignment with the same key is replaced
+	CreateAssignment(ctx context.Context, assignment *models.Assignment) error
+
+	// DeleteAssignment removes role assignment
+	DeleteAssignment(ctx context.Context, assignment *models.Assignment) error
+
+	// GetExpiredAssignments returns up to limit role assignments which expired
+	GetExpiredAssignments(ctx context.Context, limit int) ([]*models.Assignment, error)
+
+	// DeleteExpiredAssignment removes role assignment only if it is still expired.
+	// Returns false when assignment was renewed or removed in the meantime
+	DeleteExpiredAssignment(ctx context.Context, assignment *models.Assignment) (bool, error)
+
+	// CountUsers returns number of users with given role
+	CountUsers(ctx context.Context, roleID uint64) (int, error)
+
+	// GetUsers returns users with given role for given params
+	GetUsers(ctx context.Context, roleID uint64, page int, perPage int) ([]*models.RoleUser, error)
+
+	// GetPermissionsByRoleID returns permissions granted to role
+	GetPermissionsByRoleID(ctx context.Context, roleID uint64) ([]*models.Permission, error)
+
+	// GetPermissionsByRoleIDs returns distinct permissions granted to any of given roles
+	GetPermissionsByRoleIDs(ctx context.Context, roleIDs []uint64) ([]*models.Permission, error)
+}
+
+// NewRolesRepository creates RolesRepository interface implementation
+func NewRolesRepository(store db.Store) RolesRepository {
+	return &rolesRepository{
+		store: store,
+	}
+}
+
+type rolesRepository struct {
+	store db.Store
+}
+
+func (r *rolesRepository) getTx(ctx context.Context) (*sql.Tx, bool, error) {
+	var err error
+	// get transaction from context
+	tx, ok := db.TxFromContext(ctx)
+	if !ok {
+		// create new transaction
+		tx, err = r.store.Begin()
+		if err != nil {
+			return nil, false, err
+		}
+		return tx, true, nil
+	}
+	return tx, false, nil
+}
+
+func (rolesRepository) closeTx(tx *sql.Tx, shouldCommit bool, hasError bool) {
+	if shouldCommit {
+		if hasError {
+			tx.Rollback()
+			return
+		}
+		tx.Commit()
+	}
+}
+
+func (r *rolesRepository) RolesRepository() string {
+	return "rolesRepository"
+}
+
+func (r *rolesRepository) Count(ctx context.Context) (int, error) {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	query := "SELECT COUNT(id) as count FROM roles"
+
+	var count int
+	err = tx.QueryRow(query).Scan(&count)
+
+	r.closeTx(tx, shouldCommit, err != nil)
+
+	return count, err
+}
+
+func (r *rolesRepository) Save(ctx context.Context, role *models.Role) error {
+	if role.ID > 0 {
+		return r.Update(ctx, role)
+	}
+	return r.Create(ctx, role)
+}
+
+func (r *rolesRepository) Update(ctx context.Context, role *models.Role) error {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	// close tx
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := "UPDATE roles SET name = ?, description = ?, parent_id = ? WHERE id = ?"
+	_, err = tx.Exec(query, role.Name, role.Description, role.ParentID, role.ID)
+	role.UpdatedAt = time.Now()
+	return err
+}
+
+func (r *rolesRepository) Create(ctx context.Context, role *models.Role) error {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	// close tx
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := "INSERT INTO roles (name, description, parent_id) VALUES(?,?,?)"
+
+	result, err := tx.Exec(query, role.Name, role.Description, role.ParentID)
+	if err != nil {
+		return err
+	}
+
+	lastID, err := result.LastInsertId()
+
+	if lastID > 0 {
+		role.ID = uint64(lastID)
+		role.CreatedAt = time.Now()
+		role.UpdatedAt = role.CreatedAt
+	}
+
+	return err
+}
+
+func (r *rolesRepository) GetByID(ctx context.Context, id uint64) (*models.Role, error) {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// close tx
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := "SELECT id, name, description, parent_id, created_at, updated_at FROM roles WHERE id = ? "
+
+	// create empty model object
+	model := new(models.Role)
+
+	// execute query statement and scan row to model
+	err = tx.QueryRow(query, id).Scan(&model.ID, &model.Name, &model.Description, &model.ParentID, &model.CreatedAt, &model.UpdatedAt)
+
+	if err != nil && err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return model, err
+}
+
+func (r *rolesRepository) GetByName(ctx context.Context, name string) (*models.Role, error) {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// close tx
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := "SELECT id, name, description, parent_id, created_at, updated_at FROM roles WHERE name = ? "
+
+	// create empty model object
+	model := new(models.Role)
+
+	// execute query statement and scan row to model
+	err = tx.QueryRow(query, name).Scan(&model.ID, &model.Name, &model.Description, &model.ParentID, &model.CreatedAt, &model.UpdatedAt)
+
+	if err != nil && err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return model, err
+}
+
+func (r *rolesRepository) GetAll(ctx context.Context, page int, perPage int, orderBy string, orderDir string) ([]*models.Role, error) {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// close tx
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	offset := (page - 1) * perPage
+
+	column, ok := orderColumns[orderBy]
+	if !ok {
+		column = "id"
+	}
+
+	if strings.ToUpper(orderDir) != "DESC" {
+		orderDir = "ASC"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT 
+			id, name, description, parent_id, created_at, updated_at 
+		FROM roles 
+		ORDER BY %s %s 
+		LIMIT %d OFFSET %d`,
+		column, orderDir, perPage, offset)
+
+	// execute query statement
+	rows, err := tx.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := make([]*models.Role, 0, perPage)
+	// loop over results
+	for rows.Next() {
+		model := new(models.Role)
+		// scan row to model
+		if err = rows.Scan(&model.ID, &model.Name, &model.Description, &model.ParentID, &model.CreatedAt, &model.UpdatedAt); err != nil {
+			return nil, err
+		}
+		roles = append(roles, model)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return roles, err
+}
+
+func (r *rolesRepository) Delete(ctx context.Context, role *models.Role) error {
+	return r.DeleteByID(ctx, role.ID)
+}
+
+func (r *rolesRepository) DeleteByID(ctx context.Context, id uint64) error {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	// close tx
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := "DELETE FROM roles WHERE id = ? "
+	_, err = tx.Exec(query, id)
+	return err
+}
+
+func (r *rolesRepository) GetByUserID(ctx context.Context, userID uint64) ([]*models.Role, error) {
+	return r.GetByUserIDInScope(ctx, userID, "")
+}
+
+func (r *rolesRepository) GetByUserIDInScope(ctx context.Context, userID uint64, resourceScope string) ([]*models.Role, error) {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// close tx
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := `
+		SELECT DISTINCT 
+			r.id, r.name, r.description, r.parent_id, r.created_at, r.updated_at 
+		FROM roles AS r 
+		INNER JOIN users_roles AS ur ON ur.role_id = r.id 
+		WHERE ur.user_id = ? 
+			AND ur.resource_scope IN ('', ?) 
+			AND (ur.expires_at IS NULL OR ur.expires_at > NOW())`
+
+	// create empty model object
+	roles := make([]*models.Role, 0)
+
+	// execute query statement
+	rows, err := tx.Query(query, userID, resourceScope)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	// loop over results
+	for rows.Next() {
+		model := new(models.Role)
+		// scan row to model
+		if err = rows.Scan(&model.ID, &model.Name, &model.Description, &model.ParentID, &model.CreatedAt, &model.UpdatedAt); err != nil {
+			return nil, err
+		}
+		roles = append(roles, model)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return roles, err
+}
+
+func (r *rolesRepository) Assign(ctx context.Context, userID uint64, roleID uint64) error {
+	return r.CreateAssignment(ctx, &models.Assignment{UserID: userID, RoleID: roleID})
+}
+
+func (r *rolesRepository) Unassign(ctx context.Context, userID uint64, roleID uint64) error {
+	return r.DeleteAssignment(ctx, &models.Assignment{UserID: userID, RoleID: roleID})
+}
+
+func (r *rolesRepository) GetAssignment(ctx context.Context, userID uint64, roleID uint64, resourceScope string) (*models.Assignment, error) {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// close tx
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := `
+		SELECT 
+			user_id, role_id, resource_scope, expires_at, created_at 
+		FROM users_roles 
+		WHERE user_id = ? AND role_id = ? AND resource_scope = ? 
+			AND (expires_at IS NULL OR expires_at > NOW())`
+
+	// create empty model object
+	model := new(models.Assignment)
+
+	// execute query statement and scan row to model
+	err = tx.QueryRow(query, userID, roleID, resourceScope).Scan(&model.UserID, &model.RoleID, &model.ResourceScope, &model.ExpiresAt, &model.CreatedAt)
+
+	if err != nil && err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return model, err
+}
+
+func (r *rolesRepository) CreateAssignment(ctx context.Context, assignment *models.Assignment) error {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	// close tx
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := `
+		INSERT INTO users_roles (user_id, role_id, resource_scope, expires_at) VALUES(?,?,?,?) 
+		ON DUPLICATE KEY UPDATE expires_at = VALUES(expires_at), created_at = CURRENT_TIMESTAMP`
+
+	_, err = tx.Exec(query, assignment.UserID, assignment.RoleID, assignment.ResourceScope, assignment.ExpiresAt)
+	if err == nil {
+		assignment.CreatedAt = time.Now()
+	}
+
+	return err
+}
+
+func (r *rolesRepository) DeleteAssignment(ctx context.Context, assignment *models.Assignment) error {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	// close tx
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := "DELETE FROM users_roles WHERE user_id = ? AND role_id = ? AND resource_scope = ?"
+	_, err = tx.Exec(query, assignment.UserID, assignment.RoleID, assignment.ResourceScope)
+
+	return err
+}
+
+func (r *rolesRepository) GetExpiredAssignments(ctx context.Context, limit int) ([]*models.Assignment, error) {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// close tx
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := fmt.Sprintf(`
+		SELECT 
+			user_id, role_id, resource_scope, expires_at, created_at 
+		FROM users_roles 
+		WHERE expires_at IS NOT NULL AND expires_at <= NOW() 
+		ORDER BY expires_at ASC 
+		LIMIT %d`,
+		limit)
+
+	// execute query statement
+	rows, err := tx.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	assignments := make([]*models.Assignment, 0, limit)
+	// loop over results
+	for rows.Next() {
+		model := new(models.Assignment)
+		// scan row to model
+		if err = rows.Scan(&model.UserID, &model.RoleID, &model.ResourceScope, &model.ExpiresAt, &model.CreatedAt); err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, model)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return assignments, err
+}
+
+func (r *rolesRepository) DeleteExpiredAssignment(ctx context.Context, assignment *models.Assignment) (bool, error) {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	// close tx
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := `
+		DELETE FROM users_roles 
+		WHERE user_id = ? AND role_id = ? AND resource_scope = ? 
+			AND expires_at IS NOT NULL AND expires_at <= NOW()`
+
+	result, err := tx.Exec(query, assignment.UserID, assignment.RoleID, assignment.ResourceScope)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func (r *rolesRepository) CountUsers(ctx context.Context, roleID uint64) (int, error) {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// close tx
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := `
+		SELECT 
+			COUNT(u.id) 
+		FROM users AS u 
+		INNER JOIN users_roles AS ur ON ur.user_id = u.id 
+		WHERE ur.role_id = ? AND u.deleted_at IS NULL 
+			AND (ur.expires_at IS NULL OR ur.expires_at > NOW())`
+
+	var count int
+	err = tx.QueryRow(query, roleID).Scan(&count)
+	return count, err
+}
+
+func (r *rolesRepository) GetUsers(ctx context.Context, roleID uint64, page int, perPage int) ([]*models.RoleUser, error) {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// close tx
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	offset := (page - 1) * perPage
+
+	query := fmt.Sprintf(`
+		SELECT 
+			u.id, u.first_name, u.last_name, u.email, ur.resource_scope, ur.expires_at, ur.created_at 
+		FROM users AS u 
+		INNER JOIN users_roles AS ur ON ur.user_id = u.id 
+		WHERE ur.role_id = ? AND u.deleted_at IS NULL 
+			AND (ur.expires_at IS NULL OR ur.expires_at > NOW()) 
+		ORDER BY u.id ASC, ur.resource_scope ASC 
+		LIMIT %d OFFSET %d`,
+		perPage, offset)
+
+	// execute query statement
+	rows, err := tx.Query(query, roleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]*models.RoleUser, 0, perPage)
+	// loop over results
+	for rows.Next() {
+		model := new(models.RoleUser)
+		// scan row to model
+		if err = rows.Scan(&model.UserID, &model.FirstName, &model.LastName, &model.Email, &model.ResourceScope, &model.ExpiresAt, &model.AssignedAt); err != nil {
+			return nil, err
+		}
+		users = append(users, model)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, err
+}
+
+func (r *rolesRepository) GetPermissionsByRoleID(ctx context.Context, roleID uint64) ([]*models.Permission, error) {
+	query := `
+		SELECT 
+			p.id, p.name, p.description, p.created_at, p.updated_at 
+		FROM permissions AS p 
+		INNER JOIN roles_permissions AS rp ON rp.permission_id = p.id 
+		WHERE rp.role_id = ? 
+		ORDER BY p.name ASC`
+
+	return r.queryPermissions(ctx, query, roleID)
+}
+
+func (r *rolesRepository) GetPermissionsByRoleIDs(ctx context.Context, roleIDs []uint64) ([]*models.Permission, error) {
+	if len(roleIDs) == 0 {
+		return []*models.Permission{}, nil
+	}
+
+	placeholders := make([]string, 0, len(roleIDs))
+	args := make([]interface{}, 0, len(roleIDs))
+	for _, id := range roleIDs {
+		placeholders = append(placeholders, "?")
+		args = append(args, id)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT DISTINCT 
+			p.id, p.name, p.description, p.created_at, p.updated_at 
+		FROM permissions AS p 
+		INNER JOIN roles_permissions AS rp ON rp.permission_id = p.id 
+		WHERE rp.role_id IN (%s) 
+		ORDER BY p.name ASC`,
+		strings.Join(placeholders, ","))
+
+	return r.queryPermissions(ctx, query, args...)
+}
+
+// queryPermissions executes given permissions query and scans results
+func (r *rolesRepository) queryPermissions(ctx context.Context, query string, args ...interface{}) ([]*models.Permission, error) {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// close tx
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	// execute query statement
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	permissions := make([]*models.Permission, 0)
+	// loop over results
+	for rows.Next() {
+		model := new(models.Permission)
+		// scan row to model
+		if err = rows.Scan(&model.ID, &model.Name, &model.Description, &model.CreatedAt, &model.UpdatedAt); err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, model)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return permissions, err
+}
@@ -0,0 +1,51 @@
+package routers
+
+import (
+	"api/modules/roles/actions"
+	"api/modules/roles/models"
+	"api/providers/jwt"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// Router handles roles administration actions
+type Router struct {
+	auth jwt.TokenAuth
+}
+
+func NewRouter(auth jwt.TokenAuth) *Router {
+	return &Router{
+		auth: auth,
+	}
+}
+
+// Path defined http path for router
+func (r *Router) Path() string {
+	return "/roles"
+}
+
+// Middlewares provides list of middlewares used by the router
+func (r *Router) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{
+		r.auth.AuthorizeRequest(jwt.ScopeAuthorized),
+		r.auth.RequirePermissions(models.PermissionRolesRead),
+	}
+}
+
+func (r *Router) ProvideHandlers() []flow.Provider {
+	return []flow.Provider{
+		flow.NewProvider(actions.NewRolesAction),
+		flow.NewProvider(actions.NewRoleAction),
+		flow.NewProvider(actions.NewCreateRoleAction),
+		flow.NewProvider(actions.NewUpdateRoleAction),
+		flow.NewProvider(actions.NewDeleteRoleAction),
+		flow.NewProvider(actions.NewRoleUsersAction),
+		flow.NewProvider(actions.NewRolePermissionsAction),
+		flow.NewProvider(actions.NewAssignRoleUserAction),
+		flow.NewProvider(actions.NewUnassignRoleUserAction),
+	}
+}
+
+func (r *Router) RegisterSubRouters() bool {
+	return false
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"api/modules/audit"
+	"api/modules/roles/models"
+	"api/pkg/apperror"
+	"api/providers/log"
+)
+
+var (
+	// ErrRoleAssigned error is returned when role is already assigned to user
+	ErrRoleAssigned = errors.New("role is already assigned to user")
+
+	// ErrRoleNotAssigned error is returned when role is not assigned to user
+	ErrRoleNotAssigned = errors.New("role is not assigned to user")
+)
+
+// sweepBatchSize is number of expired role assignments removed in one sweeper query
+const sweepBatchSize = 100
+
+// RolesAdminService interface provides role assignment operations for administrators
+type RolesAdminService interface {
+	// RolesAdminService returns service implementation signature
+	RolesAdminService() string
+
+	// AssignUser assigns role to user and records role change, adminID is 0 for assignments made by the application.
+	// Assignment can be limited to resource scope and expiration time
+	AssignUser(ctx context.Context, adminID uint64, assignment *models.Assignment, clientIP string, userAgent string) error
+
+	// UnassignUser removes role assignment from user and records role change
+	UnassignUser(ctx context.Context, adminID uint64, assignment *models.Assignment, clientIP string, userAgent string) error
+
+	// SweepExpiredAssignments removes expired role assignments and records role change for each of them
+	SweepExpiredAssignments(ctx context.Context) error
+}
+
+// NewRolesAdminService creates RolesAdminService interface implementation
+func NewRolesAdminService(rolesService RolesService, auditService audit.AuditService, logger log.Logger) RolesAdminService {
+	return &rolesAdminService{
+		rolesService: rolesService,
+		auditService: auditService,
+		logger:       logger,
+	}
+}
+
+type rolesAdminService struct {
+	rolesService RolesService
+	auditService audit.AuditService
+	logger       log.Logger
+}
+
+func (svc *rolesAdminService) RolesAdminService() string {
+	return "rolesAdminService"
+}
+
+// AssignUser assigns role to user and records role change.
+// Assignment can be limited to resource scope and expiration time
+func (svc *rolesAdminService) AssignUser(ctx context.Context, adminID uint64, assignment *models.Assignment, clientIP string, userAgent string) (err error) {
+	defer func() {
+		svc.recordRoleChange(ctx, adminID, assignment, "assign", clientIP, userAgent, err)
+	}()
+
+	assigned, err := svc.isAssigned(ctx, assignment)
+	if err != nil {
+		return apperror.New("ROLES.110", ErrAssignRole, err)
+	}
+
+	if assigned {
+		return apperror.New("ROLES.111", ErrAssignRole, ErrRoleAssigned)
+	}
+
+	// missing user is reported by users_roles foreign key
+	if err := svc.rolesService.CreateAssignment(ctx, assignment); err != nil {
+		return apperror.New("ROLES.112", ErrAssignRole, err)
+	}
+
+	return nil
+}
+
+// UnassignUser removes role assignment from user and records role change
+func (svc *rolesAdminService) UnassignUser(ctx context.Context, adminID uint64, assignment *models.Assignment, clientIP string, userAgent string) (err error) {
+	defer func() {
+		svc.recordRoleChange(ctx, adminID, assignment, "unassign", clientIP, userAgent, err)
+	}()
+
+	assigned, err := svc.isAssigned(ctx, assignment)
+	if err != nil {
+		return apperror.New("ROLES.120", ErrUnassignRole, err)
+	}
+
+	if !assigned {
+		return apperror.New("ROLES.121", ErrUnassignRole, ErrRoleNotAssigned)
+	}
+
+	if err := svc.rolesService.DeleteAssignment(ctx, assignment); err != nil {
+		return apperror.New("ROLES.122", ErrUnassignRole, err)
+	}
+
+	return nil
+}
+
+// SweepExpiredAssignments removes expired role assignments and records role change for each of them
+func (svc *rolesAdminService) SweepExpiredAssignments(ctx context.Context) error {
+	for {
+		assignments, err := svc.rolesService.GetExpiredAssignments(ctx, sweepBatchSize)
+		if err != nil {
+			return apperror.New("ROLES.220", ErrDeleteAssignment, err)
+		}
+
+		for _, assignment := range assignments {
+			deleted, err := svc.rolesService.DeleteExpiredAssignment(ctx, assignment)
+			if err != nil {
+				return apperror.New("ROLES.221", ErrDeleteAssignment, err)
+			}
+
+			// assignment renewed in the meantime is kept
+			if deleted {
+				svc.recordRoleChange(ctx, 0, assignment, "expire", "", "", nil)
+			}
+		}
+
+		if len(assignments) < sweepBatchSize {
+			return nil
+		}
+	}
+}
+
+// isAssigned checks if existing role is assigned to user within assignment resource scope
+func (svc *rolesAdminService) isAssigned(ctx context.Context, assignment *models.Assignment) (bool, error) {
+	role, err := svc.rolesService.GetByID(ctx, assignment.RoleID)
+	if err != nil {
+		return false, err
+	}
+
+	if role == nil {
+		return false, ErrRoleNotExist
+	}
+
+	existing, err := svc.rolesService.GetAssignment(ctx, assignment.UserID, role.ID, assignment.ResourceScope)
+	if err != nil {
+		return false, err
+	}
+
+	return existing != nil, nil
+}
+
+// recordRoleChange stores role change event, adminID is 0 for changes made by the application.
+// Failure to record event is logged, so it does not change outcome of the operation
+func (svc *rolesAdminService) recordRoleChange(ctx context.Context, adminID uint64, assignment *models.Assignment, action string, clientIP string, userAgent string, err error) {
+	logger := svc.logger
+	if l, ok := log.FromContext(ctx); ok {
+		logger = l
+	}
+
+	meta := map[string]interface{}{"action": action, "roleId": assignment.RoleID}
+	if adminID > 0 {
+		meta["by"] = adminID
+	}
+	if assignment.ResourceScope != "" {
+		meta["resourceScope"] = assignment.ResourceScope
+	}
+	if assignment.ExpiresAt != nil {
+		meta["expiresAt"] = assignment.ExpiresAt
+	}
+
+	event := &audit.Event{Event: audit.EventRoleChange, UserID: assignment.UserID, ClientIP: clientIP, UserAgent: userAgent}
+	if metaErr := event.SetMeta(meta); metaErr != nil {
+		logger.Error(metaErr)
+	}
+
+	if recErr := svc.auditService.Record(ctx, event, err); recErr != nil {
+		logger.Error(recErr)
+	}
+}
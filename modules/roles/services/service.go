@@ -0,0 +1,488 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode"
+
+	"api/modules/roles/models"
+	"api/modules/roles/repositories"
+	"api/pkg/apperror"
+	"api/pkg/paging"
+	"api/providers/jwt"
+)
+
+var (
+	// ErrSaveRole error is returned when role object could not be saved
+	ErrSaveRole = errors.New("unable to save role")
+
+	// ErrNilRole error is returned when role object is nil where it should not be
+	ErrNilRole = errors.New("role object is nil")
+
+	// ErrUpdateRole error is returned when role object could not be updated
+	ErrUpdateRole = errors.New("unable to update role")
+
+	// ErrCreateRole error is returned when new role object could not be created
+	ErrCreateRole = errors.New("unable to create role")
+
+	// ErrFetchRole error is returned when role object could not be retrieved
+	ErrFetchRole = errors.New("unable to fetch role")
+
+	// ErrFetchRoles error is returned when roles collection could not be retrieved
+	ErrFetchRoles = errors.New("unable to fetch roles")
+
+	// ErrDeleteRole error is returned when role could not be deleted
+	ErrDeleteRole = errors.New("unable to delete role")
+
+	// ErrAssignRole error is returned when role could not be assigned
+	ErrAssignRole = errors.New("unable to assign role")
+
+	// ErrUnassignRole error is returned when role could not be un-assigned
+	ErrUnassignRole = errors.New("unable to un-assign role")
+
+	// ErrFetchRoleUsers error is returned when users with role could not be retrieved
+	ErrFetchRoleUsers = errors.New("unable to fetch role users")
+
+	// ErrRoleNotExist error is returned when role does not exist
+	ErrRoleNotExist = errors.New("role does not exist")
+
+	// ErrRoleExists error is returned when role with the same name already exists
+	ErrRoleExists = errors.New("role already exists")
+
+	// ErrInvalidRoleName error is returned when role name holds whitespace or `:`,
+	// which would make it ambiguous with other access token scope values
+	ErrInvalidRoleName = errors.New("role name can not contain whitespace or `:`")
+
+	// ErrReservedRoleName error is returned when role name is one of scope values issued by authentication
+	ErrReservedRoleName = errors.New("role name is reserved")
+
+	// ErrBuiltInRole error is returned when built-in role is renamed or deleted
+	ErrBuiltInRole = errors.New("built-in role can not be renamed or deleted")
+
+	// ErrParentRoleNotExist error is returned when parent role does not exist
+	ErrParentRoleNotExist = errors.New("parent role does not exist")
+
+	// ErrRoleCycle error is returned when parent role would make role inherit from itself
+	ErrRoleCycle = errors.New("role can not inherit from itself")
+
+	// ErrFetchAssignments error is returned when role assignments could not be retrieved
+	ErrFetchAssignments = errors.New("unable to fetch role assignments")
+
+	// ErrDeleteAssignment error is returned when role assignment could not be removed
+	ErrDeleteAssignment = errors.New("unable to remove role assignment")
+
+	// ErrAssignmentExpired error is returned when role assignment expiration time is not in the future
+	ErrAssignmentExpired = errors.New("role assignment expiration time has to be in the future")
+
+	// ErrFetchPermissions error is returned when permissions could not be retrieved
+	ErrFetchPermissions = errors.New("unable to fetch permissions")
+)
+
+// RolesService interface
+type RolesService interface {
+	// RolesService returns service interface
+	RolesService() string
+
+	// Count returns number of records in database
+	Count(ctx context.Context) (int, error)
+
+	// Save saves given role object
+	Save(ctx context.Context, role *models.Role) error
+
+	// Update updates role object
+	Update(ctx context.Context, role *models.Role) error
+
+	// Create role
+	Create(ctx context.Context, role *models.Role) error
+
+	// GetByID returns role object from database for given id
+	GetByID(ctx context.Context, id uint64) (*models.Role, error)
+
+	// GetAll returns all Role objects for given params
+	GetAll(ctx context.Context, page int, perPage int, orderBy string, orderDir string) ([]*models.Role, error)
+
+	// Find retrieves roles for given pagination params
+	Find(ctx context.Context, paginator *paging.Paginator) ([]*models.Role, error)
+
+	// GetUsers retrieves users with given role for given pagination params
+	GetUsers(ctx context.Context, roleID uint64, paginator *paging.Paginator) ([]*models.RoleUser, error)
+
+	// Delete removes role from database
+	Delete(ctx context.Context, role *models.Role) error
+
+	// GetByUserID returns all roles assigned to user
+	GetByUserID(ctx context.Context, userID uint64) ([]*models.Role, error)
+
+	// Assign assigns role to user
+	Assign(ctx context.Context, userID uint64, roleID uint64) error
+
+	// Unassign unnasigns role from user
+	Unassign(ctx context.Context, userID uint64, roleID uint64) error
+
+	// GetPermissions returns permissions granted to role
+	GetPermissions(ctx context.Context, roleID uint64) ([]*models.Permission, error)
+
+	// GetUserPermissions returns names of effective permissions granted to user through effective roles
+	GetUserPermissions(ctx context.Context, userID uint64) ([]string, error)
+
+	// GetEffectiveRoles returns roles assigned to user globally together with all roles they inherit from
+	GetEffectiveRoles(ctx context.Context, userID uint64) ([]*models.Role, error)
+
+	// GetEffectiveRolesInScope returns roles assigned to user globally or within given resource scope
+	// together with all roles they inherit from
+	GetEffectiveRolesInScope(ctx context.Context, userID uint64, resourceScope string) ([]*models.Role, error)
+
+	// GetAssignment returns active role assignment for given user, role and resource scope
+	GetAssignment(ctx context.Context, userID uint64, roleID uint64, resourceScope string) (*models.Assignment, error)
+
+	// CreateAssignment assigns role to user, optionally within resource scope and until expiration time
+	CreateAssignment(ctx context.Context, assignment *models.Assignment) error
+
+	// DeleteAssignment removes role assignment
+	DeleteAssignment(ctx context.Context, assignment *models.Assignment) error
+
+	// GetExpiredAssignments returns up to limit role assignments which expired
+	GetExpiredAssignments(ctx context.Context, limit int) ([]*models.Assignment, error)
+
+	// DeleteExpiredAssignment removes role assignment only if it is still expired
+	DeleteExpiredAssignment(ctx context.Context, assignment *models.Assignment) (bool, error)
+}
+
+// NewRolesService creates RolesService interface implementation
+func NewRolesService(repository repositories.RolesRepository) RolesService {
+	return &rolesService{
+		repo: repository,
+	}
+}
+
+type rolesService struct {
+	repo repositories.RolesRepository
+}
+
+func (svc *rolesService) RolesService() string {
+	return "rolesService"
+}
+
+func (svc *rolesService) Count(ctx context.Context) (int, error) {
+	return svc.repo.Count(ctx)
+}
+
+func (svc *rolesService) Save(ctx context.Context, role *models.Role) error {
+	if role == nil {
+		return apperror.New("ROLES.000", ErrSaveRole, ErrNilRole)
+	}
+	if role.ID > 0 {
+		return svc.Update(ctx, role)
+	}
+	return svc.Create(ctx, role)
+}
+
+func (svc *rolesService) Update(ctx context.Context, role *models.Role) error {
+	if role == nil {
+		return apperror.New("ROLES.010", ErrUpdateRole, ErrNilRole)
+	}
+
+	existing, err := svc.repo.GetByID(ctx, role.ID)
+	if err != nil {
+		return apperror.New("ROLES.012", ErrUpdateRole, err)
+	}
+	if existing == nil {
+		return apperror.New("ROLES.013", ErrUpdateRole, ErrRoleNotExist)
+	}
+
+	if existing.Name != role.Name {
+		// role names are used as access token scopes
+		if role.IsBuiltIn() {
+			return apperror.New("ROLES.014", ErrUpdateRole, ErrBuiltInRole)
+		}
+		if err := svc.checkName(ctx, role.Name); err != nil {
+			return apperror.New("ROLES.015", ErrUpdateRole, err)
+		}
+	}
+
+	if err := svc.checkParent(ctx, role); err != nil {
+		return apperror.New("ROLES.016", ErrUpdateRole, err)
+	}
+
+	if err := svc.repo.Update(ctx, role); err != nil {
+		return apperror.New("ROLES.011", ErrUpdateRole, err)
+	}
+
+	return nil
+}
+
+func (svc *rolesService) Create(ctx context.Context, role *models.Role) error {
+	if role == nil {
+		return apperror.New("ROLES.020", ErrCreateRole, ErrNilRole)
+	}
+	if err := svc.checkName(ctx, role.Name); err != nil {
+		return apperror.New("ROLES.022", ErrCreateRole, err)
+	}
+	if err := svc.checkParent(ctx, role); err != nil {
+		return apperror.New("ROLES.023", ErrCreateRole, err)
+	}
+	if err := svc.repo.Create(ctx, role); err != nil {
+		return apperror.New("ROLES.021", ErrCreateRole, err)
+	}
+	return nil
+}
+
+func (svc *rolesService) GetByID(ctx context.Context, id uint64) (*models.Role, error) {
+	role, err := svc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, apperror.New("ROLES.030", ErrFetchRole, err)
+	}
+
+	return role, nil
+}
+
+func (svc *rolesService) GetAll(ctx context.Context, page int, perPage int, orderBy string, orderDir string) ([]*models.Role, error) {
+	roles, err := svc.repo.GetAll(ctx, page, perPage, orderBy, orderDir)
+	if err != nil {
+		return nil, apperror.New("ROLES.040", ErrFetchRoles, err)
+	}
+	return roles, nil
+}
+
+func (svc *rolesService) Find(ctx context.Context, paginator *paging.Paginator) ([]*models.Role, error) {
+	roles, err := svc.repo.GetAll(ctx, paginator.Page, paginator.PerPage, paginator.OrderBy, paginator.OrderDir)
+	if err != nil {
+		return nil, apperror.New("ROLES.090", ErrFetchRoles, err)
+	}
+
+	count, err := svc.repo.Count(ctx)
+	if err != nil {
+		return nil, apperror.New("ROLES.091", ErrFetchRoles, err)
+	}
+
+	paginator.TotalEntriesSize = count
+	paginator.CurrentEntriesSize = len(roles)
+	paginator.TotalPages = paginator.TotalEntriesSize / paginator.PerPage
+	if paginator.TotalEntriesSize%paginator.PerPage > 0 {
+		paginator.TotalPages = paginator.TotalPages + 1
+	}
+	return roles, nil
+}
+
+func (svc *rolesService) GetUsers(ctx context.Context, roleID uint64, paginator *paging.Paginator) ([]*models.RoleUser, error) {
+	role, err := svc.repo.GetByID(ctx, roleID)
+	if err != nil {
+		return nil, apperror.New("ROLES.100", ErrFetchRoleUsers, err)
+	}
+	if role == nil {
+		return nil, apperror.New("ROLES.101", ErrFetchRoleUsers, ErrRoleNotExist)
+	}
+
+	users, err := svc.repo.GetUsers(ctx, role.ID, paginator.Page, paginator.PerPage)
+	if err != nil {
+		return nil, apperror.New("ROLES.102", ErrFetchRoleUsers, err)
+	}
+
+	count, err := svc.repo.CountUsers(ctx, role.ID)
+	if err != nil {
+		return nil, apperror.New("ROLES.103", ErrFetchRoleUsers, err)
+	}
+
+	paginator.TotalEntriesSize = count
+	paginator.CurrentEntriesSize = len(users)
+	paginator.TotalPages = paginator.TotalEntriesSize / paginator.PerPage
+	if paginator.TotalEntriesSize%paginator.PerPage > 0 {
+		paginator.TotalPages = paginator.TotalPages + 1
+	}
+	return users, nil
+}
+
+func (svc *rolesService) Delete(ctx context.Context, role *models.Role) error {
+	if role == nil {
+		return apperror.New("ROLES.050", ErrDeleteRole, ErrNilRole)
+	}
+	if role.IsBuiltIn() {
+		return apperror.New("ROLES.052", ErrDeleteRole, ErrBuiltInRole)
+	}
+	if err := svc.repo.Delete(ctx, role); err != nil {
+		return apperror.New("ROLES.051", ErrDeleteRole, err)
+	}
+	return nil
+}
+
+func (svc *rolesService) GetByUserID(ctx context.Context, userID uint64) ([]*models.Role, error) {
+	roles, err := svc.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, apperror.New("ROLES.060", ErrFetchRoles, err)
+	}
+	return roles, nil
+}
+
+func (svc *rolesService) Assign(ctx context.Context, userID uint64, roleID uint64) error {
+	if err := svc.repo.Assign(ctx, userID, roleID); err != nil {
+		return apperror.New("ROLES.070", ErrAssignRole, err)
+	}
+	return nil
+}
+
+func (svc *rolesService) Unassign(ctx context.Context, userID uint64, roleID uint64) error {
+	if err := svc.repo.Unassign(ctx, userID, roleID); err != nil {
+		return apperror.New("ROLES.080", ErrUnassignRole, err)
+	}
+	return nil
+}
+
+func (svc *rolesService) GetPermissions(ctx context.Context, roleID uint64) ([]*models.Permission, error) {
+	permissions, err := svc.repo.GetPermissionsByRoleID(ctx, roleID)
+	if err != nil {
+		return nil, apperror.New("ROLES.140", ErrFetchPermissions, err)
+	}
+	return permissions, nil
+}
+
+func (svc *rolesService) GetUserPermissions(ctx context.Context, userID uint64) ([]string, error) {
+	roles, err := svc.GetEffectiveRoles(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	roleIDs := make([]uint64, 0, len(roles))
+	for _, role := range roles {
+		roleIDs = append(roleIDs, role.ID)
+	}
+
+	permissions, err := svc.repo.GetPermissionsByRoleIDs(ctx, roleIDs)
+	if err != nil {
+		return nil, apperror.New("ROLES.150", ErrFetchPermissions, err)
+	}
+
+	names := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		names = append(names, permission.Name)
+	}
+	return names, nil
+}
+
+func (svc *rolesService) GetEffectiveRoles(ctx context.Context, userID uint64) ([]*models.Role, error) {
+	return svc.GetEffectiveRolesInScope(ctx, userID, "")
+}
+
+func (svc *rolesService) GetEffectiveRolesInScope(ctx context.Context, userID uint64, resourceScope string) ([]*models.Role, error) {
+	assigned, err := svc.repo.GetByUserIDInScope(ctx, userID, resourceScope)
+	if err != nil {
+		return nil, apperror.New("ROLES.160", ErrFetchRoles, err)
+	}
+
+	// visited guards against cycles which could exist in data modified outside of the service
+	visited := make(map[uint64]bool)
+	roles := make([]*models.Role, 0, len(assigned))
+	for _, role := range assigned {
+		for role != nil && !visited[role.ID] {
+			visited[role.ID] = true
+			roles = append(roles, role)
+
+			if role.ParentID == nil {
+				break
+			}
+
+			role, err = svc.repo.GetByID(ctx, *role.ParentID)
+			if err != nil {
+				return nil, apperror.New("ROLES.161", ErrFetchRoles, err)
+			}
+		}
+	}
+
+	return roles, nil
+}
+
+func (svc *rolesService) GetAssignment(ctx context.Context, userID uint64, roleID uint64, resourceScope string) (*models.Assignment, error) {
+	assignment, err := svc.repo.GetAssignment(ctx, userID, roleID, resourceScope)
+	if err != nil {
+		return nil, apperror.New("ROLES.170", ErrFetchAssignments, err)
+	}
+	return assignment, nil
+}
+
+func (svc *rolesService) CreateAssignment(ctx context.Context, assignment *models.Assignment) error {
+	if assignment.IsExpired() {
+		return apperror.New("ROLES.180", ErrAssignRole, ErrAssignmentExpired)
+	}
+	if err := svc.repo.CreateAssignment(ctx, assignment); err != nil {
+		return apperror.New("ROLES.181", ErrAssignRole, err)
+	}
+	return nil
+}
+
+func (svc *rolesService) DeleteAssignment(ctx context.Context, assignment *models.Assignment) error {
+	if err := svc.repo.DeleteAssignment(ctx, assignment); err != nil {
+		return apperror.New("ROLES.190", ErrUnassignRole, err)
+	}
+	return nil
+}
+
+func (svc *rolesService) GetExpiredAssignments(ctx context.Context, limit int) ([]*models.Assignment, error) {
+	assignments, err := svc.repo.GetExpiredAssignments(ctx, limit)
+	if err != nil {
+		return nil, apperror.New("ROLES.200", ErrFetchAssignments, err)
+	}
+	return assignments, nil
+}
+
+func (svc *rolesService) DeleteExpiredAssignment(ctx context.Context, assignment *models.Assignment) (bool, error) {
+	deleted, err := svc.repo.DeleteExpiredAssignment(ctx, assignment)
+	if err != nil {
+		return false, apperror.New("ROLES.210", ErrDeleteAssignment, err)
+	}
+	return deleted, nil
+}
+
+// checkName returns error when role name is not valid access token scope value
+// or role with given name already exists
+func (svc *rolesService) checkName(ctx context.Context, name string) error {
+	// scope values are space separated and permissions use `:` separator
+	if strings.IndexFunc(name, func(r rune) bool { return unicode.IsSpace(r) || r == ':' }) >= 0 {
+		return ErrInvalidRoleName
+	}
+
+	if name == jwt.ScopeAuthorized || name == jwt.ScopeUnconfirmed {
+		return ErrReservedRoleName
+	}
+
+	role, err := svc.repo.GetByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	if role != nil {
+		return ErrRoleExists
+	}
+	return nil
+}
+
+// checkParent returns error when role parent does not exist or
+// when role would inherit from itself through the parent chain
+func (svc *rolesService) checkParent(ctx context.Context, role *models.Role) error {
+	if role.ParentID == nil {
+		return nil
+	}
+
+	visited := make(map[uint64]bool)
+	parentID := *role.ParentID
+	for {
+		if role.ID > 0 && parentID == role.ID {
+			return ErrRoleCycle
+		}
+
+		parent, err := svc.repo.GetByID(ctx, parentID)
+		if err != nil {
+			return err
+		}
+		if parent == nil {
+			if parentID == *role.ParentID {
+				return ErrParentRoleNotExist
+			}
+			return nil
+		}
+
+		visited[parent.ID] = true
+		if parent.ParentID == nil || visited[*parent.ParentID] {
+			return nil
+		}
+		parentID = *parent.ParentID
+	}
+}
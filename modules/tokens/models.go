@@ -1,6 +1,9 @@
 package tokens
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // Token model
 type Token struct {
@@ -13,3 +16,57 @@ type Token struct {
 	CreatedAt   time.Time `json:"createdAt"`
 	UpdatedAt   time.Time `json:"updatedAt"`
 }
+
+// RefreshTokenMeta holds refresh token lineage stored in token meta
+type RefreshTokenMeta struct {
+	// Family groups all refresh tokens issued from single login
+	Family string `json:"family"`
+	// ParentID holds ID of the token this token was rotated from
+	ParentID uint64 `json:"parentId,omitempty"`
+	// RotatedAt is set when token is exchanged for a new one
+	RotatedAt *time.Time `json:"rotatedAt,omitempty"`
+	// UserAgent holds user agent of the client which used token family last time
+	UserAgent string `json:"userAgent,omitempty"`
+	// ClientIP holds IP address of the client which used token family last time
+	ClientIP string `json:"clientIp,omitempty"`
+	// StartedAt holds time when token family was created
+	StartedAt *time.Time `json:"startedAt,omitempty"`
+	// LastUsedAt holds time when token family was used last time
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	// ClientID holds id of OAuth client the token family was issued to, it is empty for account logins
+	ClientID string `json:"clientId,omitempty"`
+	// Scope holds scope consented to OAuth client, it limits scope of access tokens issued by the family
+	Scope string `json:"scope,omitempty"`
+}
+
+// RefreshTokenMeta decodes refresh token lineage from token meta
+func (t *Token) RefreshTokenMeta() (*RefreshTokenMeta, error) {
+	meta := new(RefreshTokenMeta)
+	if t.Meta == "" {
+		return meta, nil
+	}
+
+	if err := json.Unmarshal([]byte(t.Meta), meta); err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
+
+// SetRefreshTokenMeta encodes refresh token lineage to token meta
+func (t *Token) SetRefreshTokenMeta(meta *RefreshTokenMeta) error {
+	data, err := meta.Encode()
+	if err != nil {
+		return err
+	}
+	t.Meta = data
+	return nil
+}
+
+// Encode returns JSON encoded refresh token meta
+func (m *RefreshTokenMeta) Encode() (string, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -25,18 +25,29 @@ type TokensRepository interface {
 	// Update token
 	Update(ctx context.Context, token *Token) error
 
+	// MarkRotated sets meta of given refresh token, marked as rotated, only if stored token is not rotated yet.
+	// Returns false if token was rotated or removed concurrently
+	MarkRotated(ctx context.Context, token *Token) (bool, error)
+
 	// Delete token
 	Delete(ctx context.Context, token *Token) error
 
 	// DeleteByID removes token with provided id
 	DeleteByID(ctx context.Context, id uint64) error
 
+	// Consume removes single use token with provided id.
+	// Returns false if token was already removed
+	Consume(ctx context.Context, id uint64) (bool, error)
+
 	// GetByUserID returns all tokens for provided userID
 	GetByUserID(ctx context.Context, userID uint64) ([]*Token, error)
 
 	// GetByUserAndTokenID returns tokens for provided userID and tokenTypeID
 	GetByUserAndTokenID(ctx context.Context, userID uint64, tokenTypeID uint64) ([]*Token, error)
 
+	// GetByTokenTypeID returns all tokens for provided tokenTypeID
+	GetByTokenTypeID(ctx context.Context, tokenTypeID uint64) ([]*Token, error)
+
 	// GetByToken returns token object for provided token string
 	GetByToken(ctx context.Context, token string) (*Token, error)
 
@@ -165,6 +176,31 @@ func (r *tokensRepository) Update(ctx context.Context, token *Token) error {
 	return err
 }
 
+// MarkRotated sets meta of given refresh token, marked as rotated, only if stored token is not rotated yet
+func (r *tokensRepository) MarkRotated(ctx context.Context, token *Token) (bool, error) {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	// stored JSON is normalized by database, so only rotation mark is compared, not the whole document
+	query := "UPDATE tokens SET meta = ? WHERE id = ? AND JSON_EXTRACT(meta, '$.rotatedAt') IS NULL"
+	result, err := tx.Exec(query, token.Meta, token.ID)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	token.UpdatedAt = time.Now()
+	return affected > 0, nil
+}
+
 // Delete token
 func (r *tokensRepository) Delete(ctx context.Context, token *Token) error {
 	return r.DeleteByID(ctx, token.ID)
@@ -184,6 +220,29 @@ func (r *tokensRepository) DeleteByID(ctx context.Context, id uint64) error {
 	return err
 }
 
+// Consume removes single use token with provided id
+func (r *tokensRepository) Consume(ctx context.Context, id uint64) (bool, error) {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := "DELETE FROM tokens WHERE id = ? "
+	result, err := tx.Exec(query, id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
 // GetByUserID returns all tokens for provided userID
 func (r *tokensRepository) GetByUserID(ctx context.Context, userID uint64) ([]*Token, error) {
 	tx, shouldCommit, err := r.getTx(ctx)
@@ -262,6 +321,45 @@ func (r *tokensRepository) GetByUserAndTokenID(ctx context.Context, userID uint6
 	return tokens, nil
 }
 
+// GetByTokenTypeID returns all tokens for provided tokenTypeID
+func (r *tokensRepository) GetByTokenTypeID(ctx context.Context, tokenTypeID uint64) ([]*Token, error) {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := "SELECT id, user_id, token, meta, token_type_id, expires_at, created_at, updated_at FROM tokens WHERE token_type_id = ? ORDER BY id DESC"
+
+	// create empty model object
+	tokens := make([]*Token, 0)
+
+	// execute query statement
+	rows, err := tx.Query(query, tokenTypeID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	// loop over results
+	for rows.Next() {
+		model := new(Token)
+		// scan row to model
+		if err = rows.Scan(&model.ID, &model.UserID, &model.Token, &model.Meta, &model.TokenTypeID, &model.ExpiresAt, &model.CreatedAt, &model.UpdatedAt); err != nil {
+			return nil, err
+		}
+
+		tokens = append(tokens, model)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
 // GetByToken returns token object for provided token string
 func (r *tokensRepository) GetByToken(ctx context.Context, token string) (*Token, error) {
 	tx, shouldCommit, err := r.getTx(ctx)
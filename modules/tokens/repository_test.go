@@ -0,0 +1,143 @@
+package tokens
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"api/migrations"
+	"api/providers/db/migrator"
+
+	"github.com/google/uuid"
+)
+
+// openTestStore connects to MySQL database given by `TEST_DB_DSN` and applies migrations.
+// DSN has to enable `multiStatements` and `parseTime`, test is skipped when it is not set
+func openTestStore(t *testing.T) *sql.DB {
+	dsn := os.Getenv("TEST_DB_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DB_DSN is not set")
+	}
+
+	store, err := sql.Open("mysql", dsn)
+	if err != nil {
+		t.Fatalf("unable to open database: %s", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	if err := migrator.NewFSMigrator(migrations.Data, "mysql", store).Up(); err != nil {
+		t.Fatalf("unable to migrate database: %s", err)
+	}
+	return store
+}
+
+// createRefreshToken stores refresh token of the seeded admin user with given meta
+func createRefreshToken(t *testing.T, repo TokensRepository, meta *RefreshTokenMeta) *Token {
+	token := &Token{
+		UserID:      1,
+		Token:       uuid.New().String(),
+		TokenTypeID: TokenTypeRefresh,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+	if err := token.SetRefreshTokenMeta(meta); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.Create(context.Background(), token); err != nil {
+		t.Fatalf("unable to create token: %s", err)
+	}
+	t.Cleanup(func() { repo.DeleteByID(context.Background(), token.ID) })
+	return token
+}
+
+func TestMarkRotated(t *testing.T) {
+	repo := NewTokensRepository(openTestStore(t))
+	ctx := context.Background()
+
+	startedAt := time.Now().Add(-time.Minute).UTC()
+	token := createRefreshToken(t, repo, &RefreshTokenMeta{
+		Family:    uuid.New().String(),
+		UserAgent: "test",
+		ClientIP:  "127.0.0.1",
+		StartedAt: &startedAt,
+		Scope:     "Authorized User",
+	})
+
+	// meta is read back as normalized by database, like RotateRefreshToken does
+	stored, err := repo.GetByID(ctx, token.ID)
+	if err != nil || stored == nil {
+		t.Fatalf("unable to read token: %v", err)
+	}
+
+	meta, err := stored.RefreshTokenMeta()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	meta.RotatedAt = &now
+	if err := stored.SetRefreshTokenMeta(meta); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := repo.MarkRotated(ctx, stored)
+	if err != nil {
+		t.Fatalf("MarkRotated() error = %s", err)
+	}
+	if !updated {
+		t.Fatal("MarkRotated() = false for token which is not rotated, want true")
+	}
+
+	updated, err = repo.MarkRotated(ctx, stored)
+	if err != nil {
+		t.Fatalf("MarkRotated() error = %s", err)
+	}
+	if updated {
+		t.Fatal("MarkRotated() = true for rotated token, want false")
+	}
+
+	rotated, err := repo.GetByID(ctx, token.ID)
+	if err != nil || rotated == nil {
+		t.Fatalf("unable to read token: %v", err)
+	}
+	if meta, err := rotated.RefreshTokenMeta(); err != nil || meta.RotatedAt == nil {
+		t.Fatalf("stored token is not marked as rotated: %v", err)
+	}
+}
+
+func TestMarkRotatedConcurrently(t *testing.T) {
+	repo := NewTokensRepository(openTestStore(t))
+	ctx := context.Background()
+
+	token := createRefreshToken(t, repo, &RefreshTokenMeta{Family: uuid.New().String()})
+
+	const requests = 5
+	results := make(chan bool, requests)
+	for i := 0; i < requests; i++ {
+		go func() {
+			now := time.Now()
+			rotating := *token
+			if err := rotating.SetRefreshTokenMeta(&RefreshTokenMeta{Family: "concurrent", RotatedAt: &now}); err != nil {
+				t.Error(err)
+			}
+
+			updated, err := repo.MarkRotated(ctx, &rotating)
+			if err != nil {
+				t.Errorf("MarkRotated() error = %s", err)
+			}
+			results <- updated
+		}()
+	}
+
+	count := 0
+	for i := 0; i < requests; i++ {
+		if <-results {
+			count++
+		}
+	}
+
+	if count != 1 {
+		t.Fatalf("token rotated by %d requests, want 1", count)
+	}
+}
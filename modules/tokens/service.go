@@ -26,6 +26,9 @@ const (
 	// TokenTypeDeleteAccount holds db ID value for Delete Account token
 	TokenTypeDeleteAccount = 5
 
+	// TokenTypeAuthorizationCode holds db ID value for OAuth Authorization Code
+	TokenTypeAuthorizationCode = 6
+
 	// PasswordResetTokenDuration holds duration value in minutes for pasword reset token
 	PasswordResetTokenDuration = 30
 
@@ -35,11 +38,11 @@ const (
 	// InvitationTokenDuration holds duration value in minutes for invitation token
 	InvitationTokenDuration = 43200 //30 days
 
-	// RefreshTokenDuration holds duration value in minutes for refresh token
-	RefreshTokenDuration = 43200 // 30 days
-
 	// DeleteAccountTokenDuration holds duration value in minutes for Delete account token
 	DeleteAccountTokenDuration = 15
+
+	// AuthorizationCodeDuration holds duration value in minutes for OAuth authorization code
+	AuthorizationCodeDuration = 5
 )
 
 var (
@@ -77,6 +80,12 @@ var (
 	// ErrFetchDeleteAccountToken error is returned when delete account token could not be retrieved
 	ErrFetchDeleteAccountToken = errors.New("unable to fetch delete account token")
 
+	// ErrCreateAuthorizationCode error is returned when OAuth authorization code could not be created
+	ErrCreateAuthorizationCode = errors.New("unable to create authorization code")
+
+	// ErrFetchAuthorizationCode error is returned when OAuth authorization code could not be retrieved
+	ErrFetchAuthorizationCode = errors.New("unable to fetch authorization code")
+
 	// ErrFetchToken error is returned when token could not be retrieved
 	ErrFetchToken = errors.New("unable to fetch token")
 
@@ -94,6 +103,15 @@ var (
 
 	// ErrDeleteExpiredTokens error is returned when expired tokens could not be deleted
 	ErrDeleteExpiredTokens = errors.New("unable to delete expired tokens")
+
+	// ErrRotateRefreshToken error is returned when refresh token could not be rotated
+	ErrRotateRefreshToken = errors.New("unable to rotate refresh token")
+
+	// ErrRefreshTokenReused error is returned when already rotated refresh token is presented again
+	ErrRefreshTokenReused = errors.New("refresh token reused")
+
+	// ErrRevokeTokenFamily error is returned when refresh token family could not be revoked
+	ErrRevokeTokenFamily = errors.New("unable to revoke refresh token family")
 )
 
 // TokensService interface
@@ -109,6 +127,10 @@ type TokensService interface {
 	// GetPasswordRessetToken retrieves password reset token
 	GetPasswordRessetToken(ctx context.Context, token string) (*Token, error)
 
+	// ConsumePasswordResetToken removes given password reset token.
+	// Returns false if token was already used
+	ConsumePasswordResetToken(ctx context.Context, token *Token) (bool, error)
+
 	// CreateEmailConfirmToken creates email confirmation token for given user
 	// all previous email confirmation tokens are deleted when new token is created
 	CreateEmailConfirmToken(ctx context.Context, userID uint64, meta string) (*Token, error)
@@ -123,13 +145,30 @@ type TokensService interface {
 	// GetInviteToken retrieves user invitation token
 	GetInviteToken(ctx context.Context, token string) (*Token, error)
 
-	// CreateRefreshToken creates refresh token for given user
-	// all previous refresh tokens for given user are removed
-	CreateRefreshToken(ctx context.Context, userID uint64, meta string) (*Token, error)
+	// GetInviteTokens returns invitation tokens of all users, including expired ones
+	GetInviteTokens(ctx context.Context) ([]*Token, error)
+
+	// GetUserInviteToken returns invitation token of given user, including expired one
+	GetUserInviteToken(ctx context.Context, userID uint64) (*Token, error)
+
+	// CreateRefreshToken creates refresh token for given user, valid for given lifetime.
+	// meta holds encoded RefreshTokenMeta, when family is not set token starts new token family
+	CreateRefreshToken(ctx context.Context, userID uint64, meta string, lifetime time.Duration) (*Token, error)
 
 	// GetrefreshToken retrieves refresh token
 	GetRefreshToken(ctx context.Context, token string) (*Token, error)
 
+	// GetActiveRefreshTokens returns refresh tokens of given user which are neither rotated nor expired.
+	// Each active token represents one user session
+	GetActiveRefreshTokens(ctx context.Context, userID uint64) ([]*Token, error)
+
+	// RotateRefreshToken marks given refresh token as rotated and issues new token, valid for given lifetime,
+	// within the same family. ErrRefreshTokenReused is returned if given token was already rotated
+	RotateRefreshToken(ctx context.Context, token *Token, lifetime time.Duration, clientIP string, userAgent string) (*Token, error)
+
+	// RevokeRefreshTokenFamily removes all refresh tokens of given user which belong to given family
+	RevokeRefreshTokenFamily(ctx context.Context, userID uint64, family string) error
+
 	// CreateDeleteAccountToken creates delete account token for given user
 	// all previous delete account tokens for given user are removed
 	CreateDeleteAccountToken(ctx context.Context, userID uint64, meta string) (*Token, error)
@@ -137,6 +176,17 @@ type TokensService interface {
 	// GetDeleteAccountToken retrieves delete account
 	GetDeleteAccountToken(ctx context.Context, token string) (*Token, error)
 
+	// CreateAuthorizationCode creates short lived OAuth authorization code for given user.
+	// meta holds authorization request the code was issued for
+	CreateAuthorizationCode(ctx context.Context, userID uint64, meta string) (*Token, error)
+
+	// GetAuthorizationCode retrieves OAuth authorization code
+	GetAuthorizationCode(ctx context.Context, token string) (*Token, error)
+
+	// ConsumeAuthorizationCode removes given OAuth authorization code.
+	// Returns false if code was already used
+	ConsumeAuthorizationCode(ctx context.Context, code *Token) (bool, error)
+
 	// GetByID returns Token from database with provided id
 	GetByID(ctx context.Context, id uint64) (*Token, error)
 
@@ -152,6 +202,9 @@ type TokensService interface {
 	// DeleteByUserID removes all tokens for given userID
 	DeleteByUserID(ctx context.Context, userID uint64) error
 
+	// DeleteRefreshTokens removes all refresh tokens for given userID
+	DeleteRefreshTokens(ctx context.Context, userID uint64) error
+
 	// DeleteExpiredTokens removes all tokens that are expired
 	DeleteExpiredTokens(ctx context.Context) error
 }
@@ -200,7 +253,7 @@ func (svc *tokensService) CreatePasswordRessetToken(ctx context.Context, userID
 	}
 
 	exp := time.Now().Add(time.Minute * time.Duration(PasswordResetTokenDuration))
-	t, err := svc.create(ctx, userID, TokenTypePasswordReset, "", "", exp)
+	t, err := svc.create(ctx, userID, TokenTypePasswordReset, "", meta, exp)
 	if err != nil {
 		return nil, apperror.New("TOKENS.001", ErrCreatePasswordResetToken, err)
 	}
@@ -221,6 +274,17 @@ func (svc *tokensService) GetPasswordRessetToken(ctx context.Context, token stri
 	return t, nil
 }
 
+// ConsumePasswordResetToken removes given password reset token.
+// Returns false if token was already used
+func (svc *tokensService) ConsumePasswordResetToken(ctx context.Context, token *Token) (bool, error) {
+	consumed, err := svc.repo.Consume(ctx, token.ID)
+	if err != nil {
+		return false, apperror.New("TOKENS.011", ErrDeleteToken, err)
+	}
+
+	return consumed, nil
+}
+
 // CreateEmailConfirmToken creates email confirmation token for given user
 // all previous email confirmation tokens are deleted when new token is created
 func (svc *tokensService) CreateEmailConfirmToken(ctx context.Context, userID uint64, meta string) (*Token, error) {
@@ -230,7 +294,7 @@ func (svc *tokensService) CreateEmailConfirmToken(ctx context.Context, userID ui
 	}
 
 	exp := time.Now().Add(time.Minute * time.Duration(EmailConfirmationTokenDuration))
-	t, err := svc.create(ctx, userID, TokenTypeEmailConfirmation, "", "", exp)
+	t, err := svc.create(ctx, userID, TokenTypeEmailConfirmation, "", meta, exp)
 	if err != nil {
 		return nil, apperror.New("TOKENS.021", ErrCreateEmailConfirmToken, err)
 	}
@@ -259,7 +323,7 @@ func (svc *tokensService) CreateInviteToken(ctx context.Context, userID uint64,
 	}
 
 	exp := time.Now().Add(time.Minute * time.Duration(InvitationTokenDuration))
-	t, err := svc.create(ctx, userID, TokenTypeInvitation, "", "", exp)
+	t, err := svc.create(ctx, userID, TokenTypeInvitation, "", meta, exp)
 	if err != nil {
 		return nil, apperror.New("TOKENS.041", ErrCreateInvitationToken, err)
 	}
@@ -280,15 +344,51 @@ func (svc *tokensService) GetInviteToken(ctx context.Context, token string) (*To
 	return t, nil
 }
 
-// CreateRefreshToken creates refresh token for given user
-// all previous refresh tokens for given user are removed
-func (svc *tokensService) CreateRefreshToken(ctx context.Context, userID uint64, meta string) (*Token, error) {
-	// if err := svc.repo.DeleteByUserAndTokenTypeID(ctx, userID, TokenTypeRefresh); err != nil {
-	//	 return nil, apperror.New("040.090", ErrCreateRefreshToken, err)
-	// }
+// GetInviteTokens returns invitation tokens of all users, including expired ones
+func (svc *tokensService) GetInviteTokens(ctx context.Context) ([]*Token, error) {
+	tokens, err := svc.repo.GetByTokenTypeID(ctx, TokenTypeInvitation)
+	if err != nil {
+		return nil, apperror.New("TOKENS.200", ErrFetchInviteToken, err)
+	}
+	return tokens, nil
+}
+
+// GetUserInviteToken returns invitation token of given user, including expired one
+func (svc *tokensService) GetUserInviteToken(ctx context.Context, userID uint64) (*Token, error) {
+	tokens, err := svc.repo.GetByUserAndTokenID(ctx, userID, TokenTypeInvitation)
+	if err != nil {
+		return nil, apperror.New("TOKENS.210", ErrFetchInviteToken, err)
+	}
+
+	if len(tokens) == 0 {
+		return nil, apperror.New("TOKENS.211", ErrFetchInviteToken, ErrTokenNotExist)
+	}
+
+	return tokens[0], nil
+}
+
+// CreateRefreshToken creates refresh token for given user, valid for given lifetime.
+// meta holds encoded RefreshTokenMeta, when family is not set token starts new token family
+func (svc *tokensService) CreateRefreshToken(ctx context.Context, userID uint64, meta string, lifetime time.Duration) (*Token, error) {
+	t := &Token{Meta: meta}
+	m, err := t.RefreshTokenMeta()
+	if err != nil {
+		return nil, apperror.New("TOKENS.061", ErrCreateRefreshToken, err)
+	}
+
+	now := time.Now()
+	if m.Family == "" {
+		m.Family = uuid.New().String()
+		m.StartedAt = &now
+	}
+	m.LastUsedAt = &now
+
+	if err := t.SetRefreshTokenMeta(m); err != nil {
+		return nil, apperror.New("TOKENS.062", ErrCreateRefreshToken, err)
+	}
 
-	exp := time.Now().Add(time.Minute * time.Duration(RefreshTokenDuration))
-	t, err := svc.create(ctx, userID, TokenTypeRefresh, "", "", exp)
+	exp := now.Add(lifetime)
+	t, err = svc.create(ctx, userID, TokenTypeRefresh, "", t.Meta, exp)
 	if err != nil {
 		return nil, apperror.New("TOKENS.060", ErrCreateRefreshToken, err)
 	}
@@ -309,6 +409,108 @@ func (svc *tokensService) GetRefreshToken(ctx context.Context, token string) (*T
 	return t, nil
 }
 
+// GetActiveRefreshTokens returns refresh tokens of given user which are neither rotated nor expired.
+// Each active token represents one user session
+func (svc *tokensService) GetActiveRefreshTokens(ctx context.Context, userID uint64) ([]*Token, error) {
+	tokens, err := svc.repo.GetByUserAndTokenID(ctx, userID, TokenTypeRefresh)
+	if err != nil {
+		return nil, apperror.New("TOKENS.190", ErrFetchRefreshToken, err)
+	}
+
+	now := time.Now()
+	active := make([]*Token, 0, len(tokens))
+	for _, t := range tokens {
+		meta, err := t.RefreshTokenMeta()
+		if err != nil {
+			return nil, apperror.New("TOKENS.191", ErrFetchRefreshToken, err)
+		}
+
+		if meta.RotatedAt != nil || now.After(t.ExpiresAt) {
+			continue
+		}
+		active = append(active, t)
+	}
+
+	return active, nil
+}
+
+// RotateRefreshToken marks given refresh token as rotated and issues new token, valid for given lifetime,
+// within the same family. ErrRefreshTokenReused is returned if given token was already rotated
+func (svc *tokensService) RotateRefreshToken(ctx context.Context, token *Token, lifetime time.Duration, clientIP string, userAgent string) (*Token, error) {
+	meta, err := token.RefreshTokenMeta()
+	if err != nil {
+		return nil, apperror.New("TOKENS.170", ErrRotateRefreshToken, err)
+	}
+
+	if meta.RotatedAt != nil {
+		return nil, apperror.New("TOKENS.171", ErrRotateRefreshToken, ErrRefreshTokenReused)
+	}
+
+	// tokens issued before token families were introduced start their own family
+	if meta.Family == "" {
+		meta.Family = uuid.New().String()
+		meta.StartedAt = &token.CreatedAt
+	}
+
+	// keep rotated token until it expires so its reuse can be detected
+	now := time.Now()
+	meta.RotatedAt = &now
+	if err := token.SetRefreshTokenMeta(meta); err != nil {
+		return nil, apperror.New("TOKENS.172", ErrRotateRefreshToken, err)
+	}
+
+	// meta is changed only if token was not rotated concurrently, so only one request gets new token
+	updated, err := svc.repo.MarkRotated(ctx, token)
+	if err != nil {
+		return nil, apperror.New("TOKENS.173", ErrRotateRefreshToken, err)
+	}
+
+	if !updated {
+		return nil, apperror.New("TOKENS.176", ErrRotateRefreshToken, ErrRefreshTokenReused)
+	}
+
+	newMeta := &RefreshTokenMeta{
+		Family:    meta.Family,
+		ParentID:  token.ID,
+		UserAgent: userAgent,
+		ClientIP:  clientIP,
+		StartedAt: meta.StartedAt,
+		ClientID:  meta.ClientID,
+		Scope:     meta.Scope,
+	}
+	data, err := newMeta.Encode()
+	if err != nil {
+		return nil, apperror.New("TOKENS.174", ErrRotateRefreshToken, err)
+	}
+
+	t, err := svc.CreateRefreshToken(ctx, token.UserID, data, lifetime)
+	if err != nil {
+		return nil, apperror.New("TOKENS.175", ErrRotateRefreshToken, err)
+	}
+	return t, nil
+}
+
+// RevokeRefreshTokenFamily removes all refresh tokens of given user which belong to given family
+func (svc *tokensService) RevokeRefreshTokenFamily(ctx context.Context, userID uint64, family string) error {
+	tokens, err := svc.repo.GetByUserAndTokenID(ctx, userID, TokenTypeRefresh)
+	if err != nil {
+		return apperror.New("TOKENS.180", ErrRevokeTokenFamily, err)
+	}
+
+	for _, t := range tokens {
+		meta, err := t.RefreshTokenMeta()
+		if err != nil || meta.Family != family {
+			continue
+		}
+
+		if err := svc.repo.Delete(ctx, t); err != nil {
+			return apperror.New("TOKENS.181", ErrRevokeTokenFamily, err)
+		}
+	}
+
+	return nil
+}
+
 // CreateDeleteAccountToken creates delete account token for given user
 // all previous delete account tokens for given user are removed
 func (svc *tokensService) CreateDeleteAccountToken(ctx context.Context, userID uint64, meta string) (*Token, error) {
@@ -318,7 +520,7 @@ func (svc *tokensService) CreateDeleteAccountToken(ctx context.Context, userID u
 	}
 
 	exp := time.Now().Add(time.Minute * time.Duration(DeleteAccountTokenDuration))
-	t, err := svc.create(ctx, userID, TokenTypeDeleteAccount, "", "", exp)
+	t, err := svc.create(ctx, userID, TokenTypeDeleteAccount, "", meta, exp)
 	if err != nil {
 		return nil, apperror.New("TOKENS.081", ErrCreateDeleteAccountToken, err)
 	}
@@ -339,6 +541,42 @@ func (svc *tokensService) GetDeleteAccountToken(ctx context.Context, token strin
 	return t, nil
 }
 
+// CreateAuthorizationCode creates short lived OAuth authorization code for given user.
+// meta holds authorization request the code was issued for
+func (svc *tokensService) CreateAuthorizationCode(ctx context.Context, userID uint64, meta string) (*Token, error) {
+	exp := time.Now().Add(time.Minute * time.Duration(AuthorizationCodeDuration))
+	t, err := svc.create(ctx, userID, TokenTypeAuthorizationCode, "", meta, exp)
+	if err != nil {
+		return nil, apperror.New("TOKENS.220", ErrCreateAuthorizationCode, err)
+	}
+	return t, nil
+}
+
+// GetAuthorizationCode retrieves OAuth authorization code
+func (svc *tokensService) GetAuthorizationCode(ctx context.Context, token string) (*Token, error) {
+	t, err := svc.GetByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.TokenTypeID != TokenTypeAuthorizationCode {
+		return nil, apperror.New("TOKENS.230", ErrFetchAuthorizationCode, ErrWrongTkenType)
+	}
+
+	return t, nil
+}
+
+// ConsumeAuthorizationCode removes given OAuth authorization code.
+// Returns false if code was already used
+func (svc *tokensService) ConsumeAuthorizationCode(ctx context.Context, code *Token) (bool, error) {
+	consumed, err := svc.repo.Consume(ctx, code.ID)
+	if err != nil {
+		return false, apperror.New("TOKENS.240", ErrDeleteToken, err)
+	}
+
+	return consumed, nil
+}
+
 // GetByID returns Token from database with provided id
 func (svc *tokensService) GetByID(ctx context.Context, id uint64) (*Token, error) {
 	t, err := svc.repo.GetByID(ctx, id)
@@ -393,6 +631,14 @@ func (svc *tokensService) DeleteByUserID(ctx context.Context, userID uint64) err
 	return nil
 }
 
+// DeleteRefreshTokens removes all refresh tokens for given userID
+func (svc *tokensService) DeleteRefreshTokens(ctx context.Context, userID uint64) error {
+	if err := svc.repo.DeleteByUserAndTokenTypeID(ctx, userID, TokenTypeRefresh); err != nil {
+		return apperror.New("TOKENS.160", ErrDeleteUserTokens, err)
+	}
+	return nil
+}
+
 // DeleteExpiredTokens removes all tokens that are expired
 func (svc *tokensService) DeleteExpiredTokens(ctx context.Context) error {
 	if err := svc.repo.DeleteExpiredTokens(ctx); err != nil {
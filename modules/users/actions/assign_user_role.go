@@ -0,0 +1,86 @@
+package actions
+
+import (
+	roleModels "api/modules/roles/models"
+	"api/modules/users/services"
+	"api/pkg/apperror"
+	"api/pkg/userip"
+	"api/providers/binding"
+	"api/providers/jwt"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// AssignUserRole request object
+type AssignUserRole struct {
+	RoleID uint64 `json:"roleId" binding:"required"`
+}
+
+type AssignUserRoleAction struct {
+	vm                vm.Transformer
+	binder            binding.Binder
+	auth              jwt.TokenAuth
+	usersAdminService services.UsersAdminService
+}
+
+func NewAssignUserRoleAction(vm vm.Transformer, binder binding.Binder, auth jwt.TokenAuth, usersAdminService services.UsersAdminService) *AssignUserRoleAction {
+	return &AssignUserRoleAction{
+		vm:                vm,
+		binder:            binder,
+		auth:              auth,
+		usersAdminService: usersAdminService,
+	}
+}
+
+func (a *AssignUserRoleAction) Method() string {
+	return http.MethodPost
+}
+
+func (a *AssignUserRoleAction) Path() string {
+	return "/id/:id/roles"
+}
+
+func (a *AssignUserRoleAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{
+		a.auth.RequirePermissions(roleModels.PermissionUsersWrite, roleModels.PermissionRolesWrite),
+	}
+}
+
+// Handle assigns role to user
+// @Summary Assigns role to user
+// @Description Role is included in access tokens issued after assignment
+// @Produce json
+// @Tags users
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param req body AssignUserRole true "Assign User Role Request"
+// @Success 200 {object} vm.Response
+// @Failure 400 {object} vm.ResponseError
+// @Router /users/id/{id}/roles [post]
+func (a *AssignUserRoleAction) Handle(r *http.Request) flow.Response {
+	id, err := strconv.ParseUint(flow.ParamsFromContext(r.Context()).ByName("id"), 10, 64)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	var reqObj AssignUserRole
+	if err := a.binder.Bind(r, &reqObj); err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	adminID, err := a.auth.RequestUserID(r)
+	if err != nil {
+		return a.vm.Error(http.StatusUnauthorized, err)
+	}
+
+	err = a.usersAdminService.AssignRole(r.Context(), adminID, id, reqObj.RoleID, userip.Get(r), r.UserAgent())
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, nil)
+}
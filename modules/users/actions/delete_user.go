@@ -0,0 +1,70 @@
+package actions
+
+import (
+	roleModels "api/modules/roles/models"
+	"api/modules/users/services"
+	"api/pkg/apperror"
+	"api/providers/jwt"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-flow/flow/v2"
+)
+
+type DeleteUserAction struct {
+	vm                vm.Transformer
+	auth              jwt.TokenAuth
+	usersAdminService services.UsersAdminService
+}
+
+func NewDeleteUserAction(vm vm.Transformer, auth jwt.TokenAuth, usersAdminService services.UsersAdminService) *DeleteUserAction {
+	return &DeleteUserAction{
+		vm:                vm,
+		auth:              auth,
+		usersAdminService: usersAdminService,
+	}
+}
+
+func (a *DeleteUserAction) Method() string {
+	return http.MethodDelete
+}
+
+func (a *DeleteUserAction) Path() string {
+	return "/id/:id"
+}
+
+func (a *DeleteUserAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{
+		a.auth.RequirePermissions(roleModels.PermissionUsersWrite),
+	}
+}
+
+// Handle deletes user
+// @Summary Marks user as deleted, terminates all user sessions and removes user API keys
+// @Description Personal information is removed after grace period unless user is restored
+// @Produce json
+// @Tags users
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} vm.Response
+// @Failure 400 {object} vm.ResponseError
+// @Router /users/id/{id} [delete]
+func (a *DeleteUserAction) Handle(r *http.Request) flow.Response {
+	id, err := strconv.ParseUint(flow.ParamsFromContext(r.Context()).ByName("id"), 10, 64)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	adminID, err := a.auth.RequestUserID(r)
+	if err != nil {
+		return a.vm.Error(http.StatusUnauthorized, err)
+	}
+
+	if err := a.usersAdminService.Delete(r.Context(), adminID, id); err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, nil)
+}
@@ -0,0 +1,65 @@
+package actions
+
+import (
+	roleModels "api/modules/roles/models"
+	"api/modules/users/services"
+	"api/pkg/apperror"
+	"api/providers/jwt"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-flow/flow/v2"
+)
+
+type RestoreUserAction struct {
+	vm                vm.Transformer
+	auth              jwt.TokenAuth
+	usersAdminService services.UsersAdminService
+}
+
+func NewRestoreUserAction(vm vm.Transformer, auth jwt.TokenAuth, usersAdminService services.UsersAdminService) *RestoreUserAction {
+	return &RestoreUserAction{
+		vm:                vm,
+		auth:              auth,
+		usersAdminService: usersAdminService,
+	}
+}
+
+func (a *RestoreUserAction) Method() string {
+	return http.MethodPost
+}
+
+func (a *RestoreUserAction) Path() string {
+	return "/id/:id/restore"
+}
+
+func (a *RestoreUserAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{
+		a.auth.RequirePermissions(roleModels.PermissionUsersWrite),
+	}
+}
+
+// Handle restores user
+// @Summary Restores deleted user which is not anonymized yet
+// @Produce json
+// @Tags users
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} models.User
+// @Failure 400 {object} vm.ResponseError
+// @Router /users/id/{id}/restore [post]
+func (a *RestoreUserAction) Handle(r *http.Request) flow.Response {
+	id, err := strconv.ParseUint(flow.ParamsFromContext(r.Context()).ByName("id"), 10, 64)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	user, err := a.usersAdminService.Restore(r.Context(), id)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, user)
+}
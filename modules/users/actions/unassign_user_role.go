@@ -0,0 +1,79 @@
+package actions
+
+import (
+	roleModels "api/modules/roles/models"
+	"api/modules/users/services"
+	"api/pkg/apperror"
+	"api/pkg/userip"
+	"api/providers/jwt"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-flow/flow/v2"
+)
+
+type UnassignUserRoleAction struct {
+	vm                vm.Transformer
+	auth              jwt.TokenAuth
+	usersAdminService services.UsersAdminService
+}
+
+func NewUnassignUserRoleAction(vm vm.Transformer, auth jwt.TokenAuth, usersAdminService services.UsersAdminService) *UnassignUserRoleAction {
+	return &UnassignUserRoleAction{
+		vm:                vm,
+		auth:              auth,
+		usersAdminService: usersAdminService,
+	}
+}
+
+func (a *UnassignUserRoleAction) Method() string {
+	return http.MethodDelete
+}
+
+func (a *UnassignUserRoleAction) Path() string {
+	return "/id/:id/roles/:roleId"
+}
+
+func (a *UnassignUserRoleAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{
+		a.auth.RequirePermissions(roleModels.PermissionUsersWrite, roleModels.PermissionRolesWrite),
+	}
+}
+
+// Handle removes role from user
+// @Summary Removes role from user
+// @Produce json
+// @Tags users
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param roleId path int true "Role ID"
+// @Success 200 {object} vm.Response
+// @Failure 400 {object} vm.ResponseError
+// @Router /users/id/{id}/roles/{roleId} [delete]
+func (a *UnassignUserRoleAction) Handle(r *http.Request) flow.Response {
+	params := flow.ParamsFromContext(r.Context())
+
+	id, err := strconv.ParseUint(params.ByName("id"), 10, 64)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	roleID, err := strconv.ParseUint(params.ByName("roleId"), 10, 64)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	adminID, err := a.auth.RequestUserID(r)
+	if err != nil {
+		return a.vm.Error(http.StatusUnauthorized, err)
+	}
+
+	err = a.usersAdminService.UnassignRole(r.Context(), adminID, id, roleID, userip.Get(r), r.UserAgent())
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, nil)
+}
@@ -0,0 +1,72 @@
+package actions
+
+import (
+	roleModels "api/modules/roles/models"
+	"api/modules/users/services"
+	"api/pkg/apperror"
+	"api/providers/jwt"
+	"api/providers/lockout"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-flow/flow/v2"
+)
+
+type UnlockUserAction struct {
+	vm           vm.Transformer
+	auth         jwt.TokenAuth
+	lockout      lockout.Lockout
+	usersService services.UsersService
+}
+
+func NewUnlockUserAction(vm vm.Transformer, auth jwt.TokenAuth, lockout lockout.Lockout, usersService services.UsersService) *UnlockUserAction {
+	return &UnlockUserAction{
+		vm:           vm,
+		auth:         auth,
+		lockout:      lockout,
+		usersService: usersService,
+	}
+}
+
+func (a *UnlockUserAction) Method() string {
+	return http.MethodPost
+}
+
+func (a *UnlockUserAction) Path() string {
+	return "/id/:id/unlock"
+}
+
+func (a *UnlockUserAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{
+		a.auth.RequirePermissions(roleModels.PermissionUsersWrite),
+	}
+}
+
+// Handle unlocks user
+// @Summary Removes login lockout and resets failed login attempts of user
+// @Produce json
+// @Tags users
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} vm.Response
+// @Failure 400 {object} vm.ResponseError
+// @Router /users/id/{id}/unlock [post]
+func (a *UnlockUserAction) Handle(r *http.Request) flow.Response {
+	id, err := strconv.ParseUint(flow.ParamsFromContext(r.Context()).ByName("id"), 10, 64)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	user, err := a.usersService.GetByID(r.Context(), id)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	if err := a.lockout.Unlock(r.Context(), user.ID); err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, nil)
+}
@@ -0,0 +1,80 @@
+package actions
+
+import (
+	roleModels "api/modules/roles/models"
+	"api/modules/users/services"
+	"api/pkg/apperror"
+	"api/providers/binding"
+	"api/providers/jwt"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// UpdateUser request object
+type UpdateUser struct {
+	FirstName *string `json:"firstName" binding:"omitempty,min=3"`
+	LastName  *string `json:"lastName" binding:"omitempty,min=3"`
+}
+
+type UpdateUserAction struct {
+	vm                vm.Transformer
+	auth              jwt.TokenAuth
+	binder            binding.Binder
+	usersAdminService services.UsersAdminService
+}
+
+func NewUpdateUserAction(vm vm.Transformer, auth jwt.TokenAuth, binder binding.Binder, usersAdminService services.UsersAdminService) *UpdateUserAction {
+	return &UpdateUserAction{
+		vm:                vm,
+		auth:              auth,
+		binder:            binder,
+		usersAdminService: usersAdminService,
+	}
+}
+
+func (a *UpdateUserAction) Method() string {
+	return http.MethodPut
+}
+
+func (a *UpdateUserAction) Path() string {
+	return "/id/:id"
+}
+
+func (a *UpdateUserAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{
+		a.auth.RequirePermissions(roleModels.PermissionUsersWrite),
+	}
+}
+
+// Handle updates user
+// @Summary Updates user profile information, omitted fields are not changed
+// @Produce json
+// @Tags users
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param req body UpdateUser true "Update User Request"
+// @Success 200 {object} models.User
+// @Failure 400 {object} vm.ResponseError
+// @Router /users/id/{id} [put]
+func (a *UpdateUserAction) Handle(r *http.Request) flow.Response {
+	id, err := strconv.ParseUint(flow.ParamsFromContext(r.Context()).ByName("id"), 10, 64)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	var reqObj UpdateUser
+	if err := a.binder.Bind(r, &reqObj); err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	user, err := a.usersAdminService.Update(r.Context(), id, reqObj.FirstName, reqObj.LastName)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, user)
+}
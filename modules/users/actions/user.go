@@ -0,0 +1,60 @@
+package actions
+
+import (
+	"api/modules/users/services"
+	"api/pkg/apperror"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-flow/flow/v2"
+)
+
+type UserAction struct {
+	vm                vm.Transformer
+	usersAdminService services.UsersAdminService
+}
+
+func NewUserAction(vm vm.Transformer, usersAdminService services.UsersAdminService) *UserAction {
+	return &UserAction{
+		vm:                vm,
+		usersAdminService: usersAdminService,
+	}
+}
+
+func (a *UserAction) Method() string {
+	return http.MethodGet
+}
+
+func (a *UserAction) Path() string {
+	return "/id/:id"
+}
+
+func (a *UserAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle returns user
+// @Summary Returns user with optionally included roles and authentication providers
+// @Produce json
+// @Tags users
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param include query string false "Comma separated relations (roles, providers)"
+// @Success 200 {object} models.UserDetails
+// @Failure 400 {object} vm.ResponseError
+// @Router /users/id/{id} [get]
+func (a *UserAction) Handle(r *http.Request) flow.Response {
+	id, err := strconv.ParseUint(flow.ParamsFromContext(r.Context()).ByName("id"), 10, 64)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	user, err := a.usersAdminService.GetByID(r.Context(), id, include(r)...)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, user)
+}
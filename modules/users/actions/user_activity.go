@@ -0,0 +1,120 @@
+package actions
+
+import (
+	"api/modules/audit"
+	"api/modules/users/services"
+	"api/pkg/apperror"
+	"api/pkg/paging"
+	"api/providers/vm"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-flow/flow/v2"
+)
+
+type UserActivityAction struct {
+	vm           vm.Transformer
+	auditService audit.AuditService
+	usersService services.UsersService
+}
+
+func NewUserActivityAction(vm vm.Transformer, auditService audit.AuditService, usersService services.UsersService) *UserActivityAction {
+	return &UserActivityAction{
+		vm:           vm,
+		auditService: auditService,
+		usersService: usersService,
+	}
+}
+
+func (a *UserActivityAction) Method() string {
+	return http.MethodGet
+}
+
+func (a *UserActivityAction) Path() string {
+	return "/id/:id/activity"
+}
+
+func (a *UserActivityAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle lists user activity
+// @Summary Lists authentication events of user
+// @Produce json
+// @Tags users
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param event query string false "Event type (login, mfa_challenge, register, refresh, password_change, role_change)"
+// @Param success query bool false "Event outcome"
+// @Param client_ip query string false "Client IP address"
+// @Param from query string false "Events created at or after given time (RFC3339)"
+// @Param to query string false "Events created before given time (RFC3339)"
+// @Param page query int false "Page"
+// @Param per_page query int false "Results per page"
+// @Success 200 {object} paging.Model{results=[]audit.Event}
+// @Failure 400 {object} vm.ResponseError
+// @Router /users/id/{id}/activity [get]
+func (a *UserActivityAction) Handle(r *http.Request) flow.Response {
+	id, err := strconv.ParseUint(flow.ParamsFromContext(r.Context()).ByName("id"), 10, 64)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+
+	user, err := a.usersService.GetByID(r.Context(), id)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	query := r.URL.Query()
+	filter, err := a.filter(query)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, apperror.New("400", errors.New("validation error"), err))
+	}
+	filter.UserID = user.ID
+
+	paginator := paging.NewPaginatorFromParams(query)
+
+	events, err := a.auditService.Find(r.Context(), filter, paginator)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, &paging.Model{Results: events, Paginator: paginator})
+}
+
+// filter builds events filter from query params
+func (a *UserActivityAction) filter(query url.Values) (*audit.EventFilter, error) {
+	filter := &audit.EventFilter{
+		Event:    query.Get("event"),
+		ClientIP: query.Get("client_ip"),
+	}
+
+	if v := query.Get("success"); v != "" {
+		success, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, err
+		}
+		filter.Success = &success
+	}
+
+	if v := query.Get("from"); v != "" {
+		from, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, err
+		}
+		filter.From = &from
+	}
+
+	if v := query.Get("to"); v != "" {
+		to, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, err
+		}
+		filter.To = &to
+	}
+
+	return filter, nil
+}
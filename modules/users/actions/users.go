@@ -0,0 +1,78 @@
+package actions
+
+import (
+	"api/modules/users/services"
+	"api/pkg/paging"
+	"api/providers/vm"
+	"net/http"
+	"strings"
+
+	"github.com/go-flow/flow/v2"
+)
+
+type UsersAction struct {
+	vm                vm.Transformer
+	usersAdminService services.UsersAdminService
+}
+
+func NewUsersAction(vm vm.Transformer, usersAdminService services.UsersAdminService) *UsersAction {
+	return &UsersAction{
+		vm:                vm,
+		usersAdminService: usersAdminService,
+	}
+}
+
+func (a *UsersAction) Method() string {
+	return http.MethodGet
+}
+
+func (a *UsersAction) Path() string {
+	return "/"
+}
+
+func (a *UsersAction) Middlewares() []flow.MiddlewareHandlerFunc {
+	return []flow.MiddlewareHandlerFunc{}
+}
+
+// Handle lists users
+// @Summary Lists users with optionally included roles and authentication providers
+// @Produce json
+// @Tags users
+// @Security BearerAuth
+// @Param page query int false "Page"
+// @Param per_page query int false "Results per page"
+// @Param order_by query string false "Order by (id, firstName, lastName, email, createdAt, deletedAt)"
+// @Param order_dir query string false "Order direction (asc, desc)"
+// @Param filter query string false "First name, last name or email prefix"
+// @Param state query string false "User state (active, deleted)"
+// @Param include query string false "Comma separated relations (roles, providers)"
+// @Success 200 {object} paging.Model{results=[]models.UserDetails}
+// @Failure 400 {object} vm.ResponseError
+// @Router /users/ [get]
+func (a *UsersAction) Handle(r *http.Request) flow.Response {
+	query := r.URL.Query()
+	paginator := paging.NewPaginatorFromParams(query)
+
+	users, err := a.usersAdminService.Find(r.Context(), query.Get("state"), paginator, include(r)...)
+	if err != nil {
+		return a.vm.Error(http.StatusBadRequest, err)
+	}
+
+	return a.vm.Success(http.StatusOK, &paging.Model{Results: users, Paginator: paginator})
+}
+
+// include returns relations requested with include query param
+func include(r *http.Request) []string {
+	param := r.URL.Query().Get("include")
+	if param == "" {
+		return nil
+	}
+
+	relations := []string{}
+	for _, relation := range strings.Split(param, ",") {
+		if relation = strings.TrimSpace(relation); relation != "" {
+			relations = append(relations, relation)
+		}
+	}
+	return relations
+}
@@ -0,0 +1,18 @@
+package models
+
+import roleModels "api/modules/roles/models"
+
+const (
+	// IncludeRoles includes user roles to user details
+	IncludeRoles = "roles"
+
+	// IncludeProviders includes user authentication providers to user details
+	IncludeProviders = "providers"
+)
+
+// UserDetails holds user with optionally included relations
+type UserDetails struct {
+	*User
+	Roles     []*roleModels.Role `json:"roles,omitempty"`
+	Providers []string           `json:"providers,omitempty"`
+}
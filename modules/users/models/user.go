@@ -3,11 +3,22 @@ package models
 import "time"
 
 type User struct {
-	ID        uint64    `json:"id"`
-	FirstName string    `json:"firstName"`
-	LastName  string    `json:"lastName"`
-	Email     string    `json:"email"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
-	DeletedAt time.Time `json:"deletedAt"`
+	ID               uint64     `json:"id"`
+	FirstName        string     `json:"firstName"`
+	LastName         string     `json:"lastName"`
+	Email            string     `json:"email"`
+	EmailConfirmedAt *time.Time `json:"emailConfirmedAt"`
+	CreatedAt        time.Time  `json:"createdAt"`
+	UpdatedAt        time.Time  `json:"updatedAt"`
+	DeletedAt        *time.Time `json:"deletedAt"`
+}
+
+// IsEmailConfirmed checks if user confirmed email address
+func (u *User) IsEmailConfirmed() bool {
+	return u.EmailConfirmedAt != nil
+}
+
+// IsDeleted checks if user is deleted and waits for anonymization
+func (u *User) IsDeleted() bool {
+	return u.DeletedAt != nil
 }
@@ -1,6 +1,11 @@
 package users
 
 import (
+	"api/modules/apikeys"
+	"api/modules/audit"
+	"api/modules/auth"
+	"api/modules/roles"
+	"api/modules/tokens"
 	"api/modules/users/repositories"
 	"api/modules/users/routers"
 	"api/modules/users/services"
@@ -26,11 +31,18 @@ func (m *Module) ProvideImports() []flow.Provider {
 func (m *Module) ProvideExports() []flow.Provider {
 	return []flow.Provider{
 		flow.NewProvider(services.NewUsersService),
+		flow.NewProvider(services.NewUsersAdminService),
 	}
 }
 
 func (m *Module) ProvideModules() []flow.Provider {
-	return []flow.Provider{}
+	return []flow.Provider{
+		flow.NewProvider(roles.NewModule),
+		flow.NewProvider(auth.NewModule),
+		flow.NewProvider(tokens.NewModule),
+		flow.NewProvider(audit.NewModule),
+		flow.NewProvider(apikeys.NewModule),
+	}
 }
 
 func (m *Module) ProvideRouters() []flow.Provider {
@@ -11,11 +11,22 @@ import (
 	"api/providers/db"
 )
 
+// orderColumns holds columns users listing can be ordered by
+var orderColumns = map[string]string{
+	"id":        "id",
+	"firstName": "first_name",
+	"lastName":  "last_name",
+	"email":     "email",
+	"createdAt": "created_at",
+	"deletedAt": "deleted_at",
+}
+
 type UsersRepository interface {
 	UsersRepository() string
 
-	// Count returns number of records in database
-	Count(ctx context.Context, filter string) (int, error)
+	// Count returns number of records in database.
+	// When deleted is set, only deleted users which are not anonymized are counted
+	Count(ctx context.Context, filter string, deleted bool) (int, error)
 
 	// Save saves given user object
 	Save(ctx context.Context, user *models.User) error
@@ -32,14 +43,36 @@ type UsersRepository interface {
 	// GetByEmail returns user object from database for given email
 	GetByEmail(ctx context.Context, email string) (*models.User, error)
 
-	// GetAll returns all User objects for given params
-	GetAll(ctx context.Context, filter string, page int, perPage int, orderBy string, orderDir string) ([]*models.User, error)
+	// GetAll returns all User objects for given params.
+	// When deleted is set, only deleted users which are not anonymized are returned
+	GetAll(ctx context.Context, filter string, deleted bool, page int, perPage int, orderBy string, orderDir string) ([]*models.User, error)
+
+	// ConfirmEmail marks user email as confirmed
+	ConfirmEmail(ctx context.Context, id uint64) error
 
 	// Delete user from database
 	Delete(ctx context.Context, user *models.User) error
 
 	// DeleteByID user from database
 	DeleteByID(ctx context.Context, id uint64) error
+
+	// SoftDeleteByID marks user with given id as deleted
+	SoftDeleteByID(ctx context.Context, id uint64) error
+
+	// Restore removes deleted mark from user which is not anonymized
+	Restore(ctx context.Context, id uint64) error
+
+	// GetDeletedByEmail returns deleted user which is not anonymized for given email
+	GetDeletedByEmail(ctx context.Context, email string) (*models.User, error)
+
+	// GetDeletedByID returns deleted user which is not anonymized for given id
+	GetDeletedByID(ctx context.Context, id uint64) (*models.User, error)
+
+	// GetDeletedBefore returns users deleted before given time which are not anonymized
+	GetDeletedBefore(ctx context.Context, before time.Time) ([]*models.User, error)
+
+	// Anonymize removes personal information of deleted user
+	Anonymize(ctx context.Context, id uint64) error
 }
 
 // NewUsersRepository creates UsersRepository interface implementation
@@ -82,21 +115,19 @@ func (r *usersRepository) UsersRepository() string {
 	return "usersRepository"
 }
 
-// Count returns number of records in database
-func (r *usersRepository) Count(ctx context.Context, filter string) (int, error) {
+// Count returns number of records in database.
+// When deleted is set, only deleted users which are not anonymized are counted
+func (r *usersRepository) Count(ctx context.Context, filter string, deleted bool) (int, error) {
 	tx, shouldCommit, err := r.getTx(ctx)
 	if err != nil {
 		return 0, err
 	}
 
-	query := "SELECT COUNT(id) as count FROM users"
-	wc := r.whereClause(filter)
-	if wc != "" {
-		query += fmt.Sprintf(" WHERE %s", wc)
-	}
+	wc, args := r.whereClause(filter, deleted)
+	query := fmt.Sprintf("SELECT COUNT(id) as count FROM users WHERE %s", wc)
 
 	var count int
-	err = tx.QueryRow(query).Scan(&count)
+	err = tx.QueryRow(query, args...).Scan(&count)
 
 	r.closeTx(tx, shouldCommit, err != nil)
 
@@ -149,7 +180,7 @@ func (r *usersRepository) Create(ctx context.Context, user *models.User) error {
 	query := `
 		INSERT INTO users 
 		(first_name, last_name, email) 
-		VALUES(?, ?, ?, ?)`
+		VALUES(?, ?, ?)`
 
 	result, err := tx.Exec(query,
 		user.FirstName,
@@ -185,6 +216,7 @@ func (r *usersRepository) GetByID(ctx context.Context, id uint64) (*models.User,
 		first_name, 
 		last_name, 
 		email, 
+		email_confirmed_at, 
 		created_at, 
 		updated_at
 	FROM 
@@ -201,9 +233,9 @@ func (r *usersRepository) GetByID(ctx context.Context, id uint64) (*models.User,
 		&model.FirstName,
 		&model.LastName,
 		&model.Email,
+		&model.EmailConfirmedAt,
 		&model.CreatedAt,
-		&model.UpdatedAt,
-		&model.DeletedAt)
+		&model.UpdatedAt)
 
 	if err != nil && err == sql.ErrNoRows {
 		return nil, nil
@@ -227,6 +259,7 @@ func (r *usersRepository) GetByEmail(ctx context.Context, email string) (*models
 			first_name, 
 			last_name, 
 			email, 
+			email_confirmed_at, 
 			created_at, 
 			updated_at
 		FROM 
@@ -243,6 +276,7 @@ func (r *usersRepository) GetByEmail(ctx context.Context, email string) (*models
 		&model.FirstName,
 		&model.LastName,
 		&model.Email,
+		&model.EmailConfirmedAt,
 		&model.CreatedAt,
 		&model.UpdatedAt)
 	if err != nil && err == sql.ErrNoRows {
@@ -252,8 +286,9 @@ func (r *usersRepository) GetByEmail(ctx context.Context, email string) (*models
 	return model, err
 }
 
-// GetAll returns all User objects for given params
-func (r *usersRepository) GetAll(ctx context.Context, filter string, page int, perPage int, orderBy string, orderDir string) ([]*models.User, error) {
+// GetAll returns all User objects for given params.
+// When deleted is set, only deleted users which are not anonymized are returned
+func (r *usersRepository) GetAll(ctx context.Context, filter string, deleted bool, page int, perPage int, orderBy string, orderDir string) ([]*models.User, error) {
 	tx, shouldCommit, err := r.getTx(ctx)
 	if err != nil {
 		return nil, err
@@ -262,17 +297,17 @@ func (r *usersRepository) GetAll(ctx context.Context, filter string, page int, p
 	defer r.closeTx(tx, shouldCommit, err != nil)
 
 	offset := (page - 1) * perPage
-	if orderBy == "" {
-		orderBy = "id"
+
+	column, ok := orderColumns[orderBy]
+	if !ok {
+		column = "id"
 	}
-	if orderDir == "" {
+
+	if strings.ToUpper(orderDir) != "DESC" {
 		orderDir = "ASC"
 	}
 
-	wc := r.whereClause(filter)
-	if wc != "" {
-		wc = fmt.Sprintf(" WHERE %s", wc)
-	}
+	wc, args := r.whereClause(filter, deleted)
 
 	query := fmt.Sprintf(`
 		SELECT 
@@ -280,18 +315,19 @@ func (r *usersRepository) GetAll(ctx context.Context, filter string, page int, p
 			first_name, 
 			last_name, 
 			email, 
+			email_confirmed_at, 
 			created_at, 
 			updated_at, 
 			deleted_at 
 		FROM 
 			users 
-		%s
+		WHERE %s
 		ORDER BY %s %s
 		LIMIT %d OFFSET %d`,
-		wc, orderBy, orderDir, perPage, offset)
+		wc, column, orderDir, perPage, offset)
 
 	// execute query statement
-	rows, err := tx.Query(query)
+	rows, err := tx.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -307,6 +343,7 @@ func (r *usersRepository) GetAll(ctx context.Context, filter string, page int, p
 			&model.FirstName,
 			&model.LastName,
 			&model.Email,
+			&model.EmailConfirmedAt,
 			&model.CreatedAt,
 			&model.UpdatedAt,
 			&model.DeletedAt); err != nil {
@@ -322,6 +359,20 @@ func (r *usersRepository) GetAll(ctx context.Context, filter string, page int, p
 	return roles, err
 }
 
+// ConfirmEmail marks user email as confirmed
+func (r *usersRepository) ConfirmEmail(ctx context.Context, id uint64) error {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := "UPDATE users SET email_confirmed_at = NOW() WHERE id = ? AND email_confirmed_at IS NULL"
+	_, err = tx.Exec(query, id)
+	return err
+}
+
 // Delete user from database
 func (r *usersRepository) Delete(ctx context.Context, user *models.User) error {
 	return r.DeleteByID(ctx, user.ID)
@@ -341,13 +392,216 @@ func (r *usersRepository) DeleteByID(ctx context.Context, id uint64) error {
 	return err
 }
 
-func (usersRepository) whereClause(filter string) string {
+// SoftDeleteByID marks user with given id as deleted
+func (r *usersRepository) SoftDeleteByID(ctx context.Context, id uint64) error {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := "UPDATE users SET deleted_at = NOW() WHERE id = ? AND deleted_at IS NULL"
+	_, err = tx.Exec(query, id)
+	return err
+}
+
+// Restore removes deleted mark from user which is not anonymized
+func (r *usersRepository) Restore(ctx context.Context, id uint64) error {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := "UPDATE users SET deleted_at = NULL WHERE id = ? AND anonymized_at IS NULL"
+	_, err = tx.Exec(query, id)
+	return err
+}
+
+// GetDeletedByEmail returns deleted user which is not anonymized for given email
+func (r *usersRepository) GetDeletedByEmail(ctx context.Context, email string) (*models.User, error) {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := `
+		SELECT 
+			id, 
+			first_name, 
+			last_name, 
+			email, 
+			email_confirmed_at, 
+			created_at, 
+			updated_at, 
+			deleted_at
+		FROM 
+			users 
+		WHERE 
+			email = ? AND deleted_at IS NOT NULL AND anonymized_at IS NULL`
+
+	// create empty model object
+	model := new(models.User)
+
+	// execute query statement and scan row to model
+	err = tx.QueryRow(query, email).Scan(
+		&model.ID,
+		&model.FirstName,
+		&model.LastName,
+		&model.Email,
+		&model.EmailConfirmedAt,
+		&model.CreatedAt,
+		&model.UpdatedAt,
+		&model.DeletedAt)
+	if err != nil && err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return model, err
+}
+
+// GetDeletedByID returns deleted user which is not anonymized for given id
+func (r *usersRepository) GetDeletedByID(ctx context.Context, id uint64) (*models.User, error) {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := `
+		SELECT 
+			id, 
+			first_name, 
+			last_name, 
+			email, 
+			email_confirmed_at, 
+			created_at, 
+			updated_at, 
+			deleted_at
+		FROM 
+			users 
+		WHERE 
+			id = ? AND deleted_at IS NOT NULL AND anonymized_at IS NULL`
+
+	// create empty model object
+	model := new(models.User)
+
+	// execute query statement and scan row to model
+	err = tx.QueryRow(query, id).Scan(
+		&model.ID,
+		&model.FirstName,
+		&model.LastName,
+		&model.Email,
+		&model.EmailConfirmedAt,
+		&model.CreatedAt,
+		&model.UpdatedAt,
+		&model.DeletedAt)
+	if err != nil && err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return model, err
+}
+
+// GetDeletedBefore returns users deleted before given time which are not anonymized
+func (r *usersRepository) GetDeletedBefore(ctx context.Context, before time.Time) ([]*models.User, error) {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := `
+		SELECT 
+			id, 
+			first_name, 
+			last_name, 
+			email, 
+			email_confirmed_at, 
+			created_at, 
+			updated_at, 
+			deleted_at 
+		FROM 
+			users 
+		WHERE 
+			deleted_at < ? AND anonymized_at IS NULL
+		ORDER BY deleted_at ASC`
+
+	// execute query statement
+	rows, err := tx.Query(query, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]*models.User, 0)
+	// loop over results
+	for rows.Next() {
+		model := new(models.User)
+		// scan row to model
+		if err = rows.Scan(
+			&model.ID,
+			&model.FirstName,
+			&model.LastName,
+			&model.Email,
+			&model.EmailConfirmedAt,
+			&model.CreatedAt,
+			&model.UpdatedAt,
+			&model.DeletedAt); err != nil {
+			return nil, err
+		}
+		users = append(users, model)
+	}
+
+	err = rows.Err()
+	return users, err
+}
+
+// Anonymize removes personal information of deleted user.
+// Email is replaced with unique placeholder, so the address can be registered again
+func (r *usersRepository) Anonymize(ctx context.Context, id uint64) error {
+	tx, shouldCommit, err := r.getTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer r.closeTx(tx, shouldCommit, err != nil)
+
+	query := `
+		UPDATE 
+			users 
+		SET 
+			first_name = '', 
+			last_name = '', 
+			email = CONCAT('deleted-', id, '@anonymized.invalid'), 
+			email_confirmed_at = NULL, 
+			anonymized_at = NOW() 
+		WHERE id = ? AND deleted_at IS NOT NULL AND anonymized_at IS NULL`
+
+	_, err = tx.Exec(query, id)
+	return err
+}
+
+// whereClause builds WHERE clause and its arguments for given filter
+func (usersRepository) whereClause(filter string, deleted bool) (string, []interface{}) {
 	wc := "deleted_at IS NULL"
+	if deleted {
+		wc = "deleted_at IS NOT NULL AND anonymized_at IS NULL"
+	}
 
+	args := []interface{}{}
 	if len(filter) > 0 {
-		filter = strings.ReplaceAll(filter, "'", "''")
-		wc += fmt.Sprintf(" AND (name LIKE '%s%%' OR email LIKE '%s%%')", filter, filter)
+		// escape LIKE wildcards, so filter is matched as prefix
+		filter = strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_").Replace(filter) + "%"
+		wc += " AND (first_name LIKE ? OR last_name LIKE ? OR email LIKE ?)"
+		args = append(args, filter, filter, filter)
 	}
 
-	return wc
+	return wc, args
 }
@@ -1,6 +1,8 @@
 package routers
 
 import (
+	roleModels "api/modules/roles/models"
+	"api/modules/users/actions"
 	"api/providers/jwt"
 
 	"github.com/go-flow/flow/v2"
@@ -25,13 +27,23 @@ func (r *Router) Path() string {
 // Middlewares provides list of middlewares used by the router
 func (r *Router) Middlewares() []flow.MiddlewareHandlerFunc {
 	return []flow.MiddlewareHandlerFunc{
-		r.auth.AuthorizeRequest("Authorized"),
-		r.auth.AuthorizeRequest("Admin"),
+		r.auth.AuthorizeRequest(jwt.ScopeAuthorized),
+		r.auth.RequirePermissions(roleModels.PermissionUsersRead),
 	}
 }
 
 func (r *Router) ProvideHandlers() []flow.Provider {
-	return []flow.Provider{}
+	return []flow.Provider{
+		flow.NewProvider(actions.NewUsersAction),
+		flow.NewProvider(actions.NewUserAction),
+		flow.NewProvider(actions.NewUpdateUserAction),
+		flow.NewProvider(actions.NewDeleteUserAction),
+		flow.NewProvider(actions.NewRestoreUserAction),
+		flow.NewProvider(actions.NewAssignUserRoleAction),
+		flow.NewProvider(actions.NewUnassignUserRoleAction),
+		flow.NewProvider(actions.NewUnlockUserAction),
+		flow.NewProvider(actions.NewUserActivityAction),
+	}
 }
 
 func (r *Router) RegisterSubRouters() bool {
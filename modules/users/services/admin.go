@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"api/modules/apikeys"
+	"api/modules/auth"
+	roleModels "api/modules/roles/models"
+	roleServices "api/modules/roles/services"
+	"api/modules/tokens"
+	"api/modules/users/models"
+	"api/pkg/apperror"
+	"api/pkg/paging"
+)
+
+var (
+	// ErrFetchUserDetails error is returned when user relations could not be retrieved
+	ErrFetchUserDetails = errors.New("unable to fetch user details")
+
+	// ErrAssignUserRole error is returned when role could not be assigned to user
+	ErrAssignUserRole = errors.New("unable to assign role to user")
+
+	// ErrUnassignUserRole error is returned when role could not be removed from user
+	ErrUnassignUserRole = errors.New("unable to remove role from user")
+
+	// ErrDeleteSelf error is returned when administrator tries to delete own account
+	ErrDeleteSelf = errors.New("unable to delete own account")
+)
+
+// UsersAdminService interface provides user management operations for administrators
+type UsersAdminService interface {
+	// UsersAdminService implementation signature
+	UsersAdminService() string
+
+	// Find retrieves users in given state for given pagination params with requested relations
+	Find(ctx context.Context, userState string, paginator *paging.Paginator, include ...string) ([]*models.UserDetails, error)
+
+	// GetByID returns user with requested relations
+	GetByID(ctx context.Context, id uint64, include ...string) (*models.UserDetails, error)
+
+	// Update updates user profile information
+	Update(ctx context.Context, id uint64, firstName *string, lastName *string) (*models.User, error)
+
+	// Delete marks user as deleted, terminates all user sessions and removes user API keys
+	Delete(ctx context.Context, adminID uint64, id uint64) error
+
+	// Restore cancels deletion of user which is not anonymized
+	Restore(ctx context.Context, id uint64) (*models.User, error)
+
+	// AssignRole assigns role to user globally and permanently and records role change
+	AssignRole(ctx context.Context, adminID uint64, id uint64, roleID uint64, clientIP string, userAgent string) error
+
+	// UnassignRole removes global role assignment from user and records role change
+	UnassignRole(ctx context.Context, adminID uint64, id uint64, roleID uint64, clientIP string, userAgent string) error
+}
+
+// NewUsersAdminService creates UsersAdminService interface implementation
+func NewUsersAdminService(
+	usersService UsersService,
+	rolesService roleServices.RolesService,
+	rolesAdminService roleServices.RolesAdminService,
+	authService auth.AuthService,
+	tokensService tokens.TokensService,
+	apiKeysService apikeys.APIKeysService) UsersAdminService {
+	return &usersAdminService{
+		usersService:      usersService,
+		rolesService:      rolesService,
+		rolesAdminService: rolesAdminService,
+		authService:       authService,
+		tokensService:     tokensService,
+		apiKeysService:    apiKeysService,
+	}
+}
+
+type usersAdminService struct {
+	usersService      UsersService
+	rolesService      roleServices.RolesService
+	rolesAdminService roleServices.RolesAdminService
+	authService       auth.AuthService
+	tokensService     tokens.TokensService
+	apiKeysService    apikeys.APIKeysService
+}
+
+func (usersAdminService) UsersAdminService() string {
+	return "usersAdminService"
+}
+
+// Find retrieves users in given state for given pagination params with requested relations
+func (svc *usersAdminService) Find(ctx context.Context, userState string, paginator *paging.Paginator, include ...string) ([]*models.UserDetails, error) {
+	users, err := svc.usersService.Find(ctx, userState, paginator)
+	if err != nil {
+		return nil, err
+	}
+
+	details := make([]*models.UserDetails, 0, len(users))
+	for _, user := range users {
+		d, err := svc.details(ctx, user, include...)
+		if err != nil {
+			return nil, apperror.New("USERS.200", ErrFetchUserDetails, err)
+		}
+		details = append(details, d)
+	}
+
+	return details, nil
+}
+
+// GetByID returns user with requested relations
+func (svc *usersAdminService) GetByID(ctx context.Context, id uint64, include ...string) (*models.UserDetails, error) {
+	user, err := svc.usersService.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	details, err := svc.details(ctx, user, include...)
+	if err != nil {
+		return nil, apperror.New("USERS.210", ErrFetchUserDetails, err)
+	}
+
+	return details, nil
+}
+
+// Update updates user profile information
+func (svc *usersAdminService) Update(ctx context.Context, id uint64, firstName *string, lastName *string) (*models.User, error) {
+	if err := svc.usersService.Update(ctx, id, firstName, lastName); err != nil {
+		return nil, err
+	}
+
+	return svc.usersService.GetByID(ctx, id)
+}
+
+// Delete marks user as deleted, terminates all user sessions and removes user API keys
+func (svc *usersAdminService) Delete(ctx context.Context, adminID uint64, id uint64) error {
+	if adminID == id {
+		return apperror.New("USERS.220", ErrDeleteUser, ErrDeleteSelf)
+	}
+
+	user, err := svc.usersService.GetByID(ctx, id)
+	if err != nil {
+		return apperror.New("USERS.221", ErrDeleteUser, err)
+	}
+
+	if err := svc.usersService.SoftDelete(ctx, user.ID); err != nil {
+		return apperror.New("USERS.222", ErrDeleteUser, err)
+	}
+
+	if err := svc.tokensService.DeleteByUserID(ctx, user.ID); err != nil {
+		return apperror.New("USERS.223", ErrDeleteUser, err)
+	}
+
+	if err := svc.apiKeysService.RemoveAll(ctx, user.ID); err != nil {
+		return apperror.New("USERS.224", ErrDeleteUser, err)
+	}
+
+	return nil
+}
+
+// Restore cancels deletion of user which is not anonymized
+func (svc *usersAdminService) Restore(ctx context.Context, id uint64) (*models.User, error) {
+	if err := svc.usersService.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+
+	// user is not returned when it does not exist or it is already anonymized
+	user, err := svc.usersService.GetByID(ctx, id)
+	if err != nil {
+		return nil, apperror.New("USERS.230", ErrRestoreUser, err)
+	}
+
+	return user, nil
+}
+
+// AssignRole assigns role to user globally and permanently
+func (svc *usersAdminService) AssignRole(ctx context.Context, adminID uint64, id uint64, roleID uint64, clientIP string, userAgent string) error {
+	user, err := svc.usersService.GetByID(ctx, id)
+	if err != nil {
+		return apperror.New("USERS.240", ErrAssignUserRole, err)
+	}
+
+	if err := svc.rolesAdminService.AssignUser(ctx, adminID, &roleModels.Assignment{UserID: user.ID, RoleID: roleID}, clientIP, userAgent); err != nil {
+		return apperror.New("USERS.241", ErrAssignUserRole, err)
+	}
+
+	return nil
+}
+
+// UnassignRole removes global role assignment from user
+func (svc *usersAdminService) UnassignRole(ctx context.Context, adminID uint64, id uint64, roleID uint64, clientIP string, userAgent string) error {
+	user, err := svc.usersService.GetByID(ctx, id)
+	if err != nil {
+		return apperror.New("USERS.250", ErrUnassignUserRole, err)
+	}
+
+	if err := svc.rolesAdminService.UnassignUser(ctx, adminID, &roleModels.Assignment{UserID: user.ID, RoleID: roleID}, clientIP, userAgent); err != nil {
+		return apperror.New("USERS.251", ErrUnassignUserRole, err)
+	}
+
+	return nil
+}
+
+// details loads requested relations of given user
+func (svc *usersAdminService) details(ctx context.Context, user *models.User, include ...string) (*models.UserDetails, error) {
+	details := &models.UserDetails{User: user}
+
+	for _, relation := range include {
+		switch relation {
+		case models.IncludeRoles:
+			userRoles, err := svc.rolesService.GetByUserID(ctx, user.ID)
+			if err != nil {
+				return nil, err
+			}
+			details.Roles = userRoles
+		case models.IncludeProviders:
+			providers, err := svc.authService.GetByUserID(ctx, user.ID)
+			if err != nil {
+				return nil, err
+			}
+			details.Providers = providers
+		}
+	}
+
+	return details, nil
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"time"
 
 	"api/modules/users/models"
 	"api/modules/users/repositories"
@@ -10,6 +11,14 @@ import (
 	"api/pkg/paging"
 )
 
+const (
+	// UserStateActive identifies users which are not deleted
+	UserStateActive = "active"
+
+	// UserStateDeleted identifies deleted users which are not anonymized yet
+	UserStateDeleted = "deleted"
+)
+
 var (
 	// ErrUserExists error is returned when we want to create new user, but the user already exists
 	// user existence is defined by unique fields in database for the user - email
@@ -34,6 +43,15 @@ var (
 	ErrInvalidUserState = errors.New("invalid user state")
 
 	ErrDeleteUser = errors.New("unable to delete user")
+
+	// ErrConfirmEmail error is returned when user email could not be confirmed
+	ErrConfirmEmail = errors.New("unable to confirm email")
+
+	// ErrRestoreUser error is returned when deleted user could not be restored
+	ErrRestoreUser = errors.New("unable to restore user")
+
+	// ErrAnonymizeUser error is returned when personal information of deleted user could not be removed
+	ErrAnonymizeUser = errors.New("unable to anonymize user")
 )
 
 // UsersService interface
@@ -50,11 +68,36 @@ type UsersService interface {
 	// GetByID returns user by given user id
 	GetByID(ctx context.Context, id uint64) (*models.User, error)
 
-	// Find retrieves all users for given filter and pagination params
-	Find(ctx context.Context, userType string, paginator *paging.Paginator) ([]*models.User, error)
+	// Find retrieves all users in given state for given filter and pagination params.
+	// Empty state is handled as UserStateActive
+	Find(ctx context.Context, userState string, paginator *paging.Paginator) ([]*models.User, error)
 
 	// Update updates user profile information
 	Update(ctx context.Context, id uint64, firstName *string, lastName *string) error
+
+	// ConfirmEmail marks email of user with given id as confirmed
+	ConfirmEmail(ctx context.Context, id uint64) error
+
+	// Delete permanently removes user with given id
+	Delete(ctx context.Context, id uint64) error
+
+	// SoftDelete marks user with given id as deleted, user data is kept until it is anonymized
+	SoftDelete(ctx context.Context, id uint64) error
+
+	// Restore cancels deletion of user with given id which is not anonymized
+	Restore(ctx context.Context, id uint64) error
+
+	// GetDeletedByEmail returns deleted user which is not anonymized for given email
+	GetDeletedByEmail(ctx context.Context, email string) (*models.User, error)
+
+	// GetDeletedByID returns deleted user which is not anonymized for given id
+	GetDeletedByID(ctx context.Context, id uint64) (*models.User, error)
+
+	// GetDeletedBefore returns users deleted before given time which are not anonymized
+	GetDeletedBefore(ctx context.Context, before time.Time) ([]*models.User, error)
+
+	// Anonymize removes personal information of deleted user with given id
+	Anonymize(ctx context.Context, id uint64) error
 }
 
 // NewUsersService creates UsersService interface implementation
@@ -126,14 +169,20 @@ func (svc *usersService) GetByID(ctx context.Context, id uint64) (*models.User,
 	return user, nil
 }
 
-// Find retrieves all users for given filter and pagination params
+// Find retrieves all users in given state for given filter and pagination params.
+// Empty state is handled as UserStateActive
 func (svc *usersService) Find(ctx context.Context, userState string, paginator *paging.Paginator) ([]*models.User, error) {
-	users, err := svc.repo.GetAll(ctx, paginator.Filter, paginator.Page, paginator.PerPage, paginator.OrderBy, paginator.OrderDir)
+	if userState != "" && userState != UserStateActive && userState != UserStateDeleted {
+		return nil, apperror.New("USERS.032", ErrFetchUsers, ErrInvalidUserState)
+	}
+	deleted := userState == UserStateDeleted
+
+	users, err := svc.repo.GetAll(ctx, paginator.Filter, deleted, paginator.Page, paginator.PerPage, paginator.OrderBy, paginator.OrderDir)
 	if err != nil {
 		return nil, apperror.New("USERS.030", ErrFetchUsers, err)
 	}
 
-	count, err := svc.repo.Count(ctx, paginator.Filter)
+	count, err := svc.repo.Count(ctx, paginator.Filter, deleted)
 	if err != nil {
 		return nil, apperror.New("USERS.031", ErrFetchUsers, err)
 	}
@@ -167,3 +216,80 @@ func (svc *usersService) Update(ctx context.Context, id uint64, firstName *strin
 	}
 	return nil
 }
+
+// ConfirmEmail marks email of user with given id as confirmed
+func (svc *usersService) ConfirmEmail(ctx context.Context, id uint64) error {
+	if err := svc.repo.ConfirmEmail(ctx, id); err != nil {
+		return apperror.New("USERS.050", ErrConfirmEmail, err)
+	}
+	return nil
+}
+
+// Delete permanently removes user with given id
+func (svc *usersService) Delete(ctx context.Context, id uint64) error {
+	if err := svc.repo.DeleteByID(ctx, id); err != nil {
+		return apperror.New("USERS.060", ErrDeleteUser, err)
+	}
+	return nil
+}
+
+// SoftDelete marks user with given id as deleted, user data is kept until it is anonymized
+func (svc *usersService) SoftDelete(ctx context.Context, id uint64) error {
+	if err := svc.repo.SoftDeleteByID(ctx, id); err != nil {
+		return apperror.New("USERS.070", ErrDeleteUser, err)
+	}
+	return nil
+}
+
+// Restore cancels deletion of user with given id which is not anonymized
+func (svc *usersService) Restore(ctx context.Context, id uint64) error {
+	if err := svc.repo.Restore(ctx, id); err != nil {
+		return apperror.New("USERS.080", ErrRestoreUser, err)
+	}
+	return nil
+}
+
+// GetDeletedByEmail returns deleted user which is not anonymized for given email
+func (svc *usersService) GetDeletedByEmail(ctx context.Context, email string) (*models.User, error) {
+	user, err := svc.repo.GetDeletedByEmail(ctx, email)
+	if err != nil {
+		return nil, apperror.New("USERS.090", ErrFetchUser, err)
+	}
+
+	if user == nil {
+		return nil, apperror.New("USERS.091", ErrFetchUser, ErrUserNotExist)
+	}
+
+	return user, nil
+}
+
+// GetDeletedByID returns deleted user which is not anonymized for given id
+func (svc *usersService) GetDeletedByID(ctx context.Context, id uint64) (*models.User, error) {
+	user, err := svc.repo.GetDeletedByID(ctx, id)
+	if err != nil {
+		return nil, apperror.New("USERS.120", ErrFetchUser, err)
+	}
+
+	if user == nil {
+		return nil, apperror.New("USERS.121", ErrFetchUser, ErrUserNotExist)
+	}
+
+	return user, nil
+}
+
+// GetDeletedBefore returns users deleted before given time which are not anonymized
+func (svc *usersService) GetDeletedBefore(ctx context.Context, before time.Time) ([]*models.User, error) {
+	users, err := svc.repo.GetDeletedBefore(ctx, before)
+	if err != nil {
+		return nil, apperror.New("USERS.100", ErrFetchUsers, err)
+	}
+	return users, nil
+}
+
+// Anonymize removes personal information of deleted user with given id
+func (svc *usersService) Anonymize(ctx context.Context, id uint64) error {
+	if err := svc.repo.Anonymize(ctx, id); err != nil {
+		return apperror.New("USERS.110", ErrAnonymizeUser, err)
+	}
+	return nil
+}
@@ -3,9 +3,52 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
-//AppConfig holds all application configuration
+const (
+	// UnconfirmedLoginAllow allows users with unconfirmed email to login with full access
+	UnconfirmedLoginAllow = "allow"
+
+	// UnconfirmedLoginLimited allows users with unconfirmed email to login with reduced scope
+	UnconfirmedLoginLimited = "limited"
+
+	// UnconfirmedLoginDeny prevents users with unconfirmed email from logging in
+	UnconfirmedLoginDeny = "deny"
+)
+
+const (
+	// JWTAlgorithmRS256 signs tokens with RSA keys
+	JWTAlgorithmRS256 = "RS256"
+
+	// JWTAlgorithmES256 signs tokens with ECDSA P-256 keys
+	JWTAlgorithmES256 = "ES256"
+
+	// JWTAlgorithmEdDSA signs tokens with Ed25519 keys
+	JWTAlgorithmEdDSA = "EdDSA"
+)
+
+// IdentityProvider holds configuration of external OpenID Connect identity provider
+type IdentityProvider struct {
+	// Name identifies provider in URLs and linked accounts
+	Name string
+
+	// Issuer is used for provider discovery and has to match `iss` claim of ID tokens
+	Issuer string
+
+	// ClientID is client identifier registered at provider
+	ClientID string
+
+	// ClientSecret is client secret registered at provider, empty for public clients
+	ClientSecret string
+
+	// Scopes requested from provider, always including `openid`
+	Scopes []string
+}
+
+// AppConfig holds all application configuration
 type AppConfig interface {
 	// Env returns execution environment configuration
 	Env() string
@@ -16,49 +59,278 @@ type AppConfig interface {
 	// Addr returns http serving listen address
 	Addr() string
 
-	// RSAPrivateKey returns string content for RSA key
+	// RSAPrivateKey returns string content for private signing key, key type has to match JWTAlgorithm
 	RSAPrivateKey() string
 
-	// RSAPublicKey returns string content for RSA key
+	// RSAPublicKey returns string content for public signing key, key type has to match JWTAlgorithm
 	RSAPublicKey() string
 
-	// RSAKeyPassword returns password for RSA key
+	// RSAKeyPassword returns password for private signing keys
 	RSAKeyPassword() string
+
+	// JWTAlgorithm returns algorithm used for token signing, one of JWTAlgorithm constants
+	JWTAlgorithm() string
+
+	// JWTIssuer returns issuer of access tokens, used as `iss` claim and OpenID Connect issuer
+	JWTIssuer() string
+
+	// JWTAudience returns audience of access tokens, used as `aud` claim
+	JWTAudience() string
+
+	// JWTKeysDir returns directory with token signing keys, RSA key files are not used when it is set
+	JWTKeysDir() string
+
+	// JWTKeysReloadInterval returns interval of the job which reloads signing keys from keys directory
+	JWTKeysReloadInterval() time.Duration
+
+	// JWTKeysActivationDelay returns time rotated signing key is published only for verification before it is used for signing
+	JWTKeysActivationDelay() time.Duration
+
+	// AccessTokenLifetime returns lifetime of access tokens
+	AccessTokenLifetime() time.Duration
+
+	// RefreshTokenLifetime returns lifetime of refresh tokens, it is the default session lifetime
+	RefreshTokenLifetime() time.Duration
+
+	// RoleSessionLifetimes returns session lifetimes overriding RefreshTokenLifetime for users with given role names
+	RoleSessionLifetimes() map[string]time.Duration
+
+	// APIKeyLifetime returns lifetime of API keys
+	APIKeyLifetime() time.Duration
+
+	// JWTClockSkew returns tolerated clock difference when token time claims are verified
+	JWTClockSkew() time.Duration
+
+	// Notifier returns name of notifier used for delivering messages to users
+	Notifier() string
+
+	// OutboxDir returns directory where outbox notifier stores messages
+	OutboxDir() string
+
+	// SMTPAddr returns SMTP server address
+	SMTPAddr() string
+
+	// SMTPFrom returns sender address used for outgoing emails
+	SMTPFrom() string
+
+	// SMTPUser returns SMTP server username
+	SMTPUser() string
+
+	// SMTPPassword returns SMTP server password
+	SMTPPassword() string
+
+	// UnconfirmedLogin returns login policy for users with unconfirmed email
+	UnconfirmedLogin() string
+
+	// MFAIssuer returns issuer name displayed in authenticator apps
+	MFAIssuer() string
+
+	// DenyListStore returns name of store used for revoked access tokens
+	DenyListStore() string
+
+	// LockoutStore returns name of store used for failed login attempts counters
+	LockoutStore() string
+
+	// LockoutThreshold returns number of failed login attempts after which user is locked out
+	LockoutThreshold() int
+
+	// LockoutIPThreshold returns number of failed login attempts after which client IP is locked out
+	LockoutIPThreshold() int
+
+	// LockoutDuration returns duration of the first lockout
+	LockoutDuration() time.Duration
+
+	// LockoutMaxDuration returns maximal lockout duration
+	LockoutMaxDuration() time.Duration
+
+	// AccountDeletionGracePeriod returns duration after which personal information of deleted account is removed
+	AccountDeletionGracePeriod() time.Duration
+
+	// AccountPurgeInterval returns interval of the job which anonymizes deleted accounts
+	AccountPurgeInterval() time.Duration
+
+	// RoleAssignmentSweepInterval returns interval of the job which removes expired role assignments
+	RoleAssignmentSweepInterval() time.Duration
+
+	// IdentityProviders returns external OpenID Connect identity providers users can login with
+	IdentityProviders() []*IdentityProvider
+
+	// IdentityProviderCallbackURL returns base URL of callback endpoints registered at identity providers
+	IdentityProviderCallbackURL() string
 }
 
 // New creates new Configuration object
 func New() AppConfig {
-	privateKeyPath := mustGetEnv("RSA_PRIVATE_KEY")
-	publicKeyPath := mustGetEnv("RSA_PUBLIC_KEY")
 	privateKeyPwd := getEnv("RSA_PRIVATE_KEY_PASSWORD", "")
+	jwtKeysDir := getEnv("JWT_KEYS_DIR", "")
 
-	privateKey, err := os.ReadFile(privateKeyPath)
-	if err != nil {
-		log.Fatal(err)
+	jwtAlgorithm := getEnv("JWT_ALGORITHM", JWTAlgorithmRS256)
+	if jwtAlgorithm != JWTAlgorithmRS256 && jwtAlgorithm != JWTAlgorithmES256 && jwtAlgorithm != JWTAlgorithmEdDSA {
+		log.Fatalf(" variable `JWT_ALGORITHM` has invalid value `%s`", jwtAlgorithm)
 	}
 
-	publicKey, err := os.ReadFile(publicKeyPath)
-	if err != nil {
-		log.Fatal(err)
+	unconfirmedLogin := getEnv("UNCONFIRMED_LOGIN", UnconfirmedLoginAllow)
+	if unconfirmedLogin != UnconfirmedLoginAllow && unconfirmedLogin != UnconfirmedLoginLimited && unconfirmedLogin != UnconfirmedLoginDeny {
+		log.Fatalf(" variable `UNCONFIRMED_LOGIN` has invalid value `%s`", unconfirmedLogin)
+	}
+
+	lockoutStore := getEnv("LOCKOUT_STORE", "memory")
+	if lockoutStore != "memory" && lockoutStore != "db" {
+		log.Fatalf(" variable `LOCKOUT_STORE` has invalid value `%s`", lockoutStore)
+	}
+
+	denyListStore := getEnv("DENY_LIST_STORE", "memory")
+	if denyListStore != "memory" && denyListStore != "db" {
+		log.Fatalf(" variable `DENY_LIST_STORE` has invalid value `%s`", denyListStore)
+	}
+
+	lockoutDuration := getEnvDuration("LOCKOUT_DURATION", time.Minute)
+	lockoutMaxDuration := getEnvDuration("LOCKOUT_MAX_DURATION", time.Hour)
+	if lockoutMaxDuration < lockoutDuration {
+		log.Fatalf(" variable `LOCKOUT_MAX_DURATION` can not be less than `LOCKOUT_DURATION`")
+	}
+
+	accountPurgeInterval := getEnvDuration("ACCOUNT_PURGE_INTERVAL", time.Hour)
+	if accountPurgeInterval <= 0 {
+		log.Fatalf(" variable `ACCOUNT_PURGE_INTERVAL` has to be positive duration")
+	}
+
+	roleAssignmentSweepInterval := getEnvDuration("ROLE_ASSIGNMENT_SWEEP_INTERVAL", time.Minute)
+	if roleAssignmentSweepInterval <= 0 {
+		log.Fatalf(" variable `ROLE_ASSIGNMENT_SWEEP_INTERVAL` has to be positive duration")
+	}
+
+	jwtKeysReloadInterval := getEnvDuration("JWT_KEYS_RELOAD_INTERVAL", time.Minute)
+	if jwtKeysReloadInterval <= 0 {
+		log.Fatalf(" variable `JWT_KEYS_RELOAD_INTERVAL` has to be positive duration")
+	}
+
+	jwtKeysActivationDelay := getEnvDuration("JWT_KEYS_ACTIVATION_DELAY", 10*time.Minute)
+	if jwtKeysActivationDelay < 0 {
+		log.Fatalf(" variable `JWT_KEYS_ACTIVATION_DELAY` has to be non negative duration")
+	}
+
+	accessTokenLifetime := getEnvDuration("ACCESS_TOKEN_LIFETIME", 16*time.Minute)
+	if accessTokenLifetime <= 0 {
+		log.Fatalf(" variable `ACCESS_TOKEN_LIFETIME` has to be positive duration")
+	}
+
+	refreshTokenLifetime := getEnvDuration("REFRESH_TOKEN_LIFETIME", 30*24*time.Hour)
+	if refreshTokenLifetime < accessTokenLifetime {
+		log.Fatalf(" variable `REFRESH_TOKEN_LIFETIME` can not be less than `ACCESS_TOKEN_LIFETIME`")
+	}
+
+	roleSessionLifetimes := getEnvDurations("ROLE_SESSION_LIFETIMES")
+	for role, lifetime := range roleSessionLifetimes {
+		if lifetime <= 0 {
+			log.Fatalf(" variable `ROLE_SESSION_LIFETIMES` has to hold positive duration for role `%s`", role)
+		}
+	}
+
+	apiKeyLifetime := getEnvDuration("API_KEY_LIFETIME", 876000*time.Hour)
+	if apiKeyLifetime <= 0 {
+		log.Fatalf(" variable `API_KEY_LIFETIME` has to be positive duration")
+	}
+
+	jwtClockSkew := getEnvDuration("JWT_CLOCK_SKEW", 30*time.Second)
+	if jwtClockSkew < 0 || jwtClockSkew >= accessTokenLifetime {
+		log.Fatalf(" variable `JWT_CLOCK_SKEW` has to be non negative duration less than `ACCESS_TOKEN_LIFETIME`")
+	}
+
+	jwtIssuer := strings.TrimSuffix(getEnv("JWT_ISSUER", "http://localhost:5000"), "/")
+
+	// single key pair is used only when keys directory is not configured
+	var privateKey, publicKey []byte
+	if jwtKeysDir == "" {
+		var err error
+		privateKey, err = os.ReadFile(mustGetEnv("RSA_PRIVATE_KEY"))
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		publicKey, err = os.ReadFile(mustGetEnv("RSA_PUBLIC_KEY"))
+		if err != nil {
+			log.Fatal(err)
+		}
 	}
 
 	return &config{
-		env:            getEnv("ENV", "development"),
-		logLevel:       getEnv("LOG_LEVEL", "error"),
-		addr:           getEnv("ADDR", ""),
-		rsaPrivateKey:  string(privateKey),
-		rsaPublicKey:   string(publicKey),
-		rsaKeyPassword: privateKeyPwd,
+		env:                         getEnv("ENV", "development"),
+		logLevel:                    getEnv("LOG_LEVEL", "error"),
+		addr:                        getEnv("ADDR", ""),
+		rsaPrivateKey:               string(privateKey),
+		rsaPublicKey:                string(publicKey),
+		rsaKeyPassword:              privateKeyPwd,
+		jwtAlgorithm:                jwtAlgorithm,
+		jwtIssuer:                   jwtIssuer,
+		jwtAudience:                 getEnv("JWT_AUDIENCE", "core-api"),
+		jwtKeysDir:                  jwtKeysDir,
+		jwtKeysReloadInterval:       jwtKeysReloadInterval,
+		jwtKeysActivationDelay:      jwtKeysActivationDelay,
+		accessTokenLifetime:         accessTokenLifetime,
+		refreshTokenLifetime:        refreshTokenLifetime,
+		roleSessionLifetimes:        roleSessionLifetimes,
+		apiKeyLifetime:              apiKeyLifetime,
+		jwtClockSkew:                jwtClockSkew,
+		notifier:                    getEnv("NOTIFIER", "outbox"),
+		outboxDir:                   getEnv("OUTBOX_DIR", "./outbox"),
+		smtpAddr:                    getEnv("SMTP_ADDR", ""),
+		smtpFrom:                    getEnv("SMTP_FROM", ""),
+		smtpUser:                    getEnv("SMTP_USER", ""),
+		smtpPassword:                getEnv("SMTP_PASSWORD", ""),
+		unconfirmedLogin:            unconfirmedLogin,
+		mfaIssuer:                   getEnv("MFA_ISSUER", "core-api"),
+		denyListStore:               denyListStore,
+		lockoutStore:                lockoutStore,
+		lockoutThreshold:            getEnvInt("LOCKOUT_THRESHOLD", 5),
+		lockoutIPThreshold:          getEnvInt("LOCKOUT_IP_THRESHOLD", 20),
+		lockoutDuration:             lockoutDuration,
+		lockoutMaxDuration:          lockoutMaxDuration,
+		accountDeletionGracePeriod:  getEnvDuration("ACCOUNT_DELETION_GRACE_PERIOD", 30*24*time.Hour),
+		accountPurgeInterval:        accountPurgeInterval,
+		roleAssignmentSweepInterval: roleAssignmentSweepInterval,
+		identityProviders:           getIdentityProviders("IDENTITY_PROVIDERS"),
+		identityProviderCallbackURL: strings.TrimSuffix(getEnv("IDP_CALLBACK_URL", jwtIssuer), "/"),
 	}
 }
 
 type config struct {
-	env            string
-	logLevel       string
-	addr           string
-	rsaPrivateKey  string
-	rsaPublicKey   string
-	rsaKeyPassword string
+	env                         string
+	logLevel                    string
+	addr                        string
+	rsaPrivateKey               string
+	rsaPublicKey                string
+	rsaKeyPassword              string
+	jwtAlgorithm                string
+	jwtIssuer                   string
+	jwtAudience                 string
+	jwtKeysDir                  string
+	jwtKeysReloadInterval       time.Duration
+	jwtKeysActivationDelay      time.Duration
+	accessTokenLifetime         time.Duration
+	refreshTokenLifetime        time.Duration
+	roleSessionLifetimes        map[string]time.Duration
+	apiKeyLifetime              time.Duration
+	jwtClockSkew                time.Duration
+	notifier                    string
+	outboxDir                   string
+	smtpAddr                    string
+	smtpFrom                    string
+	smtpUser                    string
+	smtpPassword                string
+	unconfirmedLogin            string
+	mfaIssuer                   string
+	denyListStore               string
+	lockoutStore                string
+	lockoutThreshold            int
+	lockoutIPThreshold          int
+	lockoutDuration             time.Duration
+	lockoutMaxDuration          time.Duration
+	accountDeletionGracePeriod  time.Duration
+	accountPurgeInterval        time.Duration
+	roleAssignmentSweepInterval time.Duration
+	identityProviders           []*IdentityProvider
+	identityProviderCallbackURL string
 }
 
 // Env returns execution environment configuration
@@ -76,21 +348,171 @@ func (c *config) Addr() string {
 	return c.addr
 }
 
-// RSAPrivateKey returns string content for RSA key
+// RSAPrivateKey returns string content for private signing key, key type has to match JWTAlgorithm
 func (c *config) RSAPrivateKey() string {
 	return c.rsaPrivateKey
 }
 
-// RSAPublicKey returns string content for RSA key
+// RSAPublicKey returns string content for public signing key, key type has to match JWTAlgorithm
 func (c *config) RSAPublicKey() string {
 	return c.rsaPublicKey
 }
 
-// RSAKeyPassword returns password for RSA key
+// RSAKeyPassword returns password for private signing keys
 func (c *config) RSAKeyPassword() string {
 	return c.rsaKeyPassword
 }
 
+// JWTAlgorithm returns algorithm used for token signing, one of JWTAlgorithm constants
+func (c *config) JWTAlgorithm() string {
+	return c.jwtAlgorithm
+}
+
+// JWTIssuer returns issuer of access tokens, used as `iss` claim and OpenID Connect issuer
+func (c *config) JWTIssuer() string {
+	return c.jwtIssuer
+}
+
+// JWTAudience returns audience of access tokens, used as `aud` claim
+func (c *config) JWTAudience() string {
+	return c.jwtAudience
+}
+
+// JWTKeysDir returns directory with token signing keys, RSA key files are not used when it is set
+func (c *config) JWTKeysDir() string {
+	return c.jwtKeysDir
+}
+
+// JWTKeysReloadInterval returns interval of the job which reloads signing keys from keys directory
+func (c *config) JWTKeysReloadInterval() time.Duration {
+	return c.jwtKeysReloadInterval
+}
+
+// JWTKeysActivationDelay returns time rotated signing key is published only for verification before it is used for signing
+func (c *config) JWTKeysActivationDelay() time.Duration {
+	return c.jwtKeysActivationDelay
+}
+
+// AccessTokenLifetime returns lifetime of access tokens
+func (c *config) AccessTokenLifetime() time.Duration {
+	return c.accessTokenLifetime
+}
+
+// RefreshTokenLifetime returns lifetime of refresh tokens, it is the default session lifetime
+func (c *config) RefreshTokenLifetime() time.Duration {
+	return c.refreshTokenLifetime
+}
+
+// RoleSessionLifetimes returns session lifetimes overriding RefreshTokenLifetime for users with given role names
+func (c *config) RoleSessionLifetimes() map[string]time.Duration {
+	return c.roleSessionLifetimes
+}
+
+// APIKeyLifetime returns lifetime of API keys
+func (c *config) APIKeyLifetime() time.Duration {
+	return c.apiKeyLifetime
+}
+
+// JWTClockSkew returns tolerated clock difference when token time claims are verified
+func (c *config) JWTClockSkew() time.Duration {
+	return c.jwtClockSkew
+}
+
+// Notifier returns name of notifier used for delivering messages to users
+func (c *config) Notifier() string {
+	return c.notifier
+}
+
+// OutboxDir returns directory where outbox notifier stores messages
+func (c *config) OutboxDir() string {
+	return c.outboxDir
+}
+
+// SMTPAddr returns SMTP server address
+func (c *config) SMTPAddr() string {
+	return c.smtpAddr
+}
+
+// SMTPFrom returns sender address used for outgoing emails
+func (c *config) SMTPFrom() string {
+	return c.smtpFrom
+}
+
+// SMTPUser returns SMTP server username
+func (c *config) SMTPUser() string {
+	return c.smtpUser
+}
+
+// SMTPPassword returns SMTP server password
+func (c *config) SMTPPassword() string {
+	return c.smtpPassword
+}
+
+// UnconfirmedLogin returns login policy for users with unconfirmed email
+func (c *config) UnconfirmedLogin() string {
+	return c.unconfirmedLogin
+}
+
+// MFAIssuer returns issuer name displayed in authenticator apps
+func (c *config) MFAIssuer() string {
+	return c.mfaIssuer
+}
+
+// DenyListStore returns name of store used for revoked access tokens
+func (c *config) DenyListStore() string {
+	return c.denyListStore
+}
+
+// LockoutStore returns name of store used for failed login attempts counters
+func (c *config) LockoutStore() string {
+	return c.lockoutStore
+}
+
+// LockoutThreshold returns number of failed login attempts after which user is locked out
+func (c *config) LockoutThreshold() int {
+	return c.lockoutThreshold
+}
+
+// LockoutIPThreshold returns number of failed login attempts after which client IP is locked out
+func (c *config) LockoutIPThreshold() int {
+	return c.lockoutIPThreshold
+}
+
+// LockoutDuration returns duration of the first lockout
+func (c *config) LockoutDuration() time.Duration {
+	return c.lockoutDuration
+}
+
+// LockoutMaxDuration returns maximal lockout duration
+func (c *config) LockoutMaxDuration() time.Duration {
+	return c.lockoutMaxDuration
+}
+
+// AccountDeletionGracePeriod returns duration after which personal information of deleted account is removed
+func (c *config) AccountDeletionGracePeriod() time.Duration {
+	return c.accountDeletionGracePeriod
+}
+
+// AccountPurgeInterval returns interval of the job which anonymizes deleted accounts
+func (c *config) AccountPurgeInterval() time.Duration {
+	return c.accountPurgeInterval
+}
+
+// RoleAssignmentSweepInterval returns interval of the job which removes expired role assignments
+func (c *config) RoleAssignmentSweepInterval() time.Duration {
+	return c.roleAssignmentSweepInterval
+}
+
+// IdentityProviders returns external OpenID Connect identity providers users can login with
+func (c *config) IdentityProviders() []*IdentityProvider {
+	return c.identityProviders
+}
+
+// IdentityProviderCallbackURL returns base URL of callback endpoints registered at identity providers
+func (c *config) IdentityProviderCallbackURL() string {
+	return c.identityProviderCallbackURL
+}
+
 // getEnv returns value for given key from environment
 // if key is not present in environment it returns defaultValue
 func getEnv(key, defaultValue string) string {
@@ -101,6 +523,103 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvInt returns integer value for given key from environment
+// if key is not present in environment it returns defaultValue
+func getEnvInt(key string, defaultValue int) int {
+	v := os.Getenv(key)
+	if len(v) == 0 {
+		return defaultValue
+	}
+
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		log.Fatalf(" variable `%s` has invalid value `%s`", key, v)
+	}
+	return i
+}
+
+// getEnvDuration returns duration value (e.g. `15m`) for given key from environment
+// if key is not present in environment it returns defaultValue
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if len(v) == 0 {
+		return defaultValue
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Fatalf(" variable `%s` has invalid value `%s`", key, v)
+	}
+	return d
+}
+
+// getEnvDurations returns durations (e.g. `Admin=8h,Support=1h`) for given key from environment
+// if key is not present in environment it returns empty map
+func getEnvDurations(key string) map[string]time.Duration {
+	durations := map[string]time.Duration{}
+
+	v := os.Getenv(key)
+	if len(v) == 0 {
+		return durations
+	}
+
+	for _, pair := range strings.Split(v, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			log.Fatalf(" variable `%s` has invalid value `%s`", key, v)
+		}
+
+		d, err := time.ParseDuration(parts[1])
+		if err != nil {
+			log.Fatalf(" variable `%s` has invalid value `%s`", key, v)
+		}
+		durations[parts[0]] = d
+	}
+	return durations
+}
+
+// getIdentityProviders returns identity providers whose names (e.g. `google,corp`) are listed under given key.
+// Each provider is configured by `IDP_<NAME>_ISSUER`, `IDP_<NAME>_CLIENT_ID`, `IDP_<NAME>_CLIENT_SECRET`
+// and `IDP_<NAME>_SCOPES` variables
+func getIdentityProviders(key string) []*IdentityProvider {
+	providers := []*IdentityProvider{}
+
+	v := os.Getenv(key)
+	if len(v) == 0 {
+		return providers
+	}
+
+	seen := map[string]bool{}
+	for _, name := range strings.Split(v, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		// local provider name is reserved for password authentication
+		if name == "" || name == "local" || seen[name] || strings.ContainsAny(name, "/?#") {
+			log.Fatalf(" variable `%s` has invalid value `%s`", key, v)
+		}
+		seen[name] = true
+
+		prefix := "IDP_" + strings.ToUpper(name) + "_"
+		provider := &IdentityProvider{
+			Name:         name,
+			Issuer:       mustGetEnv(prefix + "ISSUER"),
+			ClientID:     mustGetEnv(prefix + "CLIENT_ID"),
+			ClientSecret: getEnv(prefix+"CLIENT_SECRET", ""),
+			Scopes:       strings.Fields(strings.ReplaceAll(getEnv(prefix+"SCOPES", "openid email profile"), ",", " ")),
+		}
+
+		hasOpenID := false
+		for _, scope := range provider.Scopes {
+			hasOpenID = hasOpenID || scope == "openid"
+		}
+		if !hasOpenID {
+			log.Fatalf(" variable `%sSCOPES` has to include `openid` scope", prefix)
+		}
+
+		providers = append(providers, provider)
+	}
+	return providers
+}
+
 // mustGetEnv returns value for given key from environment
 // if key is not present in environment function will panic
 func mustGetEnv(key string) string {
@@ -17,3 +17,9 @@ func TxFromContext(ctx context.Context) (*sql.Tx, bool) {
 func NewTxContext(ctx context.Context, tx *sql.Tx) context.Context {
 	return context.WithValue(ctx, txKey{}, tx)
 }
+
+// DetachTxContext creates context which is not bound to db transaction.
+// Changes made using detached context are committed even if bounded transaction is rolled back
+func DetachTxContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, txKey{}, nil)
+}
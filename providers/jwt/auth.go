@@ -1,21 +1,30 @@
 package jwt
 
 import (
-	"crypto/rsa"
-	"encoding/base64"
-	"encoding/pem"
+	"context"
 	"errors"
 	"fmt"
-	"math/big"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"api/providers/config"
+	"api/providers/db"
 	"api/providers/log"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/go-flow/flow/v2"
+	"github.com/google/uuid"
+)
+
+const (
+	// ScopeAuthorized is granted to fully authenticated users
+	ScopeAuthorized = "Authorized"
+
+	// ScopeUnconfirmed is granted to users which did not confirm their email
+	ScopeUnconfirmed = "Unconfirmed"
 )
 
 // ErrForbiddden is returned when user does not have required claims
@@ -23,28 +32,83 @@ var ErrForbiddden = errors.New("forbidden")
 
 var ErrUnathorized = errors.New("unauthorized")
 
+// ErrRevokedToken is returned when access token was revoked before it expired
+var ErrRevokedToken = errors.New("token revoked")
+
+// APIKeyVerifier verifies API keys presented in X-API-KEY header
+type APIKeyVerifier interface {
+	// VerifyAPIKey returns id of the user owning given API key and scopes of the key
+	VerifyAPIKey(ctx context.Context, key string) (uint64, []string, error)
+}
+
 // TokenAuth interface
 type TokenAuth interface {
 	// TokenAuth returns service implementation signature
 	TokenAuth() string
 
+	// JWKS returns public keys accepted for token verification
 	JWKS() *JWKS
 
+	// RotateKeys generates new signing key, which becomes active after configured activation delay. Previous keys are kept for verification.
+	// Returns kid of the new key
+	RotateKeys() (string, error)
+
+	// ReloadKeys loads keys from keys directory, so keys rotated by other instances are used
+	ReloadKeys() error
+
+	// SessionLifetime returns lifetime of session with given scope.
+	// Shortest lifetime configured for roles in scope is used, default refresh token lifetime otherwise
+	SessionLifetime(scope ...string) time.Duration
+
+	// AccessTokenLifetime returns lifetime of access token with given scope, it does not exceed session lifetime
+	AccessTokenLifetime(scope ...string) time.Duration
+
+	// GenerateAccessToken generates access token with given scope, token does not outlive the session
 	GenerateAccessToken(userID uint64, scope ...string) (string, error)
 
 	VerifyAccessToken(token string, scope ...string) (uint64, string, error)
 
-	GenerateRefreshToken(token string) (string, error)
+	// GenerateClientAccessToken generates access token issued to OAuth client itself, not to any user
+	GenerateClientAccessToken(clientID string, scope ...string) (string, error)
 
-	VerifyRefreshToken(tokenString string) (string, error)
+	// VerifyClientAccessToken verifies access token issued to OAuth client and returns client id and token scope
+	VerifyClientAccessToken(token string) (string, string, error)
+
+	// RevokeAccessToken adds given access token to deny list so it can not be used until it expires
+	RevokeAccessToken(accessToken string) error
+
+	// GenerateMFAToken generates short lived token which can only be used to complete MFA login step
+	GenerateMFAToken(userID uint64) (string, error)
+
+	// VerifyMFAToken verifies MFA step token and returns user id it was issued for
+	VerifyMFAToken(mfaToken string) (uint64, error)
+
+	// RevokeMFAToken adds given MFA step token to deny list so it can be used only once
+	RevokeMFAToken(mfaToken string) error
 
-	GenerateAPIKey(userID uint64) (string, error)
+	// GenerateStateToken generates short lived token carrying given values, e.g. state of external login
+	GenerateStateToken(values map[string]string, lifetime time.Duration) (string, error)
 
-	VerifyAPIKey(tokenString string) (uint64, error)
+	// VerifyStateToken verifies state token and returns values it carries
+	VerifyStateToken(stateToken string) (map[string]string, error)
 
+	// GenerateRefreshToken generates refresh token for given stored token, expiring together with it
+	GenerateRefreshToken(token string, expiresAt time.Time) (string, error)
+
+	VerifyRefreshToken(tokenString string) (string, error)
+
+	// AuthorizeRequest authorizes request when access token scope holds at least one of given claims
 	AuthorizeRequest(roles ...string) flow.MiddlewareHandlerFunc
 
-	AuthorizeAPIKey() flow.MiddlewareHandlerFunc
+	// RequirePermissions authorizes request only when access token scope holds all given permissions
+	RequirePermissions(permissions ...string) flow.MiddlewareHandlerFunc
+
+	// Authorize authorizes request when access token scope is accepted by given authorizer
+	Authorize(authorizer Authorizer) flow.MiddlewareHandlerFunc
+
+	// AuthorizeAPIKey authorizes request with API key verified by given verifier.
+	// Key owner id and key scopes are added to request context, so following middlewares can check them
+	AuthorizeAPIKey(verifier APIKeyVerifier) flow.MiddlewareHandlerFunc
 
 	// RequestUserID returns userId from request context
 	// if user isid is not found then unathorized error is returned
@@ -53,238 +117,575 @@ type TokenAuth interface {
 	// RequestUserClaims returns authorization claims from request context
 	// if claims are not found then unathorized error is returned
 	RequestUserClaims(r *http.Request) ([]string, error)
+
+	// RequestScope returns parsed access token scope from request context
+	// if scope is not found then unathorized error is returned
+	RequestScope(r *http.Request) (Scope, error)
+
+	// RequestAccessToken returns access token from request authorization header
+	RequestAccessToken(r *http.Request) string
 }
 
-func NewAuth(cfg config.AppConfig, logger log.Logger) TokenAuth {
-	var privateKey *rsa.PrivateKey
+func NewAuth(cfg config.AppConfig, store db.Store, logger log.Logger) TokenAuth {
+	var keys *keySet
 	var err error
 
-	if cfg.RSAKeyPassword() != "" {
-		privateKey, err = jwt.ParseRSAPrivateKeyFromPEMWithPassword([]byte(cfg.RSAPrivateKey()), cfg.RSAKeyPassword())
+	var denyList DenyList
+	switch cfg.DenyListStore() {
+	case "db":
+		denyList = NewDBDenyList(store)
+	default:
+		denyList = NewMemoryDenyList()
+	}
+
+	if cfg.JWTKeysDir() != "" {
+		keys, err = loadKeySet(cfg.JWTKeysDir(), cfg.RSAKeyPassword(), time.Now())
 	} else {
-		privateKey, err = jwt.ParseRSAPrivateKeyFromPEM([]byte(cfg.RSAPrivateKey()))
+		keys, err = newBaseKeySet(cfg.RSAPrivateKey(), cfg.RSAPublicKey(), cfg.RSAKeyPassword(), cfg.JWTAlgorithm())
 	}
 
 	if err != nil {
 		logger.Fatal(err)
 	}
 
-	publicKey, err := jwt.ParseRSAPublicKeyFromPEM([]byte(cfg.RSAPublicKey()))
+	return &jwtTokenAuth{
+		issuer:          cfg.JWTIssuer(),
+		audience:        cfg.JWTAudience(),
+		keys:            keys,
+		keysDir:         cfg.JWTKeysDir(),
+		keyPassword:     cfg.RSAKeyPassword(),
+		algorithm:       cfg.JWTAlgorithm(),
+		activationDelay: cfg.JWTKeysActivationDelay(),
+		denyList:        denyList,
+		logger:          logger,
+		clockSkew:       cfg.JWTClockSkew(),
+		lifetimes: tokenLifetimes{
+			access:  cfg.AccessTokenLifetime(),
+			refresh: cfg.RefreshTokenLifetime(),
+			roles:   cfg.RoleSessionLifetimes(),
+		},
+	}
+}
+
+type jwtTokenAuth struct {
+	issuer          string
+	audience        string
+	mu              sync.RWMutex
+	keys            *keySet
+	keysDir         string
+	keyPassword     string
+	algorithm       string
+	activationDelay time.Duration
+	lifetimes       tokenLifetimes
+	clockSkew       time.Duration
+	reloadedAt      time.Time
+	denyList        DenyList
+	logger          log.Logger
+}
 
+// tokenLifetimes holds configured token lifetimes
+type tokenLifetimes struct {
+	access  time.Duration
+	refresh time.Duration
+	roles   map[string]time.Duration
+}
+
+func (*jwtTokenAuth) TokenAuth() string {
+	return "jwtTokenAuth"
+}
+
+// JWKS returns public keys accepted for token verification
+func (svc *jwtTokenAuth) JWKS() *JWKS {
+	svc.mu.RLock()
+	defer svc.mu.RUnlock()
+	return svc.keys.jwks()
+}
+
+// RotateKeys generates new signing key in keys directory, which becomes active after configured activation delay.
+// Previous keys remain available for verification until they are removed from keys directory
+func (svc *jwtTokenAuth) RotateKeys() (string, error) {
+	if svc.keysDir == "" {
+		return "", ErrKeysNotRotatable
+	}
+
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+
+	kid, err := generateKey(svc.keysDir, svc.keyPassword, svc.algorithm, time.Now().Add(svc.activationDelay))
 	if err != nil {
-		logger.Fatal(err)
+		return "", err
 	}
 
-	privateKey.PublicKey = *publicKey
+	return kid, svc.reloadKeys()
+}
 
-	modulus := publicKey.N
-	exponent := big.NewInt(int64(publicKey.E))
+// ReloadKeys loads keys from keys directory, so keys rotated by other instances are used
+func (svc *jwtTokenAuth) ReloadKeys() error {
+	if svc.keysDir == "" {
+		return nil
+	}
 
-	jwk := new(JWK)
-	jwk.Alg = "RS256"
-	jwk.Use = "sig"
-	jwk.Kid = "base"
-	jwk.Kty = "RSA"
-	jwk.N = base64.RawURLEncoding.EncodeToString(modulus.Bytes())
-	jwk.E = base64.RawURLEncoding.EncodeToString(exponent.Bytes())
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	return svc.reloadKeys()
+}
 
-	block, _ := pem.Decode([]byte(cfg.RSAPublicKey()))
-	jwk.X5C = []string{
-		base64.StdEncoding.EncodeToString(block.Bytes),
+// reloadKeys replaces key set with keys from keys directory, caller has to hold write lock
+func (svc *jwtTokenAuth) reloadKeys() error {
+	keys, err := loadKeySet(svc.keysDir, svc.keyPassword, time.Now())
+	if err != nil {
+		return err
 	}
 
-	jwks := new(JWKS)
-	jwks.Keys = append(jwks.Keys, jwk)
+	svc.keys = keys
+	svc.reloadedAt = time.Now()
+	return nil
+}
 
-	return &jwtTokenAuth{
-		privateKey: privateKey,
-		jwks:       jwks,
+// newToken creates unsigned token of given type, signing method is set by sign
+func newToken(typ string) *jwt.Token {
+	return &jwt.Token{
+		Header: map[string]interface{}{
+			"typ": typ,
+		},
+		Claims: jwt.MapClaims{},
 	}
 }
 
-type jwtTokenAuth struct {
-	privateKey *rsa.PrivateKey
-	jwks       *JWKS
+// sign signs given token with active key using signing method of the key
+func (svc *jwtTokenAuth) sign(token *jwt.Token) (string, error) {
+	svc.mu.RLock()
+	key := svc.keys.activeKey()
+	svc.mu.RUnlock()
+
+	token.Method = key.method
+	token.Header["alg"] = key.method.Alg()
+	token.Header["kid"] = key.kid
+	return token.SignedString(key.privateKey)
 }
 
-func (jwtTokenAuth) TokenAuth() string {
-	return "jwtTokenAuth"
+// parse parses and verifies given token.
+// Time claims are verified with configured clock skew tolerance, so tokens issued by instances with
+// slightly different clock are accepted
+func (svc *jwtTokenAuth) parse(tokenString string) (*jwt.Token, error) {
+	parser := &jwt.Parser{SkipClaimsValidation: true}
+
+	token, err := parser.Parse(tokenString, svc.keyFunc)
+	if err != nil {
+		return token, err
+	}
+
+	c, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		token.Valid = false
+		return token, fmt.Errorf("invalid token")
+	}
+
+	now := time.Now().Unix()
+	skew := int64(svc.clockSkew.Seconds())
+
+	if !c.VerifyExpiresAt(now-skew, false) {
+		token.Valid = false
+		return token, jwt.NewValidationError("token is expired", jwt.ValidationErrorExpired)
+	}
+
+	if !c.VerifyIssuedAt(now+skew, false) {
+		token.Valid = false
+		return token, jwt.NewValidationError("token used before issued", jwt.ValidationErrorIssuedAt)
+	}
+
+	if !c.VerifyNotBefore(now+skew, false) {
+		token.Valid = false
+		return token, jwt.NewValidationError("token is not valid yet", jwt.ValidationErrorNotValidYet)
+	}
+
+	return token, nil
 }
 
-func (svc *jwtTokenAuth) JWKS() *JWKS {
-	return svc.jwks
+// keyFunc returns public key for token verification selected by token `kid` header.
+// Unknown kid causes keys reload, so tokens signed with key rotated by other instance are accepted.
+// Token `alg` header has to match signing method of the selected key
+func (svc *jwtTokenAuth) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	svc.mu.RLock()
+	key, ok := svc.keys.keys[kid]
+	reloadedAt := svc.reloadedAt
+	svc.mu.RUnlock()
+
+	if !ok && svc.keysDir != "" && time.Since(reloadedAt) > keysReloadThrottle {
+		if err := svc.ReloadKeys(); err != nil {
+			svc.logger.Error(err)
+		}
+
+		svc.mu.RLock()
+		key, ok = svc.keys.keys[kid]
+		svc.mu.RUnlock()
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key `%s`", kid)
+	}
+
+	if token.Method == nil || token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("invalid signing algorithm")
+	}
+
+	return key.publicKey, nil
 }
 
+// SessionLifetime returns lifetime of session with given scope.
+// Shortest lifetime configured for roles in scope is used, default refresh token lifetime otherwise
+func (svc *jwtTokenAuth) SessionLifetime(scope ...string) time.Duration {
+	lifetime := time.Duration(0)
+	for _, claim := range scope {
+		if d, ok := svc.lifetimes.roles[claim]; ok && (lifetime == 0 || d < lifetime) {
+			lifetime = d
+		}
+	}
+
+	if lifetime == 0 {
+		return svc.lifetimes.refresh
+	}
+	return lifetime
+}
+
+// AccessTokenLifetime returns lifetime of access token with given scope, it does not exceed session lifetime
+func (svc *jwtTokenAuth) AccessTokenLifetime(scope ...string) time.Duration {
+	if session := svc.SessionLifetime(scope...); session < svc.lifetimes.access {
+		return session
+	}
+	return svc.lifetimes.access
+}
+
+// GenerateAccessToken generates access token with given scope, token does not outlive the session
 func (svc *jwtTokenAuth) GenerateAccessToken(userID uint64, scope ...string) (string, error) {
-	token := jwt.New(jwt.GetSigningMethod("RS256"))
+	token := newToken("JWT")
 	claims := token.Claims.(jwt.MapClaims)
 
-	token.Header["kid"] = "base"
-	token.Header["typ"] = "JWT"
-
 	now := time.Now().UTC().Unix()
 
+	claims["iss"] = svc.issuer
+	claims["aud"] = svc.audience
+	claims["sub"] = strconv.FormatUint(userID, 10)
+	claims["jti"] = uuid.New().String()
 	claims["uid"] = userID
 	claims["scope"] = strings.Join(scope, " ")
-	expIn := time.Minute * time.Duration(16)
-	claims["exp"] = now + int64(expIn.Seconds())
+	claims["exp"] = now + int64(svc.AccessTokenLifetime(scope...).Seconds())
 	claims["iat"] = now
 
-	return token.SignedString(svc.privateKey)
+	return svc.sign(token)
 }
 
 func (svc *jwtTokenAuth) VerifyAccessToken(accessToken string, claims ...string) (uint64, string, error) {
-	token, err := jwt.Parse(accessToken, func(token *jwt.Token) (interface{}, error) {
-		if jwt.GetSigningMethod("RS256") != token.Method {
-			return nil, fmt.Errorf("invalid signing algorithm")
-		}
-		return &svc.privateKey.PublicKey, nil
-	})
-
+	c, err := svc.verifyAccessToken(accessToken)
 	if err != nil {
 		return 0, "", err
 	}
 
+	uid, ok := c["uid"].(float64)
+	if !ok {
+		return 0, "", fmt.Errorf("not a user access token")
+	}
+
+	scope, _ := c["scope"].(string)
+	err = svc.verifyClaims(ParseScope(scope), claims...)
+	return uint64(uid), scope, err
+}
+
+// GenerateClientAccessToken generates access token issued to OAuth client itself, not to any user
+func (svc *jwtTokenAuth) GenerateClientAccessToken(clientID string, scope ...string) (string, error) {
+	token := newToken("JWT")
+	claims := token.Claims.(jwt.MapClaims)
+
+	now := time.Now().UTC().Unix()
+
+	claims["iss"] = svc.issuer
+	claims["aud"] = svc.audience
+	claims["sub"] = clientID
+	claims["jti"] = uuid.New().String()
+	claims["client_id"] = clientID
+	claims["scope"] = strings.Join(scope, " ")
+	claims["exp"] = now + int64(svc.lifetimes.access.Seconds())
+	claims["iat"] = now
+
+	return svc.sign(token)
+}
+
+// VerifyClientAccessToken verifies access token issued to OAuth client and returns client id and token scope
+func (svc *jwtTokenAuth) VerifyClientAccessToken(accessToken string) (string, string, error) {
+	c, err := svc.verifyAccessToken(accessToken)
+	if err != nil {
+		return "", "", err
+	}
+
+	clientID, ok := c["client_id"].(string)
+	if _, isUser := c["uid"]; !ok || isUser {
+		return "", "", fmt.Errorf("not a client access token")
+	}
+
+	scope, _ := c["scope"].(string)
+	return clientID, scope, nil
+}
+
+// verifyAccessToken verifies signature, issuer, audience and revocation of given access token and returns its claims
+func (svc *jwtTokenAuth) verifyAccessToken(accessToken string) (jwt.MapClaims, error) {
+	token, err := svc.parse(accessToken)
+
+	if err != nil {
+		return nil, err
+	}
+
 	if token.Header["typ"] != "JWT" {
-		return 0, "", fmt.Errorf("not an access token")
+		return nil, fmt.Errorf("not an access token")
 	}
 
-	if c, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		userID := uint64(c["uid"].(float64))
-		scope := c["scope"].(string)
-		err = svc.verifyClaims(scope, claims...)
-		return userID, scope, err
+	c, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, fmt.Errorf("invalid token")
 	}
 
-	return 0, "", fmt.Errorf("invalid token")
+	if !c.VerifyIssuer(svc.issuer, true) {
+		return nil, fmt.Errorf("invalid token issuer")
+	}
+
+	if !verifyAudience(c, svc.audience) {
+		return nil, fmt.Errorf("invalid token audience")
+	}
+
+	if jti, ok := c["jti"].(string); ok {
+		denied, err := svc.denyList.IsDenied(jti)
+		if err != nil {
+			return nil, err
+		}
+		if denied {
+			return nil, ErrRevokedToken
+		}
+	}
+
+	return c, nil
 }
 
-func (svc *jwtTokenAuth) GenerateRefreshToken(token string) (string, error) {
-	refreshToken := jwt.New(jwt.GetSigningMethod("RS256"))
-	claims := refreshToken.Claims.(jwt.MapClaims)
+// RevokeAccessToken adds given access token to deny list so it can not be used until it expires
+func (svc *jwtTokenAuth) RevokeAccessToken(accessToken string) error {
+	return svc.revoke(accessToken)
+}
 
-	refreshToken.Header["kid"] = "base"
-	refreshToken.Header["typ"] = "RFRSH"
+// GenerateMFAToken generates short lived token which can only be used to complete MFA login step
+func (svc *jwtTokenAuth) GenerateMFAToken(userID uint64) (string, error) {
+	token := newToken("MFA")
+	claims := token.Claims.(jwt.MapClaims)
 
 	now := time.Now().UTC().Unix()
-	claims["token"] = token
-	expIn := time.Hour * time.Duration(24) * 30 // 30 Days
+
+	claims["jti"] = uuid.New().String()
+	claims["uid"] = userID
+	expIn := time.Minute * time.Duration(5)
 	claims["exp"] = now + int64(expIn.Seconds())
 	claims["iat"] = now
 
-	return refreshToken.SignedString(svc.privateKey)
+	return svc.sign(token)
 }
 
-func (svc *jwtTokenAuth) VerifyRefreshToken(tokenString string) (token string, err error) {
-	refreshToken, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if jwt.GetSigningMethod("RS256") != token.Method {
-			return nil, fmt.Errorf("invalid signing algorithm")
-		}
-
-		return &svc.privateKey.PublicKey, nil
-	})
+// VerifyMFAToken verifies MFA step token and returns user id it was issued for
+func (svc *jwtTokenAuth) VerifyMFAToken(mfaToken string) (uint64, error) {
+	token, err := svc.parse(mfaToken)
 
-	if refreshToken == nil {
-		err = errors.New("invalid token")
-		return
+	if err != nil {
+		return 0, err
 	}
 
-	if refreshToken.Header["typ"] != "RFRSH" {
-		err = fmt.Errorf("not a refresh token")
-		return
+	if token.Header["typ"] != "MFA" {
+		return 0, fmt.Errorf("not an mfa token")
 	}
 
-	if claims, ok := refreshToken.Claims.(jwt.MapClaims); ok && refreshToken.Valid {
-		token = claims["token"].(string)
+	if c, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
+		jti, _ := c["jti"].(string)
+		denied, err := svc.denyList.IsDenied(jti)
+		if err != nil {
+			return 0, err
+		}
+		if denied {
+			return 0, ErrRevokedToken
+		}
+
+		uid, ok := c["uid"].(float64)
+		if !ok {
+			return 0, fmt.Errorf("invalid token")
+		}
+		return uint64(uid), nil
 	}
 
-	return
+	return 0, fmt.Errorf("invalid token")
 }
 
-func (svc *jwtTokenAuth) GenerateAPIKey(userID uint64) (string, error) {
-	token := jwt.New(jwt.GetSigningMethod("RS256"))
-	claims := token.Claims.(jwt.MapClaims)
+// RevokeMFAToken adds given MFA step token to deny list so it can be used only once
+func (svc *jwtTokenAuth) RevokeMFAToken(mfaToken string) error {
+	return svc.revoke(mfaToken)
+}
 
-	token.Header["kid"] = "base"
-	token.Header["typ"] = "APIKEY"
+// GenerateStateToken generates short lived token carrying given values, e.g. state of external login
+func (svc *jwtTokenAuth) GenerateStateToken(values map[string]string, lifetime time.Duration) (string, error) {
+	token := newToken("STATE")
+	claims := token.Claims.(jwt.MapClaims)
 
 	now := time.Now().UTC().Unix()
 
-	claims["uid"] = userID
-	// Yes, api key expires in 100 years
-	expIn := time.Hour * time.Duration(876000)
-	claims["exp"] = now + int64(expIn.Seconds())
+	claims["data"] = values
+	claims["exp"] = now + int64(lifetime.Seconds())
 	claims["iat"] = now
 
-	return token.SignedString(svc.privateKey)
+	return svc.sign(token)
 }
 
-func (svc *jwtTokenAuth) VerifyAPIKey(apiKey string) (uint64, error) {
-	token, err := jwt.Parse(apiKey, func(token *jwt.Token) (interface{}, error) {
-		if jwt.GetSigningMethod("RS256") != token.Method {
-			return nil, fmt.Errorf("invalid signing algorithm")
+// VerifyStateToken verifies state token and returns values it carries
+func (svc *jwtTokenAuth) VerifyStateToken(stateToken string) (map[string]string, error) {
+	token, err := svc.parse(stateToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if token.Header["typ"] != "STATE" {
+		return nil, fmt.Errorf("not a state token")
+	}
+
+	c, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	data, ok := c["data"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	values := make(map[string]string, len(data))
+	for key, value := range data {
+		if values[key], ok = value.(string); !ok {
+			return nil, fmt.Errorf("invalid token")
 		}
-		return &svc.privateKey.PublicKey, nil
-	})
+	}
+	return values, nil
+}
+
+// revoke adds token identifier (jti) to deny list until token expires
+func (svc *jwtTokenAuth) revoke(tokenString string) error {
+	token, err := svc.parse(tokenString)
 
 	if err != nil {
-		return 0, err
+		return err
 	}
 
-	if token.Header["typ"] != "APIKEY" {
-		return 0, fmt.Errorf("not an api key")
+	c, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return fmt.Errorf("invalid token")
 	}
 
-	if c, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		userID := uint64(c["uid"].(float64))
-		return userID, err
+	jti, ok := c["jti"].(string)
+	if !ok {
+		return fmt.Errorf("token can not be revoked")
 	}
 
-	return 0, fmt.Errorf("invalid api key")
+	exp, ok := c["exp"].(float64)
+	if !ok {
+		return fmt.Errorf("invalid token")
+	}
+
+	return svc.denyList.Deny(jti, time.Unix(int64(exp), 0))
 }
 
+// GenerateRefreshToken generates refresh token for given stored token, expiring together with it
+func (svc *jwtTokenAuth) GenerateRefreshToken(token string, expiresAt time.Time) (string, error) {
+	refreshToken := newToken("RFRSH")
+	claims := refreshToken.Claims.(jwt.MapClaims)
+
+	now := time.Now().UTC().Unix()
+	claims["token"] = token
+	claims["exp"] = expiresAt.UTC().Unix()
+	claims["iat"] = now
+
+	return svc.sign(refreshToken)
+}
+
+func (svc *jwtTokenAuth) VerifyRefreshToken(tokenString string) (token string, err error) {
+	refreshToken, err := svc.parse(tokenString)
+
+	if refreshToken == nil {
+		err = errors.New("invalid token")
+		return
+	}
+
+	if refreshToken.Header["typ"] != "RFRSH" {
+		err = fmt.Errorf("not a refresh token")
+		return
+	}
+
+	if claims, ok := refreshToken.Claims.(jwt.MapClaims); ok && refreshToken.Valid {
+		token = claims["token"].(string)
+	}
+
+	return
+}
+
+// AuthorizeRequest authorizes request when access token scope holds at least one of given claims
 func (svc *jwtTokenAuth) AuthorizeRequest(claims ...string) flow.MiddlewareHandlerFunc {
+	if len(claims) == 0 {
+		return svc.Authorize(AllOf())
+	}
+	return svc.Authorize(AnyOf(hasScopes(claims...)...))
+}
+
+// RequirePermissions authorizes request only when access token scope holds all given permissions
+func (svc *jwtTokenAuth) RequirePermissions(permissions ...string) flow.MiddlewareHandlerFunc {
+	return svc.Authorize(AllOf(hasScopes(permissions...)...))
+}
+
+// Authorize authorizes request when access token scope is accepted by given authorizer.
+// Access token is verified once per request, following middlewares reuse id and scope claims from request context
+func (svc *jwtTokenAuth) Authorize(authorizer Authorizer) flow.MiddlewareHandlerFunc {
 	return func(next flow.MiddlewareFunc) flow.MiddlewareFunc {
 		return func(w http.ResponseWriter, r *http.Request) flow.Response {
-			token := svc.findAuthorizationToken(r)
-
-			id, scope, err := svc.VerifyAccessToken(token, claims...)
+			ctx := r.Context()
 
-			if err != nil {
-				if errors.Is(err, ErrForbiddden) {
-					return flow.ResponseError(http.StatusForbidden, err)
+			id, hasID := IDClaimFromContext(ctx)
+			scope, hasScope := ScopeClaimFromContext(ctx)
+			if !hasID || !hasScope {
+				userID, scopeStr, err := svc.VerifyAccessToken(svc.findAuthorizationToken(r))
+				if err != nil {
+					return flow.ResponseError(http.StatusUnauthorized, err)
 				}
-				return flow.ResponseError(http.StatusUnauthorized, err)
+
+				id, scope = userID, ParseScope(scopeStr)
+
+				// add id and scope claims to request
+				ctx = NewIDClaimContext(ctx, id)
+				ctx = NewScopeClaimContext(ctx, scope)
+				r = r.WithContext(ctx)
 			}
 
-			// add id and scope claims to request
-			ctx := r.Context()
-			ctx = NewIDClaimContext(ctx, id)
-			ctx = NewScopeClaimContext(ctx, scope)
-			r = r.WithContext(ctx)
+			if !authorizer(scope) {
+				return flow.ResponseError(http.StatusForbidden, ErrForbiddden)
+			}
 
 			return next(w, r)
 		}
 	}
 }
 
-func (svc *jwtTokenAuth) AuthorizeAPIKey() flow.MiddlewareHandlerFunc {
+// AuthorizeAPIKey authorizes request with API key verified by given verifier.
+// Key owner id and key scopes are added to request context, so following middlewares can check them
+func (svc *jwtTokenAuth) AuthorizeAPIKey(verifier APIKeyVerifier) flow.MiddlewareHandlerFunc {
 	return func(next flow.MiddlewareFunc) flow.MiddlewareFunc {
 		return func(w http.ResponseWriter, r *http.Request) flow.Response {
-			token := svc.findApiKey(r)
-
-			id, err := svc.VerifyAPIKey(token)
-
+			id, scopes, err := verifier.VerifyAPIKey(r.Context(), svc.findApiKey(r))
 			if err != nil {
-				if errors.Is(err, ErrForbiddden) {
-					return flow.ResponseError(http.StatusForbidden, err)
-				}
 				return flow.ResponseError(http.StatusUnauthorized, err)
 			}
 
 			// add id and scope claims to request
 			ctx := r.Context()
 			ctx = NewIDClaimContext(ctx, id)
+			ctx = NewScopeClaimContext(ctx, ParseScope(strings.Join(scopes, " ")))
 			r = r.WithContext(ctx)
 
 			return next(w, r)
@@ -305,11 +706,26 @@ func (svc *jwtTokenAuth) RequestUserID(r *http.Request) (uint64, error) {
 // RequestUserClaims returns authorization claims from request context
 // if claims are not found then unauthorized error is returned
 func (svc *jwtTokenAuth) RequestUserClaims(r *http.Request) ([]string, error) {
-	claims, ok := ScopeClaimFromContext(r.Context())
+	scope, err := svc.RequestScope(r)
+	if err != nil {
+		return []string{}, err
+	}
+	return scope.Values(), nil
+}
+
+// RequestScope returns parsed access token scope from request context
+// if scope is not found then unathorized error is returned
+func (svc *jwtTokenAuth) RequestScope(r *http.Request) (Scope, error) {
+	scope, ok := ScopeClaimFromContext(r.Context())
 	if !ok {
-		return []string{}, ErrUnathorized
+		return nil, ErrUnathorized
 	}
-	return strings.Split(claims, ","), nil
+	return scope, nil
+}
+
+// RequestAccessToken returns access token from request authorization header
+func (svc *jwtTokenAuth) RequestAccessToken(r *http.Request) string {
+	return svc.findAuthorizationToken(r)
 }
 
 func (svc *jwtTokenAuth) findAuthorizationToken(r *http.Request) string {
@@ -327,25 +743,25 @@ func (svc *jwtTokenAuth) findApiKey(r *http.Request) string {
 	return apiKey
 }
 
-func (svc *jwtTokenAuth) verifyClaims(scope interface{}, claims ...string) error {
-	if len(claims) > 0 {
-		// cast scope to string
-		scopeStr, ok := scope.(string)
-		if !ok {
-			return ErrForbiddden
-		}
+// verifyClaims checks that scope holds at least one of given claims
+func (svc *jwtTokenAuth) verifyClaims(scope Scope, claims ...string) error {
+	if len(claims) > 0 && !AnyOf(hasScopes(claims...)...)(scope) {
+		return ErrForbiddden
+	}
+	return nil
+}
 
-		match := false
-		for _, claim := range claims {
-			if strings.Contains(scopeStr, claim) {
-				match = true
-				break
+// verifyAudience checks that token `aud` claim, either single value or list, holds given audience
+func verifyAudience(claims jwt.MapClaims, audience string) bool {
+	switch aud := claims["aud"].(type) {
+	case string:
+		return aud == audience
+	case []interface{}:
+		for _, value := range aud {
+			if value == audience {
+				return true
 			}
 		}
-		if !match {
-			return ErrForbiddden
-		}
 	}
-
-	return nil
+	return false
 }
@@ -19,13 +19,13 @@ func NewIDClaimContext(ctx context.Context, id uint64) context.Context {
 	return context.WithValue(ctx, IDClaimKey{}, id)
 }
 
-// ScopeClaimFromContext returns Scope claims value within given context
-func ScopeClaimFromContext(ctx context.Context) (string, bool) {
-	tx, ok := ctx.Value(ScopeClaimKey{}).(string)
+// ScopeClaimFromContext returns parsed Scope claims value within given context
+func ScopeClaimFromContext(ctx context.Context) (Scope, bool) {
+	tx, ok := ctx.Value(ScopeClaimKey{}).(Scope)
 	return tx, ok
 }
 
-// NewScopeClaimContext creates context with Scope Claims value
-func NewScopeClaimContext(ctx context.Context, scope string) context.Context {
+// NewScopeClaimContext creates context with parsed Scope Claims value
+func NewScopeClaimContext(ctx context.Context, scope Scope) context.Context {
 	return context.WithValue(ctx, ScopeClaimKey{}, scope)
 }
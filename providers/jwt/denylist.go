@@ -0,0 +1,57 @@
+package jwt
+
+import (
+	"sync"
+	"time"
+)
+
+// DenyList stores identifiers (jti) of revoked access tokens until they expire
+type DenyList interface {
+	// Deny adds token identifier to deny list until given expiration time
+	Deny(jti string, expiresAt time.Time) error
+
+	// IsDenied checks if token identifier is in deny list
+	IsDenied(jti string) (bool, error)
+}
+
+// NewMemoryDenyList creates in-memory DenyList implementation.
+// Revoked tokens are not shared between application instances and are lost on restart
+func NewMemoryDenyList() DenyList {
+	return &memoryDenyList{
+		items: map[string]time.Time{},
+	}
+}
+
+type memoryDenyList struct {
+	mu    sync.RWMutex
+	items map[string]time.Time
+}
+
+// Deny adds token identifier to deny list until given expiration time
+func (l *memoryDenyList) Deny(jti string, expiresAt time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// remove entries for tokens which already expired
+	now := time.Now()
+	for k, exp := range l.items {
+		if now.After(exp) {
+			delete(l.items, k)
+		}
+	}
+
+	l.items[jti] = expiresAt
+	return nil
+}
+
+// IsDenied checks if token identifier is in deny list
+func (l *memoryDenyList) IsDenied(jti string) (bool, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	exp, ok := l.items[jti]
+	if !ok {
+		return false, nil
+	}
+	return time.Now().Before(exp), nil
+}
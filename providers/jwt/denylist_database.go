@@ -0,0 +1,50 @@
+package jwt
+
+import (
+	"context"
+	"time"
+
+	"api/providers/db"
+)
+
+// NewDBDenyList creates DenyList implementation backed by `revoked_tokens` table,
+// so tokens revoked on one application instance are rejected by all instances.
+//
+// Deny list does not use request transaction, because revocation has to be kept
+// even when the rest of the request is rolled back
+func NewDBDenyList(store db.Store) DenyList {
+	return &dbDenyList{
+		store: store,
+	}
+}
+
+type dbDenyList struct {
+	store db.Store
+}
+
+// Deny adds token identifier to deny list until given expiration time
+func (l *dbDenyList) Deny(jti string, expiresAt time.Time) error {
+	ctx := context.Background()
+
+	// remove entries for tokens which already expired
+	if _, err := l.store.ExecContext(ctx, "DELETE FROM revoked_tokens WHERE expires_at < ?", time.Now()); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO revoked_tokens (jti, expires_at) VALUES(?, ?)
+		ON DUPLICATE KEY UPDATE expires_at = VALUES(expires_at)`
+
+	_, err := l.store.ExecContext(ctx, query, jti, expiresAt)
+	return err
+}
+
+// IsDenied checks if token identifier is in deny list
+func (l *dbDenyList) IsDenied(jti string) (bool, error) {
+	query := "SELECT COUNT(*) FROM revoked_tokens WHERE jti = ? AND expires_at > ?"
+
+	var count int
+	if err := l.store.QueryRowContext(context.Background(), query, jti, time.Now()).Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
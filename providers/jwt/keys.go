@@ -0,0 +1,488 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"api/providers/config"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+const (
+	// privateKeyExt is extension of private key files, private keys are used for signing and verification
+	privateKeyExt = ".key"
+
+	// publicKeyExt is extension of public key files, public keys of retired keys are used only for verification
+	publicKeyExt = ".pub"
+
+	// activeKeyFile holds kid of the key used for signing
+	activeKeyFile = "active"
+
+	// nextKeyFile holds kid of rotated key followed by its activation time in RFC 3339 format.
+	// Key is published for verification, but it replaces active key only once activation time passes
+	nextKeyFile = "next"
+
+	// baseKid is kid of the key loaded from RSA_PRIVATE_KEY and RSA_PUBLIC_KEY files
+	baseKid = "base"
+
+	// rotatedKeyBits is size of RSA keys generated on rotation
+	rotatedKeyBits = 2048
+
+	// keysReloadThrottle is minimal time between keys reloads caused by unknown kid
+	keysReloadThrottle = 10 * time.Second
+)
+
+// ErrKeysNotRotatable is returned when signing keys are not loaded from keys directory
+var ErrKeysNotRotatable = errors.New("signing keys can be rotated only when loaded from keys directory")
+
+// ErrUnsupportedKey is returned for keys which are not RSA, ECDSA P-256 or Ed25519 keys
+var ErrUnsupportedKey = errors.New("unsupported signing key type")
+
+// signingKey holds key pair identified by kid and signing method determined by key type.
+// Retired keys loaded from public key files do not have private key
+type signingKey struct {
+	kid        string
+	method     jwt.SigningMethod
+	privateKey crypto.PrivateKey
+	publicKey  crypto.PublicKey
+}
+
+// newSigningKey creates signing key for given key pair, private key is nil for retired keys
+func newSigningKey(kid string, privateKey crypto.PrivateKey, publicKey crypto.PublicKey) (*signingKey, error) {
+	method, err := keyMethod(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &signingKey{kid: kid, method: method, privateKey: privateKey, publicKey: publicKey}, nil
+}
+
+// jwk returns public key in JSON Web Key format
+func (k *signingKey) jwk() *JWK {
+	jwk := new(JWK)
+	jwk.Alg = k.method.Alg()
+	jwk.Use = "sig"
+	jwk.Kid = k.kid
+
+	switch key := k.publicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+	case *ecdsa.PublicKey:
+		// coordinates are padded to curve size as required by RFC 7518
+		size := (key.Curve.Params().BitSize + 7) / 8
+		jwk.Kty = "EC"
+		jwk.Crv = key.Curve.Params().Name
+		jwk.X = base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, size)))
+		jwk.Y = base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, size)))
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(key)
+	}
+	return jwk
+}
+
+// ParseJWK returns public key of given JSON Web Key and signing method determined by key type
+func ParseJWK(jwk *JWK) (crypto.PublicKey, jwt.SigningMethod, error) {
+	var publicKey crypto.PublicKey
+
+	switch jwk.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			return nil, nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			return nil, nil, err
+		}
+		publicKey = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	case "EC":
+		if jwk.Crv != elliptic.P256().Params().Name {
+			return nil, nil, ErrUnsupportedKey
+		}
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil {
+			return nil, nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+		if err != nil {
+			return nil, nil, err
+		}
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !key.Curve.IsOnCurve(key.X, key.Y) {
+			return nil, nil, ErrUnsupportedKey
+		}
+		publicKey = key
+	case "OKP":
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil {
+			return nil, nil, err
+		}
+		if jwk.Crv != "Ed25519" || len(x) != ed25519.PublicKeySize {
+			return nil, nil, ErrUnsupportedKey
+		}
+		publicKey = ed25519.PublicKey(x)
+	default:
+		return nil, nil, ErrUnsupportedKey
+	}
+
+	method, err := keyMethod(publicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return publicKey, method, nil
+}
+
+// keySet holds all keys accepted for verification and kid of the key used for signing
+type keySet struct {
+	active string
+	keys   map[string]*signingKey
+}
+
+// activeKey returns key used for signing
+func (s *keySet) activeKey() *signingKey {
+	return s.keys[s.active]
+}
+
+// jwks returns public keys in JSON Web Key Set format, active key is listed first
+func (s *keySet) jwks() *JWKS {
+	kids := make([]string, 0, len(s.keys))
+	for kid := range s.keys {
+		if kid != s.active {
+			kids = append(kids, kid)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(kids)))
+	kids = append([]string{s.active}, kids...)
+
+	jwks := new(JWKS)
+	for _, kid := range kids {
+		jwks.Keys = append(jwks.Keys, s.keys[kid].jwk())
+	}
+	return jwks
+}
+
+// newBaseKeySet creates key set with single key from given PEM encoded key pair.
+// Key type has to match given signing algorithm
+func newBaseKeySet(privatePEM string, publicPEM string, password string, algorithm string) (*keySet, error) {
+	privateKey, err := parsePrivateKey([]byte(privatePEM), password)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, err := parsePublicKey([]byte(publicPEM))
+	if err != nil {
+		return nil, err
+	}
+
+	if key, ok := publicKey.(interface{ Equal(crypto.PublicKey) bool }); !ok || !key.Equal(publicKeyOf(privateKey)) {
+		return nil, errors.New("public key does not match private key")
+	}
+
+	key, err := newSigningKey(baseKid, privateKey, publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if key.method.Alg() != algorithm {
+		return nil, fmt.Errorf("signing key type does not match `%s` algorithm", algorithm)
+	}
+
+	return &keySet{
+		active: baseKid,
+		keys: map[string]*signingKey{
+			baseKid: key,
+		},
+	}, nil
+}
+
+// loadKeySet loads keys from given directory.
+// File name without extension is used as kid, `active` file holds kid of the signing key.
+// Key from `next` file is used for signing instead when its activation time is before given time
+func loadKeySet(dir string, password string, now time.Time) (*keySet, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	set := &keySet{keys: map[string]*signingKey{}}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		ext := filepath.Ext(entry.Name())
+		if ext != privateKeyExt && ext != publicKeyExt {
+			continue
+		}
+		kid := strings.TrimSuffix(entry.Name(), ext)
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		if ext == privateKeyExt {
+			privateKey, err := parsePrivateKey(data, password)
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse private key `%s`; %w", entry.Name(), err)
+			}
+			key, err := newSigningKey(kid, privateKey, publicKeyOf(privateKey))
+			if err != nil {
+				return nil, fmt.Errorf("unable to load private key `%s`; %w", entry.Name(), err)
+			}
+			set.keys[kid] = key
+			continue
+		}
+
+		// private key file takes precedence over public key file with the same kid
+		if _, ok := set.keys[kid]; ok {
+			continue
+		}
+		publicKey, err := parsePublicKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse public key `%s`; %w", entry.Name(), err)
+		}
+		key, err := newSigningKey(kid, nil, publicKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load public key `%s`; %w", entry.Name(), err)
+		}
+		set.keys[kid] = key
+	}
+
+	active, err := os.ReadFile(filepath.Join(dir, activeKeyFile))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read active key file; %w", err)
+	}
+	set.active = strings.TrimSpace(string(active))
+
+	next, activateAt, err := readNextKey(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if next != "" && !now.Before(activateAt) {
+		if key, ok := set.keys[next]; ok && key.privateKey != nil {
+			set.active = next
+		}
+	}
+
+	key, ok := set.keys[set.active]
+	if !ok || key.privateKey == nil {
+		return nil, fmt.Errorf("private key for active kid `%s` not found", set.active)
+	}
+
+	return set, nil
+}
+
+// generateKey creates new key for given signing algorithm in given directory, which becomes active at given time.
+// Until then the key is only published for verification. Previous keys are kept, so tokens signed with them remain valid
+func generateKey(dir string, password string, algorithm string, activateAt time.Time) (string, error) {
+	block, err := generatePrivateKeyBlock(algorithm)
+	if err != nil {
+		return "", err
+	}
+
+	if password != "" {
+		// encrypted PEM is the format supported by RSA_PRIVATE_KEY_PASSWORD
+		block, err = x509.EncryptPEMBlock(rand.Reader, block.Type, block.Bytes, []byte(password), x509.PEMCipherAES256)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	now := time.Now()
+	kid := now.UTC().Format("20060102150405")
+	path := filepath.Join(dir, kid+privateKeyExt)
+	if _, err := os.Stat(path); err == nil {
+		return "", fmt.Errorf("key `%s` already exists", kid)
+	}
+
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0600); err != nil {
+		return "", err
+	}
+
+	// previously rotated key which is already active has to stay active when `next` file is replaced
+	next, nextActivateAt, err := readNextKey(dir)
+	if err != nil {
+		return "", err
+	}
+	if next != "" && !now.Before(nextActivateAt) {
+		if err := writeKeyFile(dir, activeKeyFile, next); err != nil {
+			return "", err
+		}
+	}
+
+	if !activateAt.After(now) {
+		if err := writeKeyFile(dir, activeKeyFile, kid); err != nil {
+			return "", err
+		}
+		if err := os.Remove(filepath.Join(dir, nextKeyFile)); err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+		return kid, nil
+	}
+
+	if err := writeKeyFile(dir, nextKeyFile, kid+" "+activateAt.UTC().Format(time.RFC3339)); err != nil {
+		return "", err
+	}
+
+	return kid, nil
+}
+
+// readNextKey returns kid and activation time from `next` file of given directory, kid is empty when file does not exist
+func readNextKey(dir string) (string, time.Time, error) {
+	data, err := os.ReadFile(filepath.Join(dir, nextKeyFile))
+	if os.IsNotExist(err) {
+		return "", time.Time{}, nil
+	}
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("unable to read next key file; %w", err)
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) != 2 {
+		return "", time.Time{}, errors.New("next key file has to hold kid and activation time")
+	}
+
+	activateAt, err := time.Parse(time.RFC3339, fields[1])
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("unable to parse next key activation time; %w", err)
+	}
+
+	return fields[0], activateAt, nil
+}
+
+// writeKeyFile replaces given file of keys directory atomically, so other instances never read partial content
+func writeKeyFile(dir string, name string, content string) error {
+	tmp := filepath.Join(dir, "."+name)
+	if err := os.WriteFile(tmp, []byte(content+"\n"), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(dir, name))
+}
+
+// generatePrivateKeyBlock generates private key for given signing algorithm and returns it as PEM block
+func generatePrivateKeyBlock(algorithm string) (*pem.Block, error) {
+	switch algorithm {
+	case config.JWTAlgorithmRS256:
+		privateKey, err := rsa.GenerateKey(rand.Reader, rotatedKeyBits)
+		if err != nil {
+			return nil, err
+		}
+		return &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)}, nil
+	case config.JWTAlgorithmES256:
+		privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		der, err := x509.MarshalECPrivateKey(privateKey)
+		if err != nil {
+			return nil, err
+		}
+		return &pem.Block{Type: "EC PRIVATE KEY", Bytes: der}, nil
+	case config.JWTAlgorithmEdDSA:
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+		if err != nil {
+			return nil, err
+		}
+		return &pem.Block{Type: "PRIVATE KEY", Bytes: der}, nil
+	}
+	return nil, fmt.Errorf("unsupported signing algorithm `%s`", algorithm)
+}
+
+// keyMethod returns signing method for given public key type.
+// Method is bound to the key, so token signed with other algorithm is never verified with it
+func keyMethod(publicKey crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		return jwt.SigningMethodRS256, nil
+	case *ecdsa.PublicKey:
+		if key.Curve != elliptic.P256() {
+			return nil, ErrUnsupportedKey
+		}
+		return jwt.SigningMethodES256, nil
+	case ed25519.PublicKey:
+		return SigningMethodEdDSA, nil
+	}
+	return nil, ErrUnsupportedKey
+}
+
+// publicKeyOf returns public key of given private key
+func publicKeyOf(privateKey crypto.PrivateKey) crypto.PublicKey {
+	if key, ok := privateKey.(crypto.Signer); ok {
+		return key.Public()
+	}
+	return nil
+}
+
+// parsePrivateKey parses PEM encoded PKCS1, PKCS8 or SEC1 private key, encrypted when password is given
+func parsePrivateKey(data []byte, password string) (crypto.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, jwt.ErrKeyMustBePEMEncoded
+	}
+
+	der := block.Bytes
+	if password != "" {
+		var err error
+		if der, err = x509.DecryptPEMBlock(block, []byte(password)); err != nil {
+			return nil, err
+		}
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(der); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, ErrUnsupportedKey
+	}
+	return key, nil
+}
+
+// parsePublicKey parses PEM encoded PKIX or PKCS1 public key or certificate
+func parsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, jwt.ErrKeyMustBePEMEncoded
+	}
+
+	if key, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, ErrUnsupportedKey
+	}
+	return cert.PublicKey, nil
+}
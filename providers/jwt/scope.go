@@ -0,0 +1,91 @@
+package jwt
+
+import (
+	"sort"
+	"strings"
+)
+
+// Scope holds parsed access token scope values.
+// Values are matched exactly, so "Admin" is not satisfied by "SuperAdministrator"
+type Scope map[string]struct{}
+
+// ParseScope parses space separated access token scope
+func ParseScope(scope string) Scope {
+	s := Scope{}
+	for _, value := range strings.Fields(scope) {
+		s[value] = struct{}{}
+	}
+	return s
+}
+
+// Has checks if scope holds given value
+func (s Scope) Has(value string) bool {
+	_, ok := s[value]
+	return ok
+}
+
+// Values returns sorted scope values
+func (s Scope) Values() []string {
+	values := make([]string, 0, len(s))
+	for value := range s {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	return values
+}
+
+// String returns space separated scope values as they are stored in access token
+func (s Scope) String() string {
+	return strings.Join(s.Values(), " ")
+}
+
+// Authorizer decides whether request with given access token scope is allowed
+type Authorizer func(scope Scope) bool
+
+// HasScope allows scope which holds given value
+func HasScope(value string) Authorizer {
+	return func(scope Scope) bool {
+		return scope.Has(value)
+	}
+}
+
+// AllOf allows scope accepted by all given authorizers
+func AllOf(authorizers ...Authorizer) Authorizer {
+	return func(scope Scope) bool {
+		for _, authorizer := range authorizers {
+			if !authorizer(scope) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// AnyOf allows scope accepted by at least one of given authorizers.
+// AnyOf without authorizers does not allow any scope
+func AnyOf(authorizers ...Authorizer) Authorizer {
+	return func(scope Scope) bool {
+		for _, authorizer := range authorizers {
+			if authorizer(scope) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not allows scope rejected by given authorizer
+func Not(authorizer Authorizer) Authorizer {
+	return func(scope Scope) bool {
+		return !authorizer(scope)
+	}
+}
+
+// hasScopes converts scope values to HasScope authorizers
+func hasScopes(values ...string) []Authorizer {
+	authorizers := make([]Authorizer, 0, len(values))
+	for _, value := range values {
+		authorizers = append(authorizers, HasScope(value))
+	}
+	return authorizers
+}
@@ -0,0 +1,132 @@
+package jwt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseScope(t *testing.T) {
+	tests := []struct {
+		name  string
+		scope string
+		want  []string
+	}{
+		{"empty", "", []string{}},
+		{"blank", "   \t ", []string{}},
+		{"single", "Admin", []string{"Admin"}},
+		{"space separated", "User Admin", []string{"Admin", "User"}},
+		{"repeated separators", "  User   Admin\t\tusers:read \n", []string{"Admin", "User", "users:read"}},
+		{"repeated values", "Admin User Admin", []string{"Admin", "User"}},
+		{"comma is not separator", "Admin,User", []string{"Admin,User"}},
+		{"comma and space", "Admin, User", []string{"Admin,", "User"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseScope(tt.scope).Values(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseScope(%q) = %v, want %v", tt.scope, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasScope(t *testing.T) {
+	tests := []struct {
+		name  string
+		scope string
+		value string
+		want  bool
+	}{
+		{"exact match", "Authorized Admin", "Admin", true},
+		{"prefix of longer value", "SuperAdministrator", "Admin", false},
+		{"substring of longer value", "SuperAdmin", "Admin", false},
+		{"longer value than granted", "Admin", "SuperAdministrator", false},
+		{"case sensitive", "admin", "Admin", false},
+		{"comma separated value", "User,Admin", "Admin", false},
+		{"repeated separators", "User   Admin", "Admin", true},
+		{"empty scope", "", "Admin", false},
+		{"empty value", "Admin", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HasScope(tt.value)(ParseScope(tt.scope)); got != tt.want {
+				t.Errorf("HasScope(%q) on %q = %v, want %v", tt.value, tt.scope, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllOf(t *testing.T) {
+	tests := []struct {
+		name        string
+		scope       string
+		authorizers []Authorizer
+		want        bool
+	}{
+		{"no authorizers", "", nil, true},
+		{"no authorizers with scope", "Admin", nil, true},
+		{"all held", "Authorized Admin users:read", hasScopes("Admin", "users:read"), true},
+		{"one missing", "Authorized Admin", hasScopes("Admin", "users:read"), false},
+		{"similar value", "Authorized SuperAdministrator", hasScopes("Authorized", "Admin"), false},
+		{"empty scope", "", hasScopes("Admin"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AllOf(tt.authorizers...)(ParseScope(tt.scope)); got != tt.want {
+				t.Errorf("AllOf on %q = %v, want %v", tt.scope, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnyOf(t *testing.T) {
+	tests := []struct {
+		name        string
+		scope       string
+		authorizers []Authorizer
+		want        bool
+	}{
+		{"no authorizers", "", nil, false},
+		{"no authorizers with scope", "Admin", nil, false},
+		{"one held", "Authorized User", hasScopes("Admin", "User"), true},
+		{"none held", "Authorized Support", hasScopes("Admin", "User"), false},
+		{"similar value", "SuperAdministrator", hasScopes("Admin"), false},
+		{"empty scope", "", hasScopes("Admin"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AnyOf(tt.authorizers...)(ParseScope(tt.scope)); got != tt.want {
+				t.Errorf("AnyOf on %q = %v, want %v", tt.scope, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNot(t *testing.T) {
+	tests := []struct {
+		name       string
+		scope      string
+		authorizer Authorizer
+		want       bool
+	}{
+		{"held value", "Unconfirmed User", HasScope("Unconfirmed"), false},
+		{"missing value", "Authorized User", HasScope("Unconfirmed"), true},
+		{"similar value", "SuperAdministrator", HasScope("Admin"), true},
+		{"empty scope", "", HasScope("Admin"), true},
+		{"empty AnyOf", "Admin", AnyOf(), true},
+		{"empty AllOf", "Admin", AllOf(), false},
+		{"combined", "Authorized Admin", AllOf(HasScope("Admin"), Not(HasScope("Unconfirmed"))), false},
+		{"double negation", "Admin", Not(HasScope("Admin")), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Not(tt.authorizer)(ParseScope(tt.scope)); got != tt.want {
+				t.Errorf("Not on %q = %v, want %v", tt.scope, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package lockout
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"api/providers/db"
+)
+
+// NewDBStore creates Store implementation backed by `login_attempts` table.
+//
+// Store does not use request transaction, because failed login requests are rolled back
+// and their attempts have to be recorded anyway
+func NewDBStore(store db.Store) Store {
+	return &dbStore{
+		store: store,
+	}
+}
+
+type dbStore struct {
+	store db.Store
+}
+
+// Get returns attempts for given key or nil if there are none
+func (s *dbStore) Get(ctx context.Context, key string) (*Attempts, error) {
+	query := "SELECT attempt_key, failures, locked_until, last_failure_at FROM login_attempts WHERE attempt_key = ?"
+
+	// create empty model object
+	model := new(Attempts)
+	var lastFailureAt sql.NullTime
+
+	// execute query statement and scan row to model
+	err := s.store.QueryRowContext(ctx, query, key).Scan(&model.Key, &model.Failures, &model.LockedUntil, &lastFailureAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	model.LastFailureAt = lastFailureAt.Time
+	return model, nil
+}
+
+// Fail increments failures counter for given key and returns updated attempts.
+// Counter starts over when last failure happened before resetBefore
+func (s *dbStore) Fail(ctx context.Context, key string, now time.Time, resetBefore time.Time) (*Attempts, error) {
+	query := `INSERT INTO login_attempts (attempt_key, failures, last_failure_at) VALUES(?, 1, ?)
+		ON DUPLICATE KEY UPDATE failures = IF(last_failure_at < ?, 1, failures + 1), last_failure_at = VALUES(last_failure_at)`
+
+	if _, err := s.store.ExecContext(ctx, query, key, now, resetBefore); err != nil {
+		return nil, err
+	}
+
+	return s.Get(ctx, key)
+}
+
+// Lock locks given key until given time
+func (s *dbStore) Lock(ctx context.Context, key string, until time.Time) error {
+	query := "UPDATE login_attempts SET locked_until = ? WHERE attempt_key = ?"
+	_, err := s.store.ExecContext(ctx, query, until, key)
+	return err
+}
+
+// Delete removes attempts for given key
+func (s *dbStore) Delete(ctx context.Context, key string) error {
+	query := "DELETE FROM login_attempts WHERE attempt_key = ?"
+	_, err := s.store.ExecContext(ctx, query, key)
+	return err
+}
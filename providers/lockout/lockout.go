@@ -0,0 +1,200 @@
+package lockout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"api/providers/config"
+	"api/providers/db"
+	"api/providers/log"
+)
+
+// resetAfter is period without failures after which counters start over
+const resetAfter = time.Hour * 24
+
+// ErrLocked is returned when user or client is temporarily locked out
+var ErrLocked = errors.New("too many failed login attempts")
+
+// LockedError is returned when user or client is temporarily locked out
+type LockedError struct {
+	RetryAfter time.Duration
+}
+
+// Error interface implementation
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrLocked.Error(), e.RetryAfter)
+}
+
+// Unwrap implements error unwrapping
+func (e *LockedError) Unwrap() error {
+	return ErrLocked
+}
+
+// RetryAfterSeconds returns value for `Retry-After` response header
+func (e *LockedError) RetryAfterSeconds() string {
+	return strconv.Itoa(int(math.Ceil(e.RetryAfter.Seconds())))
+}
+
+// Lockout tracks failed login attempts per user and per client IP
+// and temporarily locks them out with exponential backoff
+type Lockout interface {
+	// Lockout returns interface implementation signature
+	Lockout() string
+
+	// Check returns LockedError when given user or client IP is locked out.
+	// Zero userID or empty clientIP are not checked
+	Check(ctx context.Context, userID uint64, clientIP string) error
+
+	// RegisterFailure records failed login attempt for given user and client IP.
+	// Zero userID is used when user is not known
+	RegisterFailure(ctx context.Context, userID uint64, clientIP string) error
+
+	// RegisterSuccess resets failed login attempts of given user
+	RegisterSuccess(ctx context.Context, userID uint64) error
+
+	// Unlock removes lock and resets failed login attempts of given user
+	Unlock(ctx context.Context, userID uint64) error
+}
+
+// New creates Lockout implementation with store based on application configuration
+func New(cfg config.AppConfig, store db.Store, logger log.Logger) Lockout {
+	var s Store
+	switch cfg.LockoutStore() {
+	case "db":
+		s = NewDBStore(store)
+	default:
+		s = NewMemoryStore()
+	}
+
+	return NewLockout(s, cfg.LockoutThreshold(), cfg.LockoutIPThreshold(), cfg.LockoutDuration(), cfg.LockoutMaxDuration())
+}
+
+// NewLockout creates Lockout implementation.
+// Key is locked for duration after reaching threshold failures, and lock duration doubles
+// with every next failure up to maxDuration
+func NewLockout(store Store, userThreshold int, ipThreshold int, duration time.Duration, maxDuration time.Duration) Lockout {
+	return &lockout{
+		store:         store,
+		userThreshold: userThreshold,
+		ipThreshold:   ipThreshold,
+		duration:      duration,
+		maxDuration:   maxDuration,
+		now:           time.Now,
+	}
+}
+
+type lockout struct {
+	store         Store
+	userThreshold int
+	ipThreshold   int
+	duration      time.Duration
+	maxDuration   time.Duration
+	now           func() time.Time
+}
+
+// Lockout returns interface implementation signature
+func (lockout) Lockout() string {
+	return "lockout"
+}
+
+// Check returns LockedError when given user or client IP is locked out.
+// Zero userID or empty clientIP are not checked
+func (l *lockout) Check(ctx context.Context, userID uint64, clientIP string) error {
+	now := l.now()
+
+	var retryAfter time.Duration
+	for _, key := range l.keys(userID, clientIP) {
+		a, err := l.store.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+
+		if a != nil && a.IsLocked(now) {
+			if d := a.LockedUntil.Sub(now); d > retryAfter {
+				retryAfter = d
+			}
+		}
+	}
+
+	if retryAfter > 0 {
+		return &LockedError{RetryAfter: retryAfter}
+	}
+
+	return nil
+}
+
+// RegisterFailure records failed login attempt for given user and client IP.
+// Zero userID is used when user is not known
+func (l *lockout) RegisterFailure(ctx context.Context, userID uint64, clientIP string) error {
+	now := l.now()
+
+	for _, key := range l.keys(userID, clientIP) {
+		a, err := l.store.Fail(ctx, key, now, now.Add(-resetAfter))
+		if err != nil {
+			return err
+		}
+
+		threshold := l.userThreshold
+		if key == ipKey(clientIP) {
+			threshold = l.ipThreshold
+		}
+
+		if d := l.lockDuration(a.Failures, threshold); d > 0 {
+			if err := l.store.Lock(ctx, key, now.Add(d)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// RegisterSuccess resets failed login attempts of given user
+func (l *lockout) RegisterSuccess(ctx context.Context, userID uint64) error {
+	return l.store.Delete(ctx, userKey(userID))
+}
+
+// Unlock removes lock and resets failed login attempts of given user
+func (l *lockout) Unlock(ctx context.Context, userID uint64) error {
+	return l.store.Delete(ctx, userKey(userID))
+}
+
+// lockDuration returns lock duration for given number of failures
+func (l *lockout) lockDuration(failures int, threshold int) time.Duration {
+	if threshold <= 0 || failures < threshold {
+		return 0
+	}
+
+	d := l.duration
+	for i := threshold; i < failures && d < l.maxDuration; i++ {
+		d *= 2
+	}
+
+	if d > l.maxDuration {
+		d = l.maxDuration
+	}
+	return d
+}
+
+func (l *lockout) keys(userID uint64, clientIP string) []string {
+	keys := []string{}
+	if userID > 0 {
+		keys = append(keys, userKey(userID))
+	}
+	if clientIP != "" {
+		keys = append(keys, ipKey(clientIP))
+	}
+	return keys
+}
+
+func userKey(userID uint64) string {
+	return fmt.Sprintf("user:%d", userID)
+}
+
+func ipKey(clientIP string) string {
+	return "ip:" + clientIP
+}
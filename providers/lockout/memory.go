@@ -0,0 +1,89 @@
+package lockout
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// NewMemoryStore creates in-memory Store implementation.
+// Counters are not shared between application instances and are lost on restart
+func NewMemoryStore() Store {
+	return &memoryStore{
+		items: map[string]*Attempts{},
+	}
+}
+
+type memoryStore struct {
+	mu    sync.Mutex
+	items map[string]*Attempts
+}
+
+// Get returns attempts for given key or nil if there are none
+func (s *memoryStore) Get(ctx context.Context, key string) (*Attempts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.items[key]
+	if !ok {
+		return nil, nil
+	}
+
+	// return copy so callers can not modify stored counter
+	c := *a
+	return &c, nil
+}
+
+// Fail increments failures counter for given key and returns updated attempts.
+// Counter starts over when last failure happened before resetBefore
+func (s *memoryStore) Fail(ctx context.Context, key string, now time.Time, resetBefore time.Time) (*Attempts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// remove stale counters
+	for k, a := range s.items {
+		if a.LastFailureAt.Before(resetBefore) && !a.IsLocked(now) {
+			delete(s.items, k)
+		}
+	}
+
+	a, ok := s.items[key]
+	if !ok {
+		a = &Attempts{Key: key}
+		s.items[key] = a
+	}
+
+	if a.LastFailureAt.Before(resetBefore) {
+		a.Failures = 0
+	}
+
+	a.Failures++
+	a.LastFailureAt = now
+
+	c := *a
+	return &c, nil
+}
+
+// Lock locks given key until given time
+func (s *memoryStore) Lock(ctx context.Context, key string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.items[key]
+	if !ok {
+		a = &Attempts{Key: key}
+		s.items[key] = a
+	}
+
+	a.LockedUntil = &until
+	return nil
+}
+
+// Delete removes attempts for given key
+func (s *memoryStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.items, key)
+	return nil
+}
@@ -0,0 +1,35 @@
+package lockout
+
+import (
+	"context"
+	"time"
+)
+
+// Attempts holds failed login attempts counter for single key (user or client IP)
+type Attempts struct {
+	Key           string
+	Failures      int
+	LockedUntil   *time.Time
+	LastFailureAt time.Time
+}
+
+// IsLocked checks if key is locked at given time
+func (a *Attempts) IsLocked(now time.Time) bool {
+	return a.LockedUntil != nil && now.Before(*a.LockedUntil)
+}
+
+// Store persists failed login attempts counters
+type Store interface {
+	// Get returns attempts for given key or nil if there are none
+	Get(ctx context.Context, key string) (*Attempts, error)
+
+	// Fail increments failures counter for given key and returns updated attempts.
+	// Counter starts over when last failure happened before resetBefore
+	Fail(ctx context.Context, key string, now time.Time, resetBefore time.Time) (*Attempts, error)
+
+	// Lock locks given key until given time
+	Lock(ctx context.Context, key string, until time.Time) error
+
+	// Delete removes attempts for given key
+	Delete(ctx context.Context, key string) error
+}
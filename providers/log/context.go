@@ -4,6 +4,8 @@ import "context"
 
 type loggerKey struct{}
 
+type requestIDKey struct{}
+
 // FromContext extracts logger from context
 func FromContext(ctx context.Context) (Logger, bool) {
 	l, ok := ctx.Value(loggerKey{}).(Logger)
@@ -14,3 +16,14 @@ func FromContext(ctx context.Context) (Logger, bool) {
 func NewContext(ctx context.Context, l Logger) context.Context {
 	return context.WithValue(ctx, loggerKey{}, l)
 }
+
+// RequestIDFromContext extracts request id from context
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok
+}
+
+// NewRequestIDContext creates context with request id
+func NewRequestIDContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
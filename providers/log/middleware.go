@@ -49,6 +49,7 @@ func MiddlewareWithFields(logger Logger, f Fields, ignore ...string) flow.Middle
 			// put request logger to request context for later use
 			ctx := r.Context()
 			ctx = NewContext(ctx, rl)
+			ctx = NewRequestIDContext(ctx, requestID)
 
 			r = r.WithContext(ctx)
 
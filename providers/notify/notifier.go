@@ -0,0 +1,56 @@
+package notify
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"api/providers/config"
+	"api/providers/log"
+)
+
+const (
+	// KindPasswordReset identifies password reset messages
+	KindPasswordReset = "password_reset"
+
+	// KindEmailConfirmation identifies email confirmation messages
+	KindEmailConfirmation = "email_confirmation"
+
+	// KindInvitation identifies user invitation messages
+	KindInvitation = "invitation"
+
+	// KindAccountDeletion identifies account deletion confirmation messages
+	KindAccountDeletion = "account_deletion"
+)
+
+// Message holds notification data delivered to user
+type Message struct {
+	Kind      string            `json:"kind"`
+	To        string            `json:"to"`
+	Subject   string            `json:"subject"`
+	Body      string            `json:"body"`
+	Data      map[string]string `json:"data"`
+	CreatedAt time.Time         `json:"createdAt"`
+}
+
+// Notifier interface
+type Notifier interface {
+	// Notifier returns interface implementation signature
+	Notifier() string
+
+	// Send delivers given message to its recipient
+	Send(ctx context.Context, msg *Message) error
+}
+
+// New creates Notifier implementation based on application configuration
+func New(cfg config.AppConfig, logger log.Logger) Notifier {
+	switch cfg.Notifier() {
+	case "smtp":
+		return NewSMTP(cfg.SMTPAddr(), cfg.SMTPFrom(), cfg.SMTPUser(), cfg.SMTPPassword())
+	default:
+		if err := os.MkdirAll(cfg.OutboxDir(), 0755); err != nil {
+			logger.Fatal(err)
+		}
+		return NewOutbox(cfg.OutboxDir())
+	}
+}
@@ -0,0 +1,46 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// NewOutbox creates Notifier implementation which writes messages
+// as JSON files to given directory. It is intended for development and tests
+func NewOutbox(dir string) Notifier {
+	return &outbox{
+		dir: dir,
+	}
+}
+
+type outbox struct {
+	dir string
+}
+
+// Notifier returns interface implementation signature
+func (outbox) Notifier() string {
+	return "outbox"
+}
+
+// Send writes given message to outbox directory
+func (n *outbox) Send(ctx context.Context, msg *Message) error {
+	if msg.CreatedAt.IsZero() {
+		msg.CreatedAt = time.Now()
+	}
+
+	data, err := json.MarshalIndent(msg, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// replace characters which are not safe for file names
+	to := strings.NewReplacer("@", "_at_", "/", "_", "\\", "_").Replace(msg.To)
+	name := fmt.Sprintf("%d_%s_%s.json", msg.CreatedAt.UnixNano(), msg.Kind, to)
+
+	return os.WriteFile(filepath.Join(n.dir, name), data, 0644)
+}
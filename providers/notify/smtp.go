@@ -0,0 +1,53 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+// NewSMTP creates Notifier implementation which delivers messages using SMTP server
+func NewSMTP(addr, from, user, password string) Notifier {
+	return &smtpNotifier{
+		addr:     addr,
+		from:     from,
+		user:     user,
+		password: password,
+	}
+}
+
+type smtpNotifier struct {
+	addr     string
+	from     string
+	user     string
+	password string
+}
+
+// Notifier returns interface implementation signature
+func (smtpNotifier) Notifier() string {
+	return "smtp"
+}
+
+// Send delivers given message as plain text email
+func (n *smtpNotifier) Send(ctx context.Context, msg *Message) error {
+	var auth smtp.Auth
+	if n.user != "" {
+		host, _, err := net.SplitHostPort(n.addr)
+		if err != nil {
+			return err
+		}
+		auth = smtp.PlainAuth("", n.user, n.password, host)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", n.from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n\r\n")
+	b.WriteString(msg.Body)
+
+	return smtp.SendMail(n.addr, auth, n.from, []string{msg.To}, []byte(b.String()))
+}
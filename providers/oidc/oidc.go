@@ -0,0 +1,197 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"api/providers/config"
+	"api/providers/log"
+)
+
+// callbackPath is path of the endpoint identity providers redirect users back to, `%s` is provider name
+const callbackPath = "/account/oauth/%s/callback"
+
+// requestTimeout limits duration of requests to identity providers
+const requestTimeout = 10 * time.Second
+
+var (
+	// ErrUnknownProvider is returned when identity provider with given name is not configured
+	ErrUnknownProvider = errors.New("unknown identity provider")
+
+	// ErrDiscovery is returned when identity provider metadata or signing keys could not be retrieved
+	ErrDiscovery = errors.New("unable to discover identity provider")
+
+	// ErrExchange is returned when authorization code could not be exchanged for tokens
+	ErrExchange = errors.New("unable to exchange authorization code")
+
+	// ErrInvalidIDToken is returned when ID token issued by identity provider is not valid
+	ErrInvalidIDToken = errors.New("invalid id token")
+)
+
+// Identity holds user identity asserted by external identity provider
+type Identity struct {
+	// Provider is name of identity provider
+	Provider string
+
+	// Subject is user identifier unique within identity provider
+	Subject string
+
+	Email         string
+	EmailVerified bool
+	FirstName     string
+	LastName      string
+}
+
+// Federation authenticates users with external OpenID Connect identity providers
+// using authorization code flow with PKCE
+type Federation interface {
+	// Federation returns interface implementation signature
+	Federation() string
+
+	// Providers returns names of configured identity providers
+	Providers() []string
+
+	// AuthorizationURL returns URL of identity provider authorization endpoint user is redirected to.
+	// Code challenge is derived from given code verifier using S256 method
+	AuthorizationURL(ctx context.Context, provider string, state string, nonce string, codeVerifier string) (string, error)
+
+	// Exchange exchanges authorization code for ID token and returns identity it asserts.
+	// ID token has to be issued for given nonce
+	Exchange(ctx context.Context, provider string, code string, codeVerifier string, nonce string) (*Identity, error)
+}
+
+// New creates Federation implementation with identity providers from application configuration
+func New(cfg config.AppConfig, logger log.Logger) Federation {
+	return NewFederation(
+		cfg.IdentityProviders(),
+		cfg.IdentityProviderCallbackURL(),
+		cfg.JWTClockSkew(),
+		&http.Client{Timeout: requestTimeout},
+		logger,
+	)
+}
+
+// NewFederation creates Federation implementation.
+// Redirect URI registered at each provider is callbackURL followed by `/account/oauth/{provider}/callback`.
+// Provider metadata is discovered on first use, so API starts even when provider is not reachable
+func NewFederation(providers []*config.IdentityProvider, callbackURL string, clockSkew time.Duration, client *http.Client, logger log.Logger) Federation {
+	f := &federation{
+		providers: make(map[string]*provider, len(providers)),
+		client:    client,
+		clockSkew: clockSkew,
+		logger:    logger,
+	}
+
+	for _, p := range providers {
+		f.names = append(f.names, p.Name)
+		f.providers[p.Name] = &provider{
+			cfg:         p,
+			redirectURL: strings.TrimSuffix(callbackURL, "/") + fmt.Sprintf(callbackPath, url.PathEscape(p.Name)),
+		}
+	}
+
+	return f
+}
+
+type federation struct {
+	names     []string
+	providers map[string]*provider
+	client    *http.Client
+	clockSkew time.Duration
+	logger    log.Logger
+}
+
+// Federation returns interface implementation signature
+func (*federation) Federation() string {
+	return "federation"
+}
+
+// Providers returns names of configured identity providers
+func (f *federation) Providers() []string {
+	return f.names
+}
+
+// AuthorizationURL returns URL of identity provider authorization endpoint user is redirected to
+func (f *federation) AuthorizationURL(ctx context.Context, name string, state string, nonce string, codeVerifier string) (string, error) {
+	p, ok := f.providers[name]
+	if !ok {
+		return "", ErrUnknownProvider
+	}
+
+	m, err := f.metadata(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	authURL, err := url.Parse(m.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrDiscovery, err)
+	}
+
+	// endpoint may already hold query parameters, which have to be kept
+	q := authURL.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", p.cfg.ClientID)
+	q.Set("redirect_uri", p.redirectURL)
+	q.Set("scope", strings.Join(p.cfg.Scopes, " "))
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", codeChallenge(codeVerifier))
+	q.Set("code_challenge_method", "S256")
+	authURL.RawQuery = q.Encode()
+
+	return authURL.String(), nil
+}
+
+// Exchange exchanges authorization code for ID token and returns identity it asserts
+func (f *federation) Exchange(ctx context.Context, name string, code string, codeVerifier string, nonce string) (*Identity, error) {
+	p, ok := f.providers[name]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+
+	m, err := f.metadata(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	idToken, err := f.exchangeCode(ctx, p, m, code, codeVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := f.verifyIDToken(ctx, p, m, idToken, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	identity := &Identity{Provider: p.cfg.Name}
+	identity.Subject, _ = claims["sub"].(string)
+	identity.Email, _ = claims["email"].(string)
+	identity.FirstName, _ = claims["given_name"].(string)
+	identity.LastName, _ = claims["family_name"].(string)
+
+	// some providers send boolean claims as strings
+	switch verified := claims["email_verified"].(type) {
+	case bool:
+		identity.EmailVerified = verified
+	case string:
+		identity.EmailVerified = verified == "true"
+	}
+
+	if identity.FirstName == "" && identity.LastName == "" {
+		name, _ := claims["name"].(string)
+		if parts := strings.SplitN(strings.TrimSpace(name), " ", 2); len(parts) == 2 {
+			identity.FirstName, identity.LastName = parts[0], parts[1]
+		} else {
+			identity.FirstName = parts[0]
+		}
+	}
+
+	return identity, nil
+}
@@ -0,0 +1,434 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"api/providers/config"
+	"api/providers/jwt"
+	"api/providers/log"
+
+	jwtgo "github.com/dgrijalva/jwt-go"
+)
+
+const (
+	testClientID     = "client-1"
+	testClientSecret = "s&cret"
+	testCallbackURL  = "https://api.example.com"
+	testVerifier     = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+)
+
+// authorization holds code challenge and nonce of authorization request code was issued for
+type authorization struct {
+	challenge string
+	nonce     string
+}
+
+// fakeIdP is OpenID Connect provider serving discovery, JWKS and token endpoint.
+// Token endpoint issues ID token built from claims returned by claims func
+type fakeIdP struct {
+	*httptest.Server
+
+	mu           sync.Mutex
+	keys         map[string]crypto.Signer
+	signingKid   string
+	signingAlg   jwtgo.SigningMethod
+	codes        map[string]*authorization
+	claims       func(nonce string) jwtgo.MapClaims
+	jwksRequests int
+}
+
+func newFakeIdP(t *testing.T) *fakeIdP {
+	idp := &fakeIdP{
+		keys:  map[string]crypto.Signer{},
+		codes: map[string]*authorization{},
+	}
+	idp.addKey(t, "k1")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", idp.discovery)
+	mux.HandleFunc("/jwks", idp.jwks)
+	mux.HandleFunc("/token", idp.token)
+	idp.Server = httptest.NewServer(mux)
+
+	idp.claims = func(nonce string) jwtgo.MapClaims {
+		now := time.Now().Unix()
+		return jwtgo.MapClaims{
+			"iss":            idp.URL,
+			"aud":            testClientID,
+			"sub":            "user-1",
+			"email":          "jane@example.com",
+			"email_verified": true,
+			"name":           "Jane Doe",
+			"nonce":          nonce,
+			"iat":            now,
+			"exp":            now + 60,
+		}
+	}
+
+	t.Cleanup(idp.Close)
+	return idp
+}
+
+// addKey generates ECDSA P-256 key with given kid and makes it the signing key
+func (idp *fakeIdP) addKey(t *testing.T, kid string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+	idp.keys[kid] = key
+	idp.signingKid = kid
+	idp.signingAlg = jwtgo.SigningMethodES256
+}
+
+// authorize simulates user approval at provider and returns authorization code for given authorization URL
+func (idp *fakeIdP) authorize(t *testing.T, authURL string) (string, string) {
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code := "code-" + u.Query().Get("state")
+	idp.mu.Lock()
+	idp.codes[code] = &authorization{challenge: u.Query().Get("code_challenge"), nonce: u.Query().Get("nonce")}
+	idp.mu.Unlock()
+
+	return code, u.Query().Get("nonce")
+}
+
+func (idp *fakeIdP) discovery(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(map[string]string{
+		"issuer":                 idp.URL,
+		"authorization_endpoint": idp.URL + "/authorize?prompt=login",
+		"token_endpoint":         idp.URL + "/token",
+		"jwks_uri":               idp.URL + "/jwks",
+	})
+}
+
+func (idp *fakeIdP) jwks(w http.ResponseWriter, r *http.Request) {
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+	idp.jwksRequests++
+
+	jwks := new(jwt.JWKS)
+	for kid, key := range idp.keys {
+		switch pub := key.Public().(type) {
+		case *ecdsa.PublicKey:
+			jwks.Keys = append(jwks.Keys, &jwt.JWK{
+				Kty: "EC",
+				Crv: "P-256",
+				Kid: kid,
+				Use: "sig",
+				X:   base64.RawURLEncoding.EncodeToString(pub.X.FillBytes(make([]byte, 32))),
+				Y:   base64.RawURLEncoding.EncodeToString(pub.Y.FillBytes(make([]byte, 32))),
+			})
+		case *rsa.PublicKey:
+			jwks.Keys = append(jwks.Keys, &jwt.JWK{
+				Kty: "RSA",
+				Kid: kid,
+				Use: "sig",
+				N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		}
+	}
+	json.NewEncoder(w).Encode(jwks)
+}
+
+func (idp *fakeIdP) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// client_secret_basic credentials are form encoded
+	user, password, ok := r.BasicAuth()
+	if !ok || user != testClientID || password != url.QueryEscape(testClientSecret) {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	// authorization code can be used only once
+	idp.mu.Lock()
+	auth, ok := idp.codes[r.PostForm.Get("code")]
+	delete(idp.codes, r.PostForm.Get("code"))
+	idp.mu.Unlock()
+
+	if !ok || auth.challenge != codeChallenge(r.PostForm.Get("code_verifier")) ||
+		r.PostForm.Get("redirect_uri") != testCallbackURL+"/account/oauth/corp/callback" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	idp.mu.Lock()
+	token := jwtgo.NewWithClaims(idp.signingAlg, idp.claims(auth.nonce))
+	token.Header["kid"] = idp.signingKid
+	var key interface{} = idp.keys[idp.signingKid]
+	if idp.signingAlg == jwtgo.SigningMethodHS256 {
+		key = []byte(testClientSecret)
+	}
+	idp.mu.Unlock()
+
+	idToken, err := token.SignedString(key)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{"access_token": "at", "token_type": "Bearer", "id_token": idToken})
+}
+
+func newTestFederation(idp *fakeIdP) Federation {
+	providers := []*config.IdentityProvider{{
+		Name:         "corp",
+		Issuer:       idp.URL,
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		Scopes:       []string{"openid", "email", "profile"},
+	}}
+	return NewFederation(providers, testCallbackURL+"/", time.Second, idp.Client(), log.New())
+}
+
+// login runs authorization code flow against fake provider, nonce is replaced when exchangeNonce is not empty
+func login(t *testing.T, idp *fakeIdP, f Federation, state string, exchangeNonce string) (*Identity, error) {
+	authURL, err := f.AuthorizationURL(context.Background(), "corp", state, "nonce-"+state, testVerifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code, nonce := idp.authorize(t, authURL)
+	if exchangeNonce != "" {
+		nonce = exchangeNonce
+	}
+	return f.Exchange(context.Background(), "corp", code, testVerifier, nonce)
+}
+
+func TestAuthorizationURL(t *testing.T) {
+	idp := newFakeIdP(t)
+	f := newTestFederation(idp)
+
+	authURL, err := f.AuthorizationURL(context.Background(), "corp", "st", "no", testVerifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"prompt":                "login",
+		"response_type":         "code",
+		"client_id":             testClientID,
+		"redirect_uri":          testCallbackURL + "/account/oauth/corp/callback",
+		"scope":                 "openid email profile",
+		"state":                 "st",
+		"nonce":                 "no",
+		"code_challenge":        "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+		"code_challenge_method": "S256",
+	}
+	for key, value := range want {
+		if got := u.Query().Get(key); got != value {
+			t.Errorf("query parameter %s = %q, want %q", key, got, value)
+		}
+	}
+
+	if u.Path != "/authorize" {
+		t.Errorf("path = %q, want /authorize", u.Path)
+	}
+
+	if _, err := f.AuthorizationURL(context.Background(), "other", "st", "no", testVerifier); !errors.Is(err, ErrUnknownProvider) {
+		t.Errorf("unknown provider error = %v, want %v", err, ErrUnknownProvider)
+	}
+}
+
+func TestExchange(t *testing.T) {
+	idp := newFakeIdP(t)
+	f := newTestFederation(idp)
+
+	identity, err := login(t, idp, f, "st", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &Identity{
+		Provider:      "corp",
+		Subject:       "user-1",
+		Email:         "jane@example.com",
+		EmailVerified: true,
+		FirstName:     "Jane",
+		LastName:      "Doe",
+	}
+	if *identity != *want {
+		t.Errorf("identity = %+v, want %+v", identity, want)
+	}
+
+	if _, err := f.Exchange(context.Background(), "other", "code", testVerifier, "no"); !errors.Is(err, ErrUnknownProvider) {
+		t.Errorf("unknown provider error = %v, want %v", err, ErrUnknownProvider)
+	}
+
+	// authorization code is bound to code challenge
+	authURL, err := f.AuthorizationURL(context.Background(), "corp", "pkce", "no", testVerifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, nonce := idp.authorize(t, authURL)
+	if _, err := f.Exchange(context.Background(), "corp", code, "other-verifier", nonce); !errors.Is(err, ErrExchange) {
+		t.Errorf("wrong code verifier error = %v, want %v", err, ErrExchange)
+	}
+}
+
+func TestExchangeInvalidIDToken(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		nonce  string
+		modify func(idp *fakeIdP, claims jwtgo.MapClaims)
+	}{
+		{name: "bad nonce", nonce: "other-nonce"},
+		{name: "missing nonce", modify: func(idp *fakeIdP, claims jwtgo.MapClaims) {
+			delete(claims, "nonce")
+		}},
+		{name: "bad audience", modify: func(idp *fakeIdP, claims jwtgo.MapClaims) {
+			claims["aud"] = "client-2"
+		}},
+		{name: "audience list without client", modify: func(idp *fakeIdP, claims jwtgo.MapClaims) {
+			claims["aud"] = []string{"client-2", "client-3"}
+		}},
+		{name: "bad authorized party", modify: func(idp *fakeIdP, claims jwtgo.MapClaims) {
+			claims["aud"] = []string{testClientID, "client-2"}
+			claims["azp"] = "client-2"
+		}},
+		{name: "bad issuer", modify: func(idp *fakeIdP, claims jwtgo.MapClaims) {
+			claims["iss"] = "https://other.example.com"
+		}},
+		{name: "expired", modify: func(idp *fakeIdP, claims jwtgo.MapClaims) {
+			claims["exp"] = time.Now().Add(-time.Minute).Unix()
+		}},
+		{name: "missing expiration", modify: func(idp *fakeIdP, claims jwtgo.MapClaims) {
+			delete(claims, "exp")
+		}},
+		{name: "issued in future", modify: func(idp *fakeIdP, claims jwtgo.MapClaims) {
+			claims["iat"] = time.Now().Add(time.Minute).Unix()
+		}},
+		{name: "missing subject", modify: func(idp *fakeIdP, claims jwtgo.MapClaims) {
+			delete(claims, "sub")
+		}},
+		{name: "wrong algorithm for key", modify: func(idp *fakeIdP, claims jwtgo.MapClaims) {
+			// token is signed with RS256 under kid of cached EC key
+			idp.keys["k1"] = rsaKey
+			idp.signingAlg = jwtgo.SigningMethodRS256
+		}},
+		{name: "symmetric algorithm", modify: func(idp *fakeIdP, claims jwtgo.MapClaims) {
+			idp.signingAlg = jwtgo.SigningMethodHS256
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idp := newFakeIdP(t)
+			f := newTestFederation(idp)
+
+			// keys are loaded before token is changed, so keys published later are not used for verification
+			if _, err := login(t, idp, f, "warmup", ""); err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.modify != nil {
+				claims := idp.claims
+				idp.claims = func(nonce string) jwtgo.MapClaims {
+					c := claims(nonce)
+					tt.modify(idp, c)
+					return c
+				}
+			}
+
+			_, err := login(t, idp, f, "st", tt.nonce)
+			if !errors.Is(err, ErrInvalidIDToken) {
+				t.Errorf("error = %v, want %v", err, ErrInvalidIDToken)
+			}
+		})
+	}
+}
+
+func TestExchangeKeyRotation(t *testing.T) {
+	idp := newFakeIdP(t)
+	f := newTestFederation(idp)
+
+	if _, err := login(t, idp, f, "first", ""); err != nil {
+		t.Fatal(err)
+	}
+	if idp.jwksRequests != 1 {
+		t.Fatalf("jwks requests = %d, want 1", idp.jwksRequests)
+	}
+
+	// known key is served from cache
+	if _, err := login(t, idp, f, "cached", ""); err != nil {
+		t.Fatal(err)
+	}
+	if idp.jwksRequests != 1 {
+		t.Fatalf("jwks requests = %d, want 1", idp.jwksRequests)
+	}
+
+	// unknown kid shortly after reload is rejected without requesting keys again
+	idp.addKey(t, "k2")
+	if _, err := login(t, idp, f, "throttled", ""); !errors.Is(err, ErrInvalidIDToken) {
+		t.Errorf("error = %v, want %v", err, ErrInvalidIDToken)
+	}
+	if idp.jwksRequests != 1 {
+		t.Fatalf("jwks requests = %d, want 1", idp.jwksRequests)
+	}
+
+	// unknown kid after throttle period reloads keys
+	p := f.(*federation).providers["corp"]
+	p.mu.Lock()
+	p.reloadedAt = time.Now().Add(-keysReloadThrottle - time.Second)
+	p.mu.Unlock()
+
+	if _, err := login(t, idp, f, "rotated", ""); err != nil {
+		t.Fatal(err)
+	}
+	if idp.jwksRequests != 2 {
+		t.Fatalf("jwks requests = %d, want 2", idp.jwksRequests)
+	}
+}
+
+func TestDiscoveryIssuerMismatch(t *testing.T) {
+	idp := newFakeIdP(t)
+
+	providers := []*config.IdentityProvider{{
+		Name:     "corp",
+		Issuer:   idp.URL + "/",
+		ClientID: testClientID,
+		Scopes:   []string{"openid"},
+	}}
+	f := NewFederation(providers, testCallbackURL, time.Second, idp.Client(), log.New())
+
+	if _, err := f.AuthorizationURL(context.Background(), "corp", "st", "no", testVerifier); !errors.Is(err, ErrDiscovery) {
+		t.Errorf("error = %v, want %v", err, ErrDiscovery)
+	}
+}
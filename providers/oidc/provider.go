@@ -0,0 +1,291 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"api/providers/config"
+	"api/providers/jwt"
+
+	jwtgo "github.com/dgrijalva/jwt-go"
+)
+
+// keysReloadThrottle is minimal time between signing keys reloads caused by unknown kid
+const keysReloadThrottle = 10 * time.Second
+
+// maxResponseSize limits size of responses read from identity providers
+const maxResponseSize = 1 << 20
+
+// metadata holds identity provider metadata used by authorization code flow
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// signingKey holds public key of identity provider and signing method determined by key type
+type signingKey struct {
+	method    jwtgo.SigningMethod
+	publicKey crypto.PublicKey
+}
+
+// provider holds configuration of identity provider and its discovered metadata and signing keys
+type provider struct {
+	cfg         *config.IdentityProvider
+	redirectURL string
+
+	mu         sync.RWMutex
+	metadata   *metadata
+	keys       map[string]*signingKey
+	reloadedAt time.Time
+}
+
+// tokenResponse is response of identity provider token endpoint
+type tokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// codeChallenge returns S256 code challenge of given code verifier
+func codeChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// metadata returns metadata of given provider, it is retrieved from discovery endpoint on first use
+func (f *federation) metadata(ctx context.Context, p *provider) (*metadata, error) {
+	p.mu.RLock()
+	m := p.metadata
+	p.mu.RUnlock()
+
+	if m != nil {
+		return m, nil
+	}
+
+	m = new(metadata)
+	discoveryURL := strings.TrimSuffix(p.cfg.Issuer, "/") + "/.well-known/openid-configuration"
+	if err := f.getJSON(ctx, discoveryURL, m); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrDiscovery, err)
+	}
+
+	// issuer has to match configured one, so tokens of other issuers are never accepted
+	if m.Issuer != p.cfg.Issuer {
+		return nil, fmt.Errorf("%w: issuer `%s` does not match configured issuer", ErrDiscovery, m.Issuer)
+	}
+
+	if m.AuthorizationEndpoint == "" || m.TokenEndpoint == "" || m.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: incomplete provider metadata", ErrDiscovery)
+	}
+
+	p.mu.Lock()
+	p.metadata = m
+	p.mu.Unlock()
+
+	return m, nil
+}
+
+// reloadKeys retrieves signing keys of given provider. Keys of unsupported types are skipped
+func (f *federation) reloadKeys(ctx context.Context, p *provider, m *metadata) error {
+	jwks := new(jwt.JWKS)
+	if err := f.getJSON(ctx, m.JWKSURI, jwks); err != nil {
+		return fmt.Errorf("%w: %s", ErrDiscovery, err)
+	}
+
+	keys := make(map[string]*signingKey, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		publicKey, method, err := jwt.ParseJWK(jwk)
+		if err != nil {
+			f.logger.Debugf("identity provider `%s` key `%s` skipped: %s", p.cfg.Name, jwk.Kid, err)
+			continue
+		}
+		keys[jwk.Kid] = &signingKey{method: method, publicKey: publicKey}
+	}
+
+	p.mu.Lock()
+	p.keys = keys
+	p.reloadedAt = time.Now()
+	p.mu.Unlock()
+
+	return nil
+}
+
+// signingKey returns provider key with given kid. Unknown kid causes keys reload,
+// so tokens signed with keys rotated by provider are accepted
+func (f *federation) signingKey(ctx context.Context, p *provider, m *metadata, kid string) (*signingKey, error) {
+	p.mu.RLock()
+	key, ok := p.keys[kid]
+	reloadedAt := p.reloadedAt
+	p.mu.RUnlock()
+
+	if !ok && time.Since(reloadedAt) > keysReloadThrottle {
+		if err := f.reloadKeys(ctx, p, m); err != nil {
+			return nil, err
+		}
+
+		p.mu.RLock()
+		key, ok = p.keys[kid]
+		p.mu.RUnlock()
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key `%s`", kid)
+	}
+	return key, nil
+}
+
+// exchangeCode exchanges authorization code at provider token endpoint and returns issued ID token
+func (f *federation) exchangeCode(ctx context.Context, p *provider, m *metadata, code string, codeVerifier string) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.redirectURL)
+	form.Set("code_verifier", codeVerifier)
+	form.Set("client_id", p.cfg.ClientID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrExchange, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	// confidential clients authenticate with client_secret_basic, credentials are form encoded as required by RFC 6749
+	if p.cfg.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	}
+
+	res, err := f.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrExchange, err)
+	}
+	defer res.Body.Close()
+
+	tokens := new(tokenResponse)
+	if err := json.NewDecoder(io.LimitReader(res.Body, maxResponseSize)).Decode(tokens); err != nil {
+		return "", fmt.Errorf("%w: %s", ErrExchange, err)
+	}
+
+	if res.StatusCode != http.StatusOK || tokens.Error != "" {
+		return "", fmt.Errorf("%w: %s %s", ErrExchange, tokens.Error, tokens.ErrorDescription)
+	}
+
+	if tokens.IDToken == "" {
+		return "", fmt.Errorf("%w: id token not issued", ErrExchange)
+	}
+
+	return tokens.IDToken, nil
+}
+
+// verifyIDToken verifies signature and claims of given ID token and returns its claims.
+// Time claims are verified with configured clock skew tolerance
+func (f *federation) verifyIDToken(ctx context.Context, p *provider, m *metadata, idToken string, nonce string) (jwtgo.MapClaims, error) {
+	parser := &jwtgo.Parser{SkipClaimsValidation: true}
+
+	token, err := parser.Parse(idToken, func(token *jwtgo.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, err := f.signingKey(ctx, p, m, kid)
+		if err != nil {
+			return nil, err
+		}
+
+		if token.Method == nil || token.Method.Alg() != key.method.Alg() {
+			return nil, fmt.Errorf("invalid signing algorithm")
+		}
+		return key.publicKey, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidIDToken, err)
+	}
+
+	c, ok := token.Claims.(jwtgo.MapClaims)
+	if !ok || !token.Valid {
+		return nil, ErrInvalidIDToken
+	}
+
+	if !c.VerifyIssuer(p.cfg.Issuer, true) {
+		return nil, fmt.Errorf("%w: invalid issuer", ErrInvalidIDToken)
+	}
+
+	if !verifyAudience(c, p.cfg.ClientID) {
+		return nil, fmt.Errorf("%w: invalid audience", ErrInvalidIDToken)
+	}
+
+	// authorized party has to be this client when token is issued for multiple audiences
+	if azp, ok := c["azp"].(string); ok && azp != p.cfg.ClientID {
+		return nil, fmt.Errorf("%w: invalid authorized party", ErrInvalidIDToken)
+	}
+
+	now := time.Now().Unix()
+	skew := int64(f.clockSkew.Seconds())
+
+	if !c.VerifyExpiresAt(now-skew, true) {
+		return nil, fmt.Errorf("%w: token is expired", ErrInvalidIDToken)
+	}
+
+	if !c.VerifyIssuedAt(now+skew, false) {
+		return nil, fmt.Errorf("%w: token used before issued", ErrInvalidIDToken)
+	}
+
+	// nonce binds ID token to login started by this user agent
+	if tokenNonce, _ := c["nonce"].(string); tokenNonce == "" || tokenNonce != nonce {
+		return nil, fmt.Errorf("%w: invalid nonce", ErrInvalidIDToken)
+	}
+
+	if sub, _ := c["sub"].(string); sub == "" {
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidIDToken)
+	}
+
+	return c, nil
+}
+
+// getJSON retrieves JSON document from given URL
+func (f *federation) getJSON(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	res, err := f.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s", res.StatusCode, url)
+	}
+
+	return json.NewDecoder(io.LimitReader(res.Body, maxResponseSize)).Decode(v)
+}
+
+// verifyAudience checks that token `aud` claim, either single value or list, holds given audience
+func verifyAudience(claims jwtgo.MapClaims, audience string) bool {
+	switch aud := claims["aud"].(type) {
+	case string:
+		return aud == audience
+	case []interface{}:
+		for _, value := range aud {
+			if value == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
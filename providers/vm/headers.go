@@ -0,0 +1,47 @@
+package vm
+
+import (
+	"net/http"
+
+	"github.com/go-flow/flow/v2"
+)
+
+// WithHeaders adds given headers to response
+func WithHeaders(resp flow.Response, headers map[string]string) flow.Response {
+	return &headersResponse{
+		Response: resp,
+		headers:  headers,
+	}
+}
+
+type headersResponse struct {
+	flow.Response
+	headers map[string]string
+}
+
+func (r *headersResponse) Handle(w http.ResponseWriter, req *http.Request) error {
+	for k, v := range r.headers {
+		w.Header().Set(k, v)
+	}
+	return r.Response.Handle(w, req)
+}
+
+// WithCookies adds given cookies to response
+func WithCookies(resp flow.Response, cookies ...*http.Cookie) flow.Response {
+	return &cookiesResponse{
+		Response: resp,
+		cookies:  cookies,
+	}
+}
+
+type cookiesResponse struct {
+	flow.Response
+	cookies []*http.Cookie
+}
+
+func (r *cookiesResponse) Handle(w http.ResponseWriter, req *http.Request) error {
+	for _, c := range r.cookies {
+		http.SetCookie(w, c)
+	}
+	return r.Response.Handle(w, req)
+}
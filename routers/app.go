@@ -64,6 +64,8 @@ func (r *Router) ProvideHandlers() []flow.Provider {
 		flow.NewProvider(actions.NewIndexAction),
 		flow.NewProvider(actions.NewHealthAction),
 		flow.NewProvider(actions.NewSwaggerAction),
+		flow.NewProvider(actions.NewJWKSAction),
+		flow.NewProvider(actions.NewOpenIDConfigurationAction),
 	}
 }
 